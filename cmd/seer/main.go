@@ -9,9 +9,13 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/mx-seer/seer/internal/ai"
+	"github.com/mx-seer/seer/internal/alerts"
 	"github.com/mx-seer/seer/internal/api"
 	"github.com/mx-seer/seer/internal/config"
 	"github.com/mx-seer/seer/internal/db"
+	"github.com/mx-seer/seer/internal/enrich"
+	"github.com/mx-seer/seer/internal/schedule"
 	"github.com/mx-seer/seer/internal/sources"
 )
 
@@ -48,13 +52,68 @@ func main() {
 
 	// Initialize source manager
 	sourceManager := sources.NewManager(database.DB, cfg.Sources.FetchInterval)
+	if cfg.Sources.AllowExec {
+		sourceManager.RegisterFactory("exec", sources.NewExec)
+		log.Println("Exec sources enabled")
+	}
 	if err := sourceManager.Start(); err != nil {
 		log.Fatalf("Failed to start source manager: %v", err)
 	}
 	defer sourceManager.Stop()
 
+	// Load the key that encrypts stored AI provider credentials
+	secretKey, err := ai.LoadKey(cfg.SecretKey(), cfg.KeyFile())
+	if err != nil {
+		log.Fatalf("Failed to load secret key: %v", err)
+	}
+	cipher, err := ai.NewCipher(secretKey)
+	if err != nil {
+		log.Fatalf("Failed to initialize secret key: %v", err)
+	}
+
+	// Meter AI calls against the configured prices and monthly spend cap
+	prices := ai.Prices{}
+	for provider, models := range cfg.AI.Prices {
+		prices[provider] = make(map[string]ai.Price)
+		for model, price := range models {
+			prices[provider][model] = ai.Price{Input: price.Input, Output: price.Output}
+		}
+	}
+	meter := ai.NewMeter(database.DB, ai.DefaultPrices.Merge(prices), cfg.AI.MonthlyCap)
+	aiProviders := ai.NewStore(database.DB, cipher, meter)
+
+	// Enrich high-scoring opportunities with AI verdicts in the background
+	if cfg.AI.Enrichment.Enabled {
+		enrichment := enrich.NewWorker(database.DB, aiProviders, enrich.Options{
+			MinScore:    cfg.AI.Enrichment.MinScore,
+			Concurrency: cfg.AI.Enrichment.Concurrency,
+			DailyBudget: cfg.AI.Enrichment.DailyBudget,
+			Provider:    cfg.AI.Enrichment.Provider,
+			Interval:    cfg.AI.Enrichment.Interval,
+		})
+		if err := enrichment.Start(); err != nil {
+			log.Fatalf("Failed to start AI enrichment: %v", err)
+		}
+		defer enrichment.Stop()
+	}
+
+	// Deliver alerts and scheduled reports
+	alertService := alerts.NewAlertService(database.DB)
+	alertService.UseSMTP(alerts.SMTPConfig{
+		Host:     cfg.Alerts.SMTP.Host,
+		Port:     cfg.Alerts.SMTP.Port,
+		Username: cfg.Alerts.SMTP.Username,
+		Password: cfg.Alerts.SMTP.Password,
+		From:     cfg.Alerts.SMTP.From,
+	})
+	scheduler := schedule.NewScheduler(database.DB, aiProviders, alertService, sourceManager.Cron(), cfg.PublicURL())
+	if err := scheduler.Start(); err != nil {
+		log.Fatalf("Failed to start report scheduler: %v", err)
+	}
+	defer scheduler.Stop()
+
 	// Create API server
-	server := api.NewServer(database, sourceManager)
+	server := api.NewServer(database, sourceManager, aiProviders, alertService, scheduler)
 
 	// Start HTTP server
 	go func() {
@@ -1,11 +1,9 @@
 package ai
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"time"
 )
@@ -23,9 +21,17 @@ type Anthropic struct {
 }
 
 type anthropicRequest struct {
-	Model     string    `json:"model"`
-	MaxTokens int       `json:"max_tokens"`
-	Messages  []Message `json:"messages"`
+	Model       string    `json:"model"`
+	MaxTokens   int       `json:"max_tokens"`
+	System      string    `json:"system,omitempty"`
+	Messages    []Message `json:"messages"`
+	Temperature *float64  `json:"temperature,omitempty"`
+	Stream      bool      `json:"stream,omitempty"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
 }
 
 type anthropicResponse struct {
@@ -33,6 +39,25 @@ type anthropicResponse struct {
 		Type string `json:"type"`
 		Text string `json:"text"`
 	} `json:"content"`
+	Usage anthropicUsage `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// anthropicEvent is a server-sent event of a streamed reply
+type anthropicEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	// Message is sent with message_start and carries the prompt token count
+	Message struct {
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"`
+	// Usage is sent with message_delta and carries the running output token count
+	Usage anthropicUsage `json:"usage"`
 	Error *struct {
 		Message string `json:"message"`
 	} `json:"error,omitempty"`
@@ -68,55 +93,121 @@ func (a *Anthropic) Name() string {
 	return "anthropic"
 }
 
+func (a *Anthropic) Model() string {
+	return a.model
+}
+
 func (a *Anthropic) Available() bool {
 	return a.apiKey != ""
 }
 
 func (a *Anthropic) Analyze(ctx context.Context, prompt string) (string, error) {
-	reqBody := anthropicRequest{
-		Model:     a.model,
-		MaxTokens: 4096,
-		Messages: []Message{
-			{Role: "user", Content: prompt},
-		},
-	}
-
-	body, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+"/messages", bytes.NewReader(body))
+	resp, err := a.Chat(ctx, Prompt(prompt))
 	if err != nil {
 		return "", err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", a.apiKey)
-	req.Header.Set("anthropic-version", "2023-06-01")
+	return resp.Content, nil
+}
 
-	resp, err := a.client.Do(req)
+func (a *Anthropic) Chat(ctx context.Context, req ChatRequest) (*Response, error) {
+	resp, err := postJSON(ctx, a.client, "anthropic", a.baseURL+"/messages", a.headers(), a.request(req, false))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("anthropic returned status %d: %s", resp.StatusCode, string(respBody))
-	}
-
 	var anthropicResp anthropicResponse
 	if err := json.NewDecoder(resp.Body).Decode(&anthropicResp); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if anthropicResp.Error != nil {
-		return "", fmt.Errorf("anthropic error: %s", anthropicResp.Error.Message)
+		return nil, fmt.Errorf("anthropic error: %s", anthropicResp.Error.Message)
 	}
 
 	if len(anthropicResp.Content) == 0 {
-		return "", fmt.Errorf("anthropic returned no content")
+		return nil, fmt.Errorf("anthropic returned no content")
 	}
 
-	return anthropicResp.Content[0].Text, nil
+	return &Response{
+		Content: anthropicResp.Content[0].Text,
+		Usage: Usage{
+			Model:            a.model,
+			PromptTokens:     anthropicResp.Usage.InputTokens,
+			CompletionTokens: anthropicResp.Usage.OutputTokens,
+		},
+	}, nil
+}
+
+func (a *Anthropic) Stream(ctx context.Context, req ChatRequest) (<-chan Chunk, error) {
+	resp, err := postJSON(ctx, streamClient, "anthropic", a.baseURL+"/messages", a.headers(), a.request(req, true))
+	if err != nil {
+		return nil, err
+	}
+
+	return streamBody(ctx, resp.Body, func(emit func(string) bool) (Usage, error) {
+		usage := Usage{Model: a.model}
+		err := readSSE(resp.Body, func(_, data string) (bool, error) {
+			var event anthropicEvent
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				return false, fmt.Errorf("anthropic returned an invalid stream event: %w", err)
+			}
+
+			switch event.Type {
+			case "message_start":
+				usage.PromptTokens = event.Message.Usage.InputTokens
+			case "message_delta":
+				usage.CompletionTokens = event.Usage.OutputTokens
+			case "content_block_delta":
+				if event.Delta.Type == "text_delta" {
+					return emit(event.Delta.Text), nil
+				}
+			case "message_stop":
+				return false, nil
+			case "error":
+				if event.Error != nil {
+					return false, fmt.Errorf("anthropic error: %s", event.Error.Message)
+				}
+				return false, fmt.Errorf("anthropic stream failed")
+			}
+			return true, nil
+		})
+		return usage, err
+	}), nil
+}
+
+func (a *Anthropic) Models(ctx context.Context) ([]Model, error) {
+	var list struct {
+		Data []struct {
+			ID          string `json:"id"`
+			DisplayName string `json:"display_name"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, a.client, "anthropic", a.baseURL+"/models?limit=1000", a.headers(), &list); err != nil {
+		return nil, err
+	}
+
+	models := make([]Model, 0, len(list.Data))
+	for _, m := range list.Data {
+		models = append(models, Model{ID: m.ID, Name: m.DisplayName})
+	}
+	return sortModels(models), nil
+}
+
+func (a *Anthropic) request(req ChatRequest, stream bool) anthropicRequest {
+	return anthropicRequest{
+		Model:       a.model,
+		MaxTokens:   req.maxTokens(),
+		System:      req.System,
+		Messages:    req.Messages,
+		Temperature: req.Temperature,
+		Stream:      stream,
+	}
+}
+
+func (a *Anthropic) headers() map[string]string {
+	return map[string]string{
+		"x-api-key":         a.apiKey,
+		"anthropic-version": "2023-06-01",
+	}
 }
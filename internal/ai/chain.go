@@ -0,0 +1,173 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy configures how a Chain retries a provider before falling back to the next
+type RetryPolicy struct {
+	// MaxAttempts is the number of calls made to each provider
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry; it doubles with each retry
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff between retries
+	MaxDelay time.Duration
+	// MaxRetryAfter is the longest Retry-After the chain waits for; a provider asking
+	// for longer is skipped in favour of the next one
+	MaxRetryAfter time.Duration
+}
+
+// DefaultRetryPolicy is used by chains built from stored provider settings
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:   3,
+	BaseDelay:     time.Second,
+	MaxDelay:      15 * time.Second,
+	MaxRetryAfter: time.Minute,
+}
+
+// Chain is a Provider that tries an ordered list of providers. Retryable failures are
+// retried with jittered exponential backoff, or after the delay the provider asked
+// for; any other failure moves on to the next provider. The Usage of a reply names
+// the provider that answered.
+type Chain struct {
+	providers []Provider
+	policy    RetryPolicy
+	sleep     func(ctx context.Context, d time.Duration) error
+}
+
+// NewChain creates a provider that tries providers in order
+func NewChain(policy RetryPolicy, providers ...Provider) *Chain {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+	return &Chain{providers: providers, policy: policy, sleep: sleep}
+}
+
+// Name returns the name of the first provider in the chain
+func (c *Chain) Name() string {
+	if len(c.providers) == 0 {
+		return ""
+	}
+	return c.providers[0].Name()
+}
+
+// Available reports whether any provider in the chain is available
+func (c *Chain) Available() bool {
+	for _, p := range c.providers {
+		if p.Available() {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Chain) Analyze(ctx context.Context, prompt string) (string, error) {
+	resp, err := c.Chat(ctx, Prompt(prompt))
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
+
+func (c *Chain) Chat(ctx context.Context, req ChatRequest) (*Response, error) {
+	var resp *Response
+	err := c.try(ctx, func(p Provider) error {
+		var err error
+		resp, err = p.Chat(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// Stream falls back only while opening the stream; once a provider has started
+// replying, a failure is passed on to the caller
+func (c *Chain) Stream(ctx context.Context, req ChatRequest) (<-chan Chunk, error) {
+	var chunks <-chan Chunk
+	err := c.try(ctx, func(p Provider) error {
+		var err error
+		chunks, err = p.Stream(ctx, req)
+		return err
+	})
+	return chunks, err
+}
+
+// try calls fn with each provider in turn until one succeeds
+func (c *Chain) try(ctx context.Context, fn func(p Provider) error) error {
+	var errs []error
+
+	for i, p := range c.providers {
+		for attempt := 1; ; attempt++ {
+			err := fn(p)
+			if err == nil {
+				if i > 0 || attempt > 1 {
+					log.Printf("AI call answered by %s after %d failed attempts", p.Name(), len(errs))
+				}
+				return nil
+			}
+
+			// Neither a cancelled caller nor the spend cap is helped by another provider
+			if ctx.Err() != nil || errors.Is(err, ErrBudgetExceeded) {
+				return err
+			}
+			errs = append(errs, err)
+
+			delay, retry := c.retryDelay(err, attempt)
+			if !retry {
+				break
+			}
+			if err := c.sleep(ctx, delay); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(c.providers) == 1 {
+		return errs[len(errs)-1]
+	}
+	return fmt.Errorf("all AI providers failed: %w", errors.Join(errs...))
+}
+
+// retryDelay returns how long to wait before calling the same provider again, and
+// false if it should not be called again
+func (c *Chain) retryDelay(err error, attempt int) (time.Duration, bool) {
+	var e *Error
+	if !errors.As(err, &e) || e.Kind != ErrorRetryable || attempt >= c.policy.MaxAttempts {
+		return 0, false
+	}
+
+	if e.RetryAfter > 0 {
+		return e.RetryAfter, e.RetryAfter <= c.policy.MaxRetryAfter
+	}
+
+	// Full jitter spreads out retries from concurrent callers
+	backoff := c.policy.BaseDelay << (attempt - 1)
+	if backoff <= 0 || backoff > c.policy.MaxDelay {
+		backoff = c.policy.MaxDelay
+	}
+	if backoff <= 0 {
+		return 0, true
+	}
+	return time.Duration(rand.Int63n(int64(backoff))), true
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
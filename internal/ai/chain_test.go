@@ -0,0 +1,235 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mx-seer/seer/internal/db"
+)
+
+// fakeProvider answers from a list of canned errors, then succeeds
+type fakeProvider struct {
+	name  string
+	errs  []error
+	calls int
+}
+
+func (p *fakeProvider) Name() string    { return p.name }
+func (p *fakeProvider) Available() bool { return true }
+
+func (p *fakeProvider) Analyze(ctx context.Context, prompt string) (string, error) {
+	resp, err := p.Chat(ctx, Prompt(prompt))
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
+
+func (p *fakeProvider) Chat(ctx context.Context, req ChatRequest) (*Response, error) {
+	p.calls++
+	if p.calls <= len(p.errs) {
+		return nil, p.errs[p.calls-1]
+	}
+	return &Response{Content: "reply from " + p.name, Usage: Usage{Model: "m", PromptTokens: 1000, CompletionTokens: 500}}, nil
+}
+
+func (p *fakeProvider) Stream(ctx context.Context, req ChatRequest) (<-chan Chunk, error) {
+	resp, err := p.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan Chunk, 2)
+	out <- Chunk{Content: resp.Content}
+	out <- Chunk{Usage: &resp.Usage}
+	close(out)
+	return out, nil
+}
+
+// recordSleeps makes a chain record its waits instead of sleeping
+func recordSleeps(c *Chain) *[]time.Duration {
+	var sleeps []time.Duration
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return ctx.Err()
+	}
+	return &sleeps
+}
+
+var testPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: 4 * time.Second, MaxRetryAfter: time.Minute}
+
+func retryable() error { return &Error{Provider: "fake", Kind: ErrorRetryable, StatusCode: 503} }
+
+func TestChain_RetriesWithBackoff(t *testing.T) {
+	p := &fakeProvider{name: "primary", errs: []error{retryable(), retryable()}}
+	chain := NewChain(testPolicy, p)
+	sleeps := recordSleeps(chain)
+
+	resp, err := chain.Chat(context.Background(), Prompt("hi"))
+	if err != nil || resp.Content != "reply from primary" {
+		t.Fatalf("expected the third attempt to answer, got %v, %v", resp, err)
+	}
+	if p.calls != 3 || len(*sleeps) != 2 {
+		t.Fatalf("expected 3 calls and 2 waits, got %d and %v", p.calls, *sleeps)
+	}
+	// Full jitter waits up to the doubling backoff
+	for i, max := range []time.Duration{time.Second, 2 * time.Second} {
+		if d := (*sleeps)[i]; d < 0 || d >= max {
+			t.Errorf("wait %d: expected below %s, got %s", i+1, max, d)
+		}
+	}
+}
+
+func TestChain_HonoursRetryAfter(t *testing.T) {
+	p := &fakeProvider{name: "primary", errs: []error{&Error{Kind: ErrorRetryable, StatusCode: 429, RetryAfter: 7 * time.Second}}}
+	chain := NewChain(testPolicy, p)
+	sleeps := recordSleeps(chain)
+
+	if _, err := chain.Chat(context.Background(), Prompt("hi")); err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if len(*sleeps) != 1 || (*sleeps)[0] != 7*time.Second {
+		t.Errorf("expected to wait the requested 7s, got %v", *sleeps)
+	}
+
+	// A provider asking for longer than the policy allows is skipped for the next
+	slow := &fakeProvider{name: "slow", errs: []error{&Error{Kind: ErrorRetryable, StatusCode: 429, RetryAfter: time.Hour}}}
+	next := &fakeProvider{name: "next"}
+	chain = NewChain(testPolicy, slow, next)
+	sleeps = recordSleeps(chain)
+
+	resp, err := chain.Chat(context.Background(), Prompt("hi"))
+	if err != nil || resp.Content != "reply from next" || slow.calls != 1 || len(*sleeps) != 0 {
+		t.Errorf("expected to fall back without waiting, got %v, %v, %d calls, waits %v", resp, err, slow.calls, *sleeps)
+	}
+}
+
+func TestChain_FallbackOrder(t *testing.T) {
+	auth := &fakeProvider{name: "first", errs: []error{&Error{Kind: ErrorAuth, StatusCode: 401}}}
+	flaky := &fakeProvider{name: "second", errs: []error{retryable(), retryable(), retryable()}}
+	last := &fakeProvider{name: "third"}
+	chain := NewChain(testPolicy, auth, flaky, last)
+	recordSleeps(chain)
+
+	resp, err := chain.Chat(context.Background(), Prompt("hi"))
+	if err != nil || resp.Content != "reply from third" {
+		t.Fatalf("expected the third provider to answer, got %v, %v", resp, err)
+	}
+	// Auth failures are not retried; retryable ones are, up to MaxAttempts
+	if auth.calls != 1 || flaky.calls != 3 || last.calls != 1 {
+		t.Errorf("unexpected calls %d, %d, %d", auth.calls, flaky.calls, last.calls)
+	}
+
+	failing := NewChain(testPolicy, &fakeProvider{name: "a", errs: []error{&Error{Kind: ErrorInvalid}}}, &fakeProvider{name: "b", errs: []error{&Error{Kind: ErrorQuota}}})
+	if _, err := failing.Chat(context.Background(), Prompt("hi")); err == nil || !strings.Contains(err.Error(), "all AI providers failed") {
+		t.Errorf("expected every failure to be reported, got %v", err)
+	}
+}
+
+func TestChain_StopsOnCancelAndBudget(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &fakeProvider{name: "primary", errs: []error{retryable()}}
+	chain := NewChain(testPolicy, p, &fakeProvider{name: "next"})
+	chain.sleep = func(ctx context.Context, d time.Duration) error {
+		cancel()
+		return ctx.Err()
+	}
+	if _, err := chain.Chat(ctx, Prompt("hi")); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancellation to stop the chain, got %v", err)
+	}
+
+	capped := &fakeProvider{name: "primary", errs: []error{ErrBudgetExceeded}}
+	next := &fakeProvider{name: "next"}
+	if _, err := NewChain(testPolicy, capped, next).Chat(context.Background(), Prompt("hi")); !errors.Is(err, ErrBudgetExceeded) || next.calls != 0 {
+		t.Errorf("expected the spend cap to stop the chain, got %v after %d fallback calls", err, next.calls)
+	}
+}
+
+func TestMeter_MonthlyCap(t *testing.T) {
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	defer database.Close()
+
+	prices := Prices{"fake": {"m": {Input: 1, Output: 2}}}
+	meter := NewMeter(database.DB, prices, 0.002)
+	now := time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC)
+	meter.now = func() time.Time { return now }
+
+	p := &fakeProvider{name: "fake"}
+	provider := meter.Wrap(p)
+
+	// 1000 prompt and 500 completion tokens cost 0.001 + 0.001
+	if _, err := provider.Chat(context.Background(), Prompt("hi")); err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if spend, _ := meter.MonthSpend(); spend != 0.002 {
+		t.Errorf("expected spend of 0.002, got %v", spend)
+	}
+
+	if _, err := provider.Chat(context.Background(), Prompt("hi")); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("expected the cap to short-circuit the call, got %v", err)
+	}
+	if p.calls != 1 {
+		t.Errorf("expected the provider not to be called over the cap, got %d calls", p.calls)
+	}
+
+	// A new month starts from zero
+	now = now.Add(2 * time.Hour)
+	if _, err := provider.Chat(context.Background(), Prompt("hi")); err != nil {
+		t.Errorf("expected calls to resume in a new month, got %v", err)
+	}
+}
+
+// brokenStream streams part of a reply and then fails, without reporting usage
+type brokenStream struct {
+	fakeProvider
+}
+
+func (p *brokenStream) Model() string { return "m" }
+
+func (p *brokenStream) Stream(ctx context.Context, req ChatRequest) (<-chan Chunk, error) {
+	out := make(chan Chunk, 2)
+	out <- Chunk{Content: strings.Repeat("x", 400)}
+	out <- Chunk{Err: errors.New("connection reset")}
+	close(out)
+	return out, nil
+}
+
+func TestMeter_EstimatesCutShortStreams(t *testing.T) {
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	defer database.Close()
+
+	meter := NewMeter(database.DB, Prices{"fake": {"m": {Input: 1, Output: 2}}}, 0)
+	provider := meter.Wrap(&brokenStream{fakeProvider{name: "fake"}})
+
+	chunks, err := provider.Stream(context.Background(), Prompt(strings.Repeat("y", 4000)))
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	if _, err := Collect(chunks); err == nil {
+		t.Fatal("expected the stream to fail")
+	}
+	for range chunks {
+		// Usage is recorded before the stream is closed
+	}
+
+	var model string
+	var prompt, completion int
+	if err := database.QueryRow(`SELECT model, prompt_tokens, completion_tokens FROM ai_usage`).Scan(&model, &prompt, &completion); err != nil {
+		t.Fatalf("expected the failed stream to be recorded: %v", err)
+	}
+	if model != "m" || prompt != 1000 || completion != 100 {
+		t.Errorf("expected an estimate of 1000 and 100 tokens of model m, got %d and %d of %q", prompt, completion, model)
+	}
+	if spend, _ := meter.MonthSpend(); spend != 0.0012 {
+		t.Errorf("expected the estimate to count towards the cap, got %v", spend)
+	}
+}
@@ -0,0 +1,112 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrorKind classifies a failed provider call by what the caller can do about it
+type ErrorKind string
+
+const (
+	// ErrorRetryable covers rate limits, timeouts, network failures and server
+	// errors, which may succeed when tried again
+	ErrorRetryable ErrorKind = "retryable"
+	// ErrorAuth means the API key was rejected
+	ErrorAuth ErrorKind = "auth"
+	// ErrorQuota means the account is out of credit or quota
+	ErrorQuota ErrorKind = "quota"
+	// ErrorInvalid means the provider rejected the request itself
+	ErrorInvalid ErrorKind = "invalid"
+)
+
+// Error is a failed provider call
+type Error struct {
+	Provider   string
+	Kind       ErrorKind
+	StatusCode int
+	// RetryAfter is how long the provider asked to wait before retrying, if it did
+	RetryAfter time.Duration
+	Message    string
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s returned status %d: %s", e.Provider, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("%s request failed: %v", e.Provider, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// KindOf returns the kind of a provider error, or an empty kind for other errors
+func KindOf(err error) ErrorKind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return ""
+}
+
+// quotaMarkers identify 429 responses caused by an exhausted balance rather than a rate limit
+var quotaMarkers = []string{"insufficient_quota", "billing", "credit balance", "out of credits"}
+
+// statusError classifies a non-200 provider response
+func statusError(provider string, resp *http.Response, body []byte) *Error {
+	e := &Error{
+		Provider:   provider,
+		StatusCode: resp.StatusCode,
+		Message:    string(body),
+		RetryAfter: retryAfter(resp.Header, time.Now()),
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		e.Kind = ErrorAuth
+	case resp.StatusCode == http.StatusPaymentRequired:
+		e.Kind = ErrorQuota
+	case resp.StatusCode == http.StatusTooManyRequests:
+		e.Kind = ErrorRetryable
+		lower := strings.ToLower(e.Message)
+		for _, marker := range quotaMarkers {
+			if strings.Contains(lower, marker) {
+				e.Kind = ErrorQuota
+				break
+			}
+		}
+	case resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode >= 500:
+		e.Kind = ErrorRetryable
+	default:
+		e.Kind = ErrorInvalid
+	}
+
+	return e
+}
+
+// retryAfter reads the Retry-After header, in seconds or as an HTTP date, falling back
+// to the retry-after-ms header some APIs send instead
+func retryAfter(h http.Header, now time.Time) time.Duration {
+	if v := h.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.ParseFloat(v, 64); err == nil && seconds >= 0 {
+			return time.Duration(seconds * float64(time.Second))
+		}
+		if at, err := http.ParseTime(v); err == nil && at.After(now) {
+			return at.Sub(now)
+		}
+	}
+
+	if v := h.Get("retry-after-ms"); v != "" {
+		if ms, err := strconv.ParseFloat(v, 64); err == nil && ms >= 0 {
+			return time.Duration(ms * float64(time.Millisecond))
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,60 @@
+package ai
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   ErrorKind
+	}{
+		{"unauthorized", http.StatusUnauthorized, "", ErrorAuth},
+		{"forbidden", http.StatusForbidden, "", ErrorAuth},
+		{"payment required", http.StatusPaymentRequired, "", ErrorQuota},
+		{"rate limit", http.StatusTooManyRequests, `{"error": "rate limited"}`, ErrorRetryable},
+		{"exhausted quota", http.StatusTooManyRequests, `{"error": {"code": "insufficient_quota"}}`, ErrorQuota},
+		{"timeout", http.StatusRequestTimeout, "", ErrorRetryable},
+		{"server error", http.StatusBadGateway, "", ErrorRetryable},
+		{"bad request", http.StatusBadRequest, "", ErrorInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			if got := statusError("fake", resp, []byte(tt.body)); got.Kind != tt.want || got.StatusCode != tt.status {
+				t.Errorf("expected %s for status %d, got %+v", tt.want, tt.status, got)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{"seconds", http.Header{"Retry-After": {"12"}}, 12 * time.Second},
+		{"fractional seconds", http.Header{"Retry-After": {"1.5"}}, 1500 * time.Millisecond},
+		{"http date", http.Header{"Retry-After": {now.Add(30 * time.Second).Format(http.TimeFormat)}}, 30 * time.Second},
+		{"past date", http.Header{"Retry-After": {now.Add(-time.Minute).Format(http.TimeFormat)}}, 0},
+		{"milliseconds", http.Header{"Retry-After-Ms": {"250"}}, 250 * time.Millisecond},
+		{"garbage", http.Header{"Retry-After": {"soon"}}, 0},
+		{"missing", http.Header{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryAfter(tt.header, now); got != tt.want {
+				t.Errorf("retryAfter() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,12 +1,11 @@
 package ai
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -22,27 +21,63 @@ type Google struct {
 	client  *http.Client
 }
 
+type googlePart struct {
+	Text string `json:"text"`
+}
+
+type googleContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []googlePart `json:"parts"`
+}
+
+type googleGenerationConfig struct {
+	Temperature     *float64 `json:"temperature,omitempty"`
+	MaxOutputTokens int      `json:"maxOutputTokens,omitempty"`
+}
+
 type googleRequest struct {
-	Contents []struct {
-		Parts []struct {
-			Text string `json:"text"`
-		} `json:"parts"`
-	} `json:"contents"`
+	SystemInstruction *googleContent         `json:"systemInstruction,omitempty"`
+	Contents          []googleContent        `json:"contents"`
+	GenerationConfig  googleGenerationConfig `json:"generationConfig"`
 }
 
 type googleResponse struct {
 	Candidates []struct {
-		Content struct {
-			Parts []struct {
-				Text string `json:"text"`
-			} `json:"parts"`
-		} `json:"content"`
+		Content googleContent `json:"content"`
 	} `json:"candidates"`
+	UsageMetadata *struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata,omitempty"`
 	Error *struct {
 		Message string `json:"message"`
 	} `json:"error,omitempty"`
 }
 
+// text returns the text of the first candidate
+func (r *googleResponse) text() string {
+	if len(r.Candidates) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, part := range r.Candidates[0].Content.Parts {
+		sb.WriteString(part.Text)
+	}
+	return sb.String()
+}
+
+// usage returns the token counts of the response; streamed responses repeat the
+// running totals in every chunk
+func (r *googleResponse) usage(model string) Usage {
+	usage := Usage{Model: model}
+	if r.UsageMetadata != nil {
+		usage.PromptTokens = r.UsageMetadata.PromptTokenCount
+		usage.CompletionTokens = r.UsageMetadata.CandidatesTokenCount
+	}
+	return usage
+}
+
 // NewGoogle creates a new Google AI provider
 func NewGoogle(cfg ProviderConfig) (Provider, error) {
 	if cfg.APIKey == "" {
@@ -73,62 +108,126 @@ func (g *Google) Name() string {
 	return "google"
 }
 
+func (g *Google) Model() string {
+	return g.model
+}
+
 func (g *Google) Available() bool {
 	return g.apiKey != ""
 }
 
 func (g *Google) Analyze(ctx context.Context, prompt string) (string, error) {
-	reqBody := googleRequest{
-		Contents: []struct {
-			Parts []struct {
-				Text string `json:"text"`
-			} `json:"parts"`
-		}{
-			{
-				Parts: []struct {
-					Text string `json:"text"`
-				}{
-					{Text: prompt},
-				},
-			},
-		},
-	}
-
-	body, err := json.Marshal(reqBody)
+	resp, err := g.Chat(ctx, Prompt(prompt))
 	if err != nil {
 		return "", err
 	}
+	return resp.Content, nil
+}
 
-	url := fmt.Sprintf("%s/models/%s:generateContent?key=%s", g.baseURL, g.model, g.apiKey)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := g.client.Do(req)
+func (g *Google) Chat(ctx context.Context, req ChatRequest) (*Response, error) {
+	url := fmt.Sprintf("%s/models/%s:generateContent", g.baseURL, g.model)
+	resp, err := postJSON(ctx, g.client, "google", url, g.headers(), g.request(req))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("google returned status %d: %s", resp.StatusCode, string(respBody))
-	}
-
 	var googleResp googleResponse
 	if err := json.NewDecoder(resp.Body).Decode(&googleResp); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if googleResp.Error != nil {
-		return "", fmt.Errorf("google error: %s", googleResp.Error.Message)
+		return nil, fmt.Errorf("google error: %s", googleResp.Error.Message)
 	}
 
 	if len(googleResp.Candidates) == 0 || len(googleResp.Candidates[0].Content.Parts) == 0 {
-		return "", fmt.Errorf("google returned no content")
+		return nil, fmt.Errorf("google returned no content")
+	}
+
+	return &Response{Content: googleResp.text(), Usage: googleResp.usage(g.model)}, nil
+}
+
+func (g *Google) Stream(ctx context.Context, req ChatRequest) (<-chan Chunk, error) {
+	url := fmt.Sprintf("%s/models/%s:streamGenerateContent?alt=sse", g.baseURL, g.model)
+	resp, err := postJSON(ctx, streamClient, "google", url, g.headers(), g.request(req))
+	if err != nil {
+		return nil, err
+	}
+
+	return streamBody(ctx, resp.Body, func(emit func(string) bool) (Usage, error) {
+		usage := Usage{Model: g.model}
+		err := readSSE(resp.Body, func(_, data string) (bool, error) {
+			var chunk googleResponse
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				return false, fmt.Errorf("google returned an invalid stream event: %w", err)
+			}
+			if chunk.Error != nil {
+				return false, fmt.Errorf("google error: %s", chunk.Error.Message)
+			}
+			if chunk.UsageMetadata != nil {
+				usage = chunk.usage(g.model)
+			}
+			return emit(chunk.text()), nil
+		})
+		return usage, err
+	}), nil
+}
+
+// Models lists the models that can generate content
+func (g *Google) Models(ctx context.Context) ([]Model, error) {
+	var list struct {
+		Models []struct {
+			Name                       string   `json:"name"`
+			DisplayName                string   `json:"displayName"`
+			SupportedGenerationMethods []string `json:"supportedGenerationMethods"`
+		} `json:"models"`
+	}
+	url := fmt.Sprintf("%s/models?pageSize=1000", g.baseURL)
+	if err := getJSON(ctx, g.client, "google", url, g.headers(), &list); err != nil {
+		return nil, err
+	}
+
+	models := []Model{}
+	for _, m := range list.Models {
+		for _, method := range m.SupportedGenerationMethods {
+			if method == "generateContent" {
+				models = append(models, Model{ID: strings.TrimPrefix(m.Name, "models/"), Name: m.DisplayName})
+				break
+			}
+		}
+	}
+	return sortModels(models), nil
+}
+
+// headers carries the API key, which would otherwise end up in logged and reported URLs
+func (g *Google) headers() map[string]string {
+	return map[string]string{"x-goog-api-key": g.apiKey}
+}
+
+func (g *Google) request(req ChatRequest) googleRequest {
+	googleReq := googleRequest{
+		GenerationConfig: googleGenerationConfig{
+			Temperature:     req.Temperature,
+			MaxOutputTokens: req.maxTokens(),
+		},
+	}
+
+	if req.System != "" {
+		googleReq.SystemInstruction = &googleContent{Parts: []googlePart{{Text: req.System}}}
+	}
+
+	// Gemini calls the assistant role "model"
+	for _, m := range req.Messages {
+		role := m.Role
+		if role == "assistant" {
+			role = "model"
+		}
+		googleReq.Contents = append(googleReq.Contents, googleContent{
+			Role:  role,
+			Parts: []googlePart{{Text: m.Content}},
+		})
 	}
 
-	return googleResp.Candidates[0].Content.Parts[0].Text, nil
+	return googleReq
 }
@@ -0,0 +1,38 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGoogle_KeyInHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") != "" {
+			t.Errorf("expected no key in the URL, got %s", r.URL.RawQuery)
+		}
+		if r.Header.Get("x-goog-api-key") != "g-secret" {
+			t.Errorf("expected key in the x-goog-api-key header, got %q", r.Header.Get("x-goog-api-key"))
+		}
+		w.Write([]byte(`{"models": [{"name": "models/gemini-pro", "displayName": "Gemini Pro", "supportedGenerationMethods": ["generateContent"]}]}`))
+	}))
+
+	provider, _ := NewGoogle(ProviderConfig{APIKey: "g-secret", BaseURL: server.URL})
+	models, err := provider.(ModelLister).Models(context.Background())
+	if err != nil || len(models) != 1 || models[0].ID != "gemini-pro" {
+		t.Fatalf("unexpected models %v, %v", models, err)
+	}
+
+	// A network failure must not report the URL the request went to
+	server.Close()
+	provider, _ = NewGoogle(ProviderConfig{APIKey: "g-secret", BaseURL: server.URL + "/v1?key=g-secret"})
+	_, err = provider.(ModelLister).Models(context.Background())
+	if err == nil || KindOf(err) != ErrorRetryable {
+		t.Fatalf("expected a retryable error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "g-secret") || strings.Contains(err.Error(), server.URL) {
+		t.Errorf("expected the URL to be left out of the error, got %q", err.Error())
+	}
+}
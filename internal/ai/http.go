@@ -0,0 +1,183 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// streamClient is used for streamed replies, which may legitimately take longer than
+// a client-wide timeout allows; only waiting for the first response byte is bounded
+var streamClient = &http.Client{
+	Transport: func() http.RoundTripper {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.ResponseHeaderTimeout = 60 * time.Second
+		return t
+	}(),
+}
+
+// postJSON sends a JSON request and returns the response, or an *Error classifying
+// the failure, including the body of any non-200 response
+func postJSON(ctx context.Context, client *http.Client, name, url string, headers map[string]string, payload any) (*http.Response, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// A cancelled caller is not a provider failure
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, &Error{Provider: name, Kind: ErrorRetryable, Err: transportError(err)}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return nil, statusError(name, resp, respBody)
+	}
+
+	return resp, nil
+}
+
+// transportError drops the request URL from a failed request's error, since URLs
+// may carry credentials and provider errors are shown to API callers
+func transportError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s: %w", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
+
+// getJSON sends a GET request and decodes the JSON response into out
+func getJSON(ctx context.Context, client *http.Client, name, url string, headers map[string]string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &Error{Provider: name, Kind: ErrorRetryable, Err: transportError(err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return statusError(name, resp, respBody)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// streamBody runs read in the background and forwards the text it emits as chunks,
+// followed by the usage read returns. emit reports false once the consumer has gone
+// away, and read should stop.
+func streamBody(ctx context.Context, body io.ReadCloser, read func(emit func(string) bool) (Usage, error)) <-chan Chunk {
+	chunks := make(chan Chunk)
+
+	go func() {
+		defer close(chunks)
+		defer body.Close()
+
+		emit := func(text string) bool {
+			if text == "" {
+				return true
+			}
+			select {
+			case chunks <- Chunk{Content: text}:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		usage, err := read(emit)
+		last := Chunk{Usage: &usage}
+		if err != nil {
+			last = Chunk{Err: err}
+		}
+		select {
+		case chunks <- last:
+		case <-ctx.Done():
+		}
+	}()
+
+	return chunks
+}
+
+// readSSE calls fn with the event name and data of each server-sent event until fn
+// returns false or the stream ends
+func readSSE(r io.Reader, fn func(event, data string) (bool, error)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var event string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case line == "":
+			if len(data) > 0 {
+				more, err := fn(event, strings.Join(data, "\n"))
+				if err != nil || !more {
+					return err
+				}
+			}
+			event, data = "", nil
+		case strings.HasPrefix(line, ":"):
+			// Comment, used by some APIs as a keep-alive
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	// A final event without a trailing blank line
+	if len(data) > 0 {
+		_, err := fn(event, strings.Join(data, "\n"))
+		return err
+	}
+
+	return nil
+}
+
+// withSystem prepends a system message to the conversation for APIs that take the
+// system prompt as a message
+func withSystem(req ChatRequest) []Message {
+	if req.System == "" {
+		return req.Messages
+	}
+	return append([]Message{{Role: "system", Content: req.System}}, req.Messages...)
+}
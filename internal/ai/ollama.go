@@ -1,7 +1,6 @@
 package ai
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -21,14 +20,29 @@ type Ollama struct {
 	client  *http.Client
 }
 
+type ollamaOptions struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+	NumPredict  int      `json:"num_predict,omitempty"`
+}
+
 type ollamaRequest struct {
-	Model  string `json:"model"`
-	Prompt string `json:"prompt"`
-	Stream bool   `json:"stream"`
+	Model    string        `json:"model"`
+	Messages []Message     `json:"messages"`
+	Stream   bool          `json:"stream"`
+	Options  ollamaOptions `json:"options"`
 }
 
 type ollamaResponse struct {
-	Response string `json:"response"`
+	Message         Message `json:"message"`
+	Done            bool    `json:"done"`
+	PromptEvalCount int     `json:"prompt_eval_count"`
+	EvalCount       int     `json:"eval_count"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// usage returns the token counts, which Ollama reports on the final response
+func (r *ollamaResponse) usage(model string) Usage {
+	return Usage{Model: model, PromptTokens: r.PromptEvalCount, CompletionTokens: r.EvalCount}
 }
 
 // NewOllama creates a new Ollama provider
@@ -56,6 +70,10 @@ func (o *Ollama) Name() string {
 	return "ollama"
 }
 
+func (o *Ollama) Model() string {
+	return o.model
+}
+
 func (o *Ollama) Available() bool {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -75,38 +93,89 @@ func (o *Ollama) Available() bool {
 }
 
 func (o *Ollama) Analyze(ctx context.Context, prompt string) (string, error) {
-	reqBody := ollamaRequest{
-		Model:  o.model,
-		Prompt: prompt,
-		Stream: false,
-	}
-
-	body, err := json.Marshal(reqBody)
+	resp, err := o.Chat(ctx, Prompt(prompt))
 	if err != nil {
 		return "", err
 	}
+	return resp.Content, nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/api/generate", bytes.NewReader(body))
+func (o *Ollama) Chat(ctx context.Context, req ChatRequest) (*Response, error) {
+	resp, err := postJSON(ctx, o.client, "ollama", o.baseURL+"/api/chat", nil, o.request(req, false))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
+	defer resp.Body.Close()
 
-	resp, err := o.client.Do(req)
+	var ollamaResp ollamaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
+		return nil, err
+	}
+
+	if ollamaResp.Error != "" {
+		return nil, fmt.Errorf("ollama error: %s", ollamaResp.Error)
+	}
+
+	return &Response{Content: ollamaResp.Message.Content, Usage: ollamaResp.usage(o.model)}, nil
+}
+
+func (o *Ollama) Stream(ctx context.Context, req ChatRequest) (<-chan Chunk, error) {
+	resp, err := postJSON(ctx, streamClient, "ollama", o.baseURL+"/api/chat", nil, o.request(req, true))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("ollama returned status %d: %s", resp.StatusCode, string(respBody))
+	// Ollama streams newline-delimited JSON objects rather than server-sent events
+	return streamBody(ctx, resp.Body, func(emit func(string) bool) (Usage, error) {
+		usage := Usage{Model: o.model}
+		decoder := json.NewDecoder(resp.Body)
+		for {
+			var chunk ollamaResponse
+			if err := decoder.Decode(&chunk); err != nil {
+				if err == io.EOF {
+					return usage, nil
+				}
+				return usage, fmt.Errorf("ollama returned an invalid stream line: %w", err)
+			}
+			if chunk.Error != "" {
+				return usage, fmt.Errorf("ollama error: %s", chunk.Error)
+			}
+			if chunk.Done {
+				usage = chunk.usage(o.model)
+			}
+			if !emit(chunk.Message.Content) || chunk.Done {
+				return usage, nil
+			}
+		}
+	}), nil
+}
+
+// Models lists the models pulled into the Ollama server
+func (o *Ollama) Models(ctx context.Context) ([]Model, error) {
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := getJSON(ctx, o.client, "ollama", o.baseURL+"/api/tags", nil, &tags); err != nil {
+		return nil, err
 	}
 
-	var ollamaResp ollamaResponse
-	if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
-		return "", err
+	models := make([]Model, 0, len(tags.Models))
+	for _, m := range tags.Models {
+		models = append(models, Model{ID: m.Name})
 	}
+	return sortModels(models), nil
+}
 
-	return ollamaResp.Response, nil
+func (o *Ollama) request(req ChatRequest, stream bool) ollamaRequest {
+	return ollamaRequest{
+		Model:    o.model,
+		Messages: withSystem(req),
+		Stream:   stream,
+		Options: ollamaOptions{
+			Temperature: req.Temperature,
+			NumPredict:  req.maxTokens(),
+		},
+	}
 }
@@ -0,0 +1,180 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// openAICompatible implements the Provider interface for APIs that follow the OpenAI
+// chat completions format. OpenAI, Groq, Mistral, DeepSeek and OpenRouter embed it.
+type openAICompatible struct {
+	name    string
+	apiKey  string
+	baseURL string
+	model   string
+	headers map[string]string
+	client  *http.Client
+	// streamUsage asks for token usage in the last chunk of a stream, which not every
+	// compatible API accepts
+	streamUsage bool
+}
+
+type openAIRequest struct {
+	Model       string    `json:"model"`
+	Messages    []Message `json:"messages"`
+	Temperature *float64  `json:"temperature,omitempty"`
+	MaxTokens   int       `json:"max_tokens,omitempty"`
+	Stream      bool      `json:"stream,omitempty"`
+	StreamOpts  *struct {
+		IncludeUsage bool `json:"include_usage"`
+	} `json:"stream_options,omitempty"`
+}
+
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *openAIUsage `json:"usage,omitempty"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func (o *openAICompatible) Name() string {
+	return o.name
+}
+
+func (o *openAICompatible) Model() string {
+	return o.model
+}
+
+func (o *openAICompatible) Available() bool {
+	return o.apiKey != ""
+}
+
+func (o *openAICompatible) Analyze(ctx context.Context, prompt string) (string, error) {
+	resp, err := o.Chat(ctx, Prompt(prompt))
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
+
+func (o *openAICompatible) Chat(ctx context.Context, req ChatRequest) (*Response, error) {
+	resp, err := postJSON(ctx, o.client, o.name, o.baseURL+"/chat/completions", o.requestHeaders(), o.request(req, false))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var chatResp openAIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return nil, err
+	}
+
+	if chatResp.Error != nil {
+		return nil, fmt.Errorf("%s error: %s", o.name, chatResp.Error.Message)
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return nil, fmt.Errorf("%s returned no choices", o.name)
+	}
+
+	return &Response{Content: chatResp.Choices[0].Message.Content, Usage: o.usage(chatResp.Usage)}, nil
+}
+
+func (o *openAICompatible) Stream(ctx context.Context, req ChatRequest) (<-chan Chunk, error) {
+	resp, err := postJSON(ctx, streamClient, o.name, o.baseURL+"/chat/completions", o.requestHeaders(), o.request(req, true))
+	if err != nil {
+		return nil, err
+	}
+
+	return streamBody(ctx, resp.Body, func(emit func(string) bool) (Usage, error) {
+		usage := o.usage(nil)
+		err := readSSE(resp.Body, func(_, data string) (bool, error) {
+			if data == "[DONE]" {
+				return false, nil
+			}
+
+			var chunk openAIResponse
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				return false, fmt.Errorf("%s returned an invalid stream event: %w", o.name, err)
+			}
+			if chunk.Error != nil {
+				return false, fmt.Errorf("%s error: %s", o.name, chunk.Error.Message)
+			}
+			if chunk.Usage != nil {
+				usage = o.usage(chunk.Usage)
+			}
+			if len(chunk.Choices) == 0 {
+				return true, nil
+			}
+
+			return emit(chunk.Choices[0].Delta.Content), nil
+		})
+		return usage, err
+	}), nil
+}
+
+func (o *openAICompatible) usage(u *openAIUsage) Usage {
+	usage := Usage{Model: o.model}
+	if u != nil {
+		usage.PromptTokens = u.PromptTokens
+		usage.CompletionTokens = u.CompletionTokens
+	}
+	return usage
+}
+
+func (o *openAICompatible) Models(ctx context.Context) ([]Model, error) {
+	var list struct {
+		Data []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, o.client, o.name, o.baseURL+"/models", o.requestHeaders(), &list); err != nil {
+		return nil, err
+	}
+
+	models := make([]Model, 0, len(list.Data))
+	for _, m := range list.Data {
+		models = append(models, Model{ID: m.ID, Name: m.Name})
+	}
+	return sortModels(models), nil
+}
+
+func (o *openAICompatible) request(req ChatRequest, stream bool) openAIRequest {
+	r := openAIRequest{
+		Model:       o.model,
+		Messages:    withSystem(req),
+		Temperature: req.Temperature,
+		MaxTokens:   req.maxTokens(),
+		Stream:      stream,
+	}
+	if stream && o.streamUsage {
+		r.StreamOpts = &struct {
+			IncludeUsage bool `json:"include_usage"`
+		}{IncludeUsage: true}
+	}
+	return r
+}
+
+func (o *openAICompatible) requestHeaders() map[string]string {
+	headers := map[string]string{"Authorization": "Bearer " + o.apiKey}
+	for k, v := range o.headers {
+		headers[k] = v
+	}
+	return headers
+}
@@ -3,6 +3,8 @@ package ai
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 )
 
 // Provider defines the interface for AI providers
@@ -13,10 +15,92 @@ type Provider interface {
 	// Analyze sends a prompt to the AI and returns the analysis
 	Analyze(ctx context.Context, prompt string) (string, error)
 
+	// Chat sends a conversation to the AI and returns the complete reply
+	Chat(ctx context.Context, req ChatRequest) (*Response, error)
+
+	// Stream sends a conversation to the AI and returns the reply as it is generated.
+	// The channel is closed when the reply is complete; a failure is sent as a final
+	// chunk with Err set.
+	Stream(ctx context.Context, req ChatRequest) (<-chan Chunk, error)
+
 	// Available checks if the provider is properly configured
 	Available() bool
 }
 
+// DefaultMaxTokens is the reply length limit used when a request does not set one
+const DefaultMaxTokens = 4096
+
+// ChatRequest is a conversation sent to a provider
+type ChatRequest struct {
+	// System is an optional system prompt
+	System string
+	// Messages are the conversation turns, oldest first, with roles "user" and "assistant"
+	Messages []Message
+	// Temperature is the sampling temperature; nil uses the provider default
+	Temperature *float64
+	// MaxTokens limits the reply length; zero uses DefaultMaxTokens
+	MaxTokens int
+}
+
+// maxTokens returns the reply length limit of the request
+func (r ChatRequest) maxTokens() int {
+	if r.MaxTokens > 0 {
+		return r.MaxTokens
+	}
+	return DefaultMaxTokens
+}
+
+// Prompt creates a single-turn request
+func Prompt(prompt string) ChatRequest {
+	return ChatRequest{Messages: []Message{{Role: "user", Content: prompt}}}
+}
+
+// Usage is the number of tokens a provider call consumed, and the provider and
+// model that answered
+type Usage struct {
+	Provider         string `json:"provider"`
+	Model            string `json:"model"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+}
+
+// Response is a complete reply from a provider
+type Response struct {
+	Content string
+	Usage   Usage
+}
+
+// Chunk is a piece of a streamed reply. The last chunk of a successful stream
+// carries the usage of the call and no content.
+type Chunk struct {
+	Content string
+	Usage   *Usage
+	Err     error
+}
+
+// Collect reads a stream to the end and returns the full reply
+func Collect(chunks <-chan Chunk) (string, error) {
+	var sb strings.Builder
+	for chunk := range chunks {
+		if chunk.Err != nil {
+			return sb.String(), chunk.Err
+		}
+		sb.WriteString(chunk.Content)
+	}
+	return sb.String(), nil
+}
+
+// Model is a model offered by a provider
+type Model struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// ModelLister is implemented by providers that can list the models they offer
+type ModelLister interface {
+	Models(ctx context.Context) ([]Model, error)
+}
+
 // ProviderConfig holds configuration for an AI provider
 type ProviderConfig struct {
 	Type     string            `json:"type"`
@@ -52,11 +136,18 @@ func New(cfg ProviderConfig) (Provider, error) {
 	return factory(cfg)
 }
 
-// AvailableProviders returns a list of registered provider types
+// AvailableProviders returns the registered provider types in alphabetical order
 func AvailableProviders() []string {
-	var providers []string
+	providers := make([]string, 0, len(registry))
 	for name := range registry {
 		providers = append(providers, name)
 	}
+	sort.Strings(providers)
 	return providers
 }
+
+// sortModels orders models by ID
+func sortModels(models []Model) []Model {
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+	return models
+}
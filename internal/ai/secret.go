@@ -0,0 +1,117 @@
+package ai
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// keySize is the AES-256 key length in bytes
+const keySize = 32
+
+// encryptedPrefix marks values written by Cipher.Encrypt
+const encryptedPrefix = "v1:"
+
+// Cipher encrypts secrets such as API keys before they are stored
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates an AES-GCM cipher from a 32-byte key
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != keySize {
+		return nil, fmt.Errorf("secret key must be %d bytes, got %d", keySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt encrypts a secret with a random nonce
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a secret produced by Encrypt
+func (c *Cipher) Decrypt(encrypted string) (string, error) {
+	if !strings.HasPrefix(encrypted, encryptedPrefix) {
+		return "", fmt.Errorf("unrecognized secret format")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(encrypted, encryptedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode secret: %w", err)
+	}
+
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", fmt.Errorf("secret is too short")
+	}
+
+	plaintext, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret (wrong secret key?): %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// LoadKey returns the key used to encrypt secrets. A configured secret is used when
+// set: base64 for a raw 32-byte key, anything else is hashed as a passphrase.
+// Otherwise the key is read from keyFile, which is generated on first use.
+func LoadKey(secret, keyFile string) ([]byte, error) {
+	if secret != "" {
+		if key, err := base64.StdEncoding.DecodeString(secret); err == nil && len(key) == keySize {
+			return key, nil
+		}
+		sum := sha256.Sum256([]byte(secret))
+		return sum[:], nil
+	}
+
+	data, err := os.ReadFile(keyFile)
+	if err == nil {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(key) != keySize {
+			return nil, fmt.Errorf("invalid secret key file %s", keyFile)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read secret key file: %w", err)
+	}
+
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate secret key: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(keyFile), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create secret key directory: %w", err)
+	}
+	if err := os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write secret key file: %w", err)
+	}
+
+	return key, nil
+}
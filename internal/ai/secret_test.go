@@ -0,0 +1,69 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCipher_RoundTrip(t *testing.T) {
+	c, err := NewCipher(bytes.Repeat([]byte{1}, keySize))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+
+	a, _ := c.Encrypt("sk-secret")
+	b, _ := c.Encrypt("sk-secret")
+	if a == b || !strings.HasPrefix(a, encryptedPrefix) || strings.Contains(a, "sk-secret") {
+		t.Errorf("expected distinct prefixed ciphertexts, got %q and %q", a, b)
+	}
+
+	if got, err := c.Decrypt(a); err != nil || got != "sk-secret" {
+		t.Errorf("Decrypt() = %q, %v", got, err)
+	}
+
+	other, _ := NewCipher(bytes.Repeat([]byte{2}, keySize))
+	if _, err := other.Decrypt(a); err == nil {
+		t.Error("expected decryption with another key to fail")
+	}
+	if _, err := c.Decrypt("plain"); err == nil {
+		t.Error("expected unprefixed values to be rejected")
+	}
+	if _, err := NewCipher([]byte("short")); err == nil {
+		t.Error("expected a short key to be rejected")
+	}
+}
+
+func TestLoadKey(t *testing.T) {
+	raw := bytes.Repeat([]byte{7}, keySize)
+	if key, err := LoadKey(base64.StdEncoding.EncodeToString(raw), ""); err != nil || !bytes.Equal(key, raw) {
+		t.Errorf("expected a base64 secret to be used as is, got %v, %v", key, err)
+	}
+
+	passphrase, err := LoadKey("correct horse battery staple", "")
+	if err != nil || len(passphrase) != keySize {
+		t.Errorf("expected a passphrase to be hashed to a key, got %v, %v", passphrase, err)
+	}
+
+	keyFile := filepath.Join(t.TempDir(), "data", "secret.key")
+	generated, err := LoadKey("", keyFile)
+	if err != nil || len(generated) != keySize {
+		t.Fatalf("expected a generated key, got %v, %v", generated, err)
+	}
+	if info, err := os.Stat(keyFile); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected a private key file, got %v, %v", info, err)
+	}
+
+	again, err := LoadKey("", keyFile)
+	if err != nil || !bytes.Equal(again, generated) {
+		t.Error("expected the key file to be read back")
+	}
+
+	os.WriteFile(keyFile, []byte("not a key"), 0600)
+	if _, err := LoadKey("", keyFile); err == nil {
+		t.Error("expected an invalid key file to be rejected")
+	}
+}
@@ -0,0 +1,388 @@
+package ai
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNotConfigured is returned when no settings exist for the requested provider
+var ErrNotConfigured = errors.New("AI provider not configured")
+
+// ErrKeyRequired is returned when settings point a provider at another base URL
+// without an API key; the stored key is never sent to a host it was not saved for
+var ErrKeyRequired = errors.New("an API key is required when changing the base URL")
+
+// Settings are the stored configuration of an AI provider
+type Settings struct {
+	Type      string            `json:"type"`
+	APIKey    string            `json:"-"`
+	BaseURL   string            `json:"base_url,omitempty"`
+	Model     string            `json:"model,omitempty"`
+	Options   map[string]string `json:"options,omitempty"`
+	IsDefault bool              `json:"is_default"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// Config returns the provider configuration for these settings
+func (s *Settings) Config() ProviderConfig {
+	return ProviderConfig{
+		Type:    s.Type,
+		APIKey:  s.APIKey,
+		BaseURL: s.BaseURL,
+		Model:   s.Model,
+		Options: s.Options,
+	}
+}
+
+// MaskKey hides all but the last four characters of an API key
+func MaskKey(key string) string {
+	if key == "" {
+		return ""
+	}
+	if len(key) <= 8 {
+		return "••••"
+	}
+	return "••••" + key[len(key)-4:]
+}
+
+// Store persists AI provider settings, encrypting API keys at rest
+type Store struct {
+	db     *sql.DB
+	cipher *Cipher
+	meter  *Meter
+}
+
+// NewStore creates a new provider settings store whose providers are metered by meter
+func NewStore(db *sql.DB, cipher *Cipher, meter *Meter) *Store {
+	return &Store{db: db, cipher: cipher, meter: meter}
+}
+
+// Meter returns the usage meter of the store's providers
+func (s *Store) Meter() *Meter {
+	return s.meter
+}
+
+// List returns the settings of every configured provider
+func (s *Store) List() ([]Settings, error) {
+	rows, err := s.db.Query(`
+		SELECT type, api_key, base_url, model, options, is_default, updated_at
+		FROM ai_providers
+		ORDER BY type ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query AI providers: %w", err)
+	}
+	defer rows.Close()
+
+	settings := []Settings{}
+	for rows.Next() {
+		st, err := s.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		settings = append(settings, *st)
+	}
+
+	return settings, rows.Err()
+}
+
+// Get returns the settings of a provider, or nil if it is not configured
+func (s *Store) Get(providerType string) (*Settings, error) {
+	st, err := s.scan(s.db.QueryRow(`
+		SELECT type, api_key, base_url, model, options, is_default, updated_at
+		FROM ai_providers
+		WHERE type = ?
+	`, providerType))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return st, err
+}
+
+// Default returns the default provider settings, or nil if none is configured
+func (s *Store) Default() (*Settings, error) {
+	st, err := s.scan(s.db.QueryRow(`
+		SELECT type, api_key, base_url, model, options, is_default, updated_at
+		FROM ai_providers
+		ORDER BY is_default DESC, updated_at DESC
+		LIMIT 1
+	`))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return st, err
+}
+
+// Save creates or updates provider settings. An empty API key keeps the stored one,
+// so clients never need to send a key back, unless the base URL changes. The first
+// provider saved becomes the default.
+func (s *Store) Save(st *Settings) error {
+	if _, ok := registry[st.Type]; !ok {
+		return fmt.Errorf("unknown AI provider: %s", st.Type)
+	}
+
+	existing, err := s.Get(st.Type)
+	if err != nil {
+		return err
+	}
+	if st.APIKey == "" && existing != nil {
+		if existing.APIKey != "" && !sameBaseURL(st.BaseURL, existing.BaseURL) {
+			return ErrKeyRequired
+		}
+		st.APIKey = existing.APIKey
+	}
+
+	// Make sure the settings actually produce a provider before storing them
+	if _, err := New(st.Config()); err != nil {
+		return err
+	}
+
+	var encrypted sql.NullString
+	if st.APIKey != "" {
+		value, err := s.cipher.Encrypt(st.APIKey)
+		if err != nil {
+			return err
+		}
+		encrypted = sql.NullString{String: value, Valid: true}
+	}
+
+	options, err := json.Marshal(st.Options)
+	if err != nil || st.Options == nil {
+		options = []byte("{}")
+	}
+
+	current, err := s.Default()
+	if err != nil {
+		return err
+	}
+	if current == nil || current.Type == st.Type && current.IsDefault {
+		st.IsDefault = true
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if st.IsDefault {
+		if _, err := tx.Exec(`UPDATE ai_providers SET is_default = false WHERE type != ?`, st.Type); err != nil {
+			return fmt.Errorf("failed to clear default AI provider: %w", err)
+		}
+	}
+
+	st.UpdatedAt = time.Now().UTC()
+	_, err = tx.Exec(`
+		INSERT INTO ai_providers (type, api_key, base_url, model, options, is_default, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(type) DO UPDATE SET
+			api_key = excluded.api_key,
+			base_url = excluded.base_url,
+			model = excluded.model,
+			options = excluded.options,
+			is_default = excluded.is_default,
+			updated_at = excluded.updated_at
+	`, st.Type, encrypted, st.BaseURL, st.Model, string(options), st.IsDefault, st.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save AI provider: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes the settings of a provider
+func (s *Store) Delete(providerType string) error {
+	result, err := s.db.Exec(`DELETE FROM ai_providers WHERE type = ?`, providerType)
+	if err != nil {
+		return fmt.Errorf("failed to delete AI provider: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrNotConfigured
+	}
+
+	return nil
+}
+
+// Direct creates the provider of the given type on its own, without fallback, retries
+// or metering, for checking its settings. Non-empty fields of override replace the
+// stored ones, so settings can be tried before they are saved; an override of the base
+// URL must bring its own API key. With a nil override the provider must be configured.
+func (s *Store) Direct(providerType string, override *Settings) (Provider, *Settings, error) {
+	if _, ok := registry[providerType]; !ok {
+		return nil, nil, fmt.Errorf("unknown AI provider: %s", providerType)
+	}
+
+	st, err := s.Get(providerType)
+	if err != nil {
+		return nil, nil, err
+	}
+	if st == nil {
+		if override == nil {
+			return nil, nil, ErrNotConfigured
+		}
+		st = &Settings{Type: providerType}
+	}
+
+	if override != nil {
+		if override.BaseURL != "" && !sameBaseURL(override.BaseURL, st.BaseURL) {
+			if override.APIKey == "" && st.APIKey != "" {
+				return nil, nil, ErrKeyRequired
+			}
+			st.APIKey = override.APIKey
+			st.BaseURL = override.BaseURL
+		}
+		if override.APIKey != "" {
+			st.APIKey = override.APIKey
+		}
+		if override.Model != "" {
+			st.Model = override.Model
+		}
+		if override.Options != nil {
+			st.Options = override.Options
+		}
+	}
+
+	provider, err := New(st.Config())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return provider, st, nil
+}
+
+// Fallback returns the provider types tried, in order, when the requested provider fails
+func (s *Store) Fallback() ([]string, error) {
+	rows, err := s.db.Query(`
+		SELECT type FROM ai_providers
+		WHERE fallback_position IS NOT NULL
+		ORDER BY fallback_position ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query AI fallback order: %w", err)
+	}
+	defer rows.Close()
+
+	types := []string{}
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+
+	return types, rows.Err()
+}
+
+// SetFallback replaces the fallback order. Every provider in it must be configured.
+func (s *Store) SetFallback(types []string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE ai_providers SET fallback_position = NULL`); err != nil {
+		return fmt.Errorf("failed to clear AI fallback order: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for i, t := range types {
+		if seen[t] {
+			return fmt.Errorf("%s is listed twice", t)
+		}
+		seen[t] = true
+
+		result, err := tx.Exec(`UPDATE ai_providers SET fallback_position = ? WHERE type = ?`, i, t)
+		if err != nil {
+			return fmt.Errorf("failed to save AI fallback order: %w", err)
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return fmt.Errorf("%w: %s", ErrNotConfigured, t)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Provider creates a provider from stored settings that calls the provider of the
+// given type, or the default provider when providerType is empty, followed by the
+// fallback order. Every call is retried per DefaultRetryPolicy and metered.
+func (s *Store) Provider(providerType string) (Provider, error) {
+	var st *Settings
+	var err error
+	if providerType == "" {
+		st, err = s.Default()
+	} else {
+		st, err = s.Get(providerType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if st == nil {
+		return nil, ErrNotConfigured
+	}
+
+	primary, err := New(st.Config())
+	if err != nil {
+		return nil, err
+	}
+	providers := []Provider{s.meter.Wrap(primary)}
+
+	fallback, err := s.Fallback()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range fallback {
+		if t == st.Type {
+			continue
+		}
+
+		// A fallback whose settings no longer work is skipped rather than failing the call
+		fst, err := s.Get(t)
+		if err != nil || fst == nil {
+			continue
+		}
+		p, err := New(fst.Config())
+		if err != nil {
+			continue
+		}
+		providers = append(providers, s.meter.Wrap(p))
+	}
+
+	return NewChain(DefaultRetryPolicy, providers...), nil
+}
+
+// sameBaseURL reports whether two base URLs address the same endpoint
+func sameBaseURL(a, b string) bool {
+	return strings.TrimRight(strings.TrimSpace(a), "/") == strings.TrimRight(strings.TrimSpace(b), "/")
+}
+
+func (s *Store) scan(row interface{ Scan(...any) error }) (*Settings, error) {
+	var st Settings
+	var apiKey, baseURL, model, options sql.NullString
+
+	if err := row.Scan(&st.Type, &apiKey, &baseURL, &model, &options, &st.IsDefault, &st.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	st.BaseURL = baseURL.String
+	st.Model = model.String
+	json.Unmarshal([]byte(options.String), &st.Options)
+
+	if apiKey.String != "" {
+		key, err := s.cipher.Decrypt(apiKey.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s API key: %w", st.Type, err)
+		}
+		st.APIKey = key
+	}
+
+	return &st, nil
+}
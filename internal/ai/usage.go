@@ -0,0 +1,362 @@
+package ai
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+)
+
+// ErrBudgetExceeded is returned instead of calling a provider once the monthly spend cap is reached
+var ErrBudgetExceeded = errors.New("monthly AI spend cap reached")
+
+// Price is the cost of a model in US dollars per million tokens
+type Price struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// Prices maps provider types to model names to prices. The model "*" prices every
+// model of a provider that has no entry of its own.
+type Prices map[string]map[string]Price
+
+// DefaultPrices covers the default model of each provider
+var DefaultPrices = Prices{
+	"anthropic":  {"claude-3-haiku-20240307": {Input: 0.25, Output: 1.25}},
+	"deepseek":   {"deepseek-chat": {Input: 0.27, Output: 1.10}},
+	"google":     {"gemini-1.5-flash": {Input: 0.075, Output: 0.30}},
+	"groq":       {"llama-3.3-70b-versatile": {Input: 0.59, Output: 0.79}},
+	"mistral":    {"mistral-small-latest": {Input: 0.20, Output: 0.60}},
+	"ollama":     {"*": {}},
+	"openai":     {"gpt-4o-mini": {Input: 0.15, Output: 0.60}, "gpt-4o": {Input: 2.50, Output: 10}},
+	"openrouter": {"meta-llama/llama-3.2-3b-instruct:free": {}},
+}
+
+// Merge returns the prices with overrides applied on top
+func (p Prices) Merge(overrides Prices) Prices {
+	merged := make(Prices, len(p))
+	for _, prices := range []Prices{p, overrides} {
+		for provider, models := range prices {
+			if merged[provider] == nil {
+				merged[provider] = make(map[string]Price)
+			}
+			for model, price := range models {
+				merged[provider][model] = price
+			}
+		}
+	}
+	return merged
+}
+
+// Cost returns the cost in US dollars of a call, and false if the model has no price
+func (p Prices) Cost(provider string, u Usage) (float64, bool) {
+	models := p[provider]
+	price, ok := models[u.Model]
+	if !ok {
+		price, ok = models["*"]
+	}
+	if !ok {
+		return 0, false
+	}
+	return (float64(u.PromptTokens)*price.Input + float64(u.CompletionTokens)*price.Output) / 1e6, true
+}
+
+type purposeKey struct{}
+
+// WithPurpose labels the provider calls made with ctx, such as "analyze" or "enrich",
+// so their usage can be told apart
+func WithPurpose(ctx context.Context, purpose string) context.Context {
+	return context.WithValue(ctx, purposeKey{}, purpose)
+}
+
+func purposeOf(ctx context.Context) string {
+	purpose, _ := ctx.Value(purposeKey{}).(string)
+	return purpose
+}
+
+// Meter records the token usage and cost of provider calls and enforces a monthly spend cap
+type Meter struct {
+	db         *sql.DB
+	prices     Prices
+	monthlyCap float64
+	now        func() time.Time
+}
+
+// NewMeter creates a new usage meter. A monthlyCap of zero disables the cap.
+func NewMeter(db *sql.DB, prices Prices, monthlyCap float64) *Meter {
+	if prices == nil {
+		prices = DefaultPrices
+	}
+	return &Meter{db: db, prices: prices, monthlyCap: monthlyCap, now: time.Now}
+}
+
+// Wrap returns a provider whose calls are checked against the cap and recorded
+func (m *Meter) Wrap(p Provider) Provider {
+	return &metered{Provider: p, meter: m}
+}
+
+// Check returns ErrBudgetExceeded once this month's spend has reached the cap.
+// Calls already in flight may overshoot the cap by their own cost.
+func (m *Meter) Check() error {
+	if m.monthlyCap <= 0 {
+		return nil
+	}
+
+	spend, err := m.MonthSpend()
+	if err != nil {
+		return err
+	}
+	if spend >= m.monthlyCap {
+		return ErrBudgetExceeded
+	}
+
+	return nil
+}
+
+// MonthSpend returns the cost of all calls since the start of the current UTC month
+func (m *Meter) MonthSpend() (float64, error) {
+	var spend float64
+	err := m.db.QueryRow(`
+		SELECT COALESCE(SUM(cost), 0) FROM ai_usage WHERE datetime(created_at) >= datetime(?)
+	`, m.monthStart().Format(time.RFC3339)).Scan(&spend)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum AI spend: %w", err)
+	}
+	return spend, nil
+}
+
+// Record stores the usage of a call and returns its cost
+func (m *Meter) Record(purpose string, u Usage) (float64, error) {
+	cost, ok := m.prices.Cost(u.Provider, u)
+	if !ok && u.PromptTokens+u.CompletionTokens > 0 {
+		log.Printf("No price configured for %s model %s, recording its usage at no cost", u.Provider, u.Model)
+	}
+
+	_, err := m.db.Exec(`
+		INSERT INTO ai_usage (provider, model, purpose, prompt_tokens, completion_tokens, cost, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, u.Provider, u.Model, purpose, u.PromptTokens, u.CompletionTokens, cost, m.now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return 0, fmt.Errorf("failed to record AI usage: %w", err)
+	}
+
+	return cost, nil
+}
+
+// UsageTotal sums the calls of a period or group
+type UsageTotal struct {
+	Period           string  `json:"period,omitempty"`
+	Provider         string  `json:"provider,omitempty"`
+	Model            string  `json:"model,omitempty"`
+	Purpose          string  `json:"purpose,omitempty"`
+	Calls            int     `json:"calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	Cost             float64 `json:"cost"`
+}
+
+// UsageSummary reports spend against the monthly cap with daily and monthly totals
+type UsageSummary struct {
+	// MonthlyCap is the monthly spend cap in US dollars; zero means no cap
+	MonthlyCap float64 `json:"monthly_cap"`
+	MonthSpend float64 `json:"month_spend"`
+	// Remaining is the spend left this month, or nil without a cap
+	Remaining *float64     `json:"remaining"`
+	Daily     []UsageTotal `json:"daily"`
+	Monthly   []UsageTotal `json:"monthly"`
+	// Breakdown splits this month's usage by provider, model and purpose
+	Breakdown []UsageTotal `json:"breakdown"`
+}
+
+// Summary returns usage totals for the last days days and months months, newest first
+func (m *Meter) Summary(days, months int) (*UsageSummary, error) {
+	now := m.now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart := m.monthStart()
+
+	summary := &UsageSummary{MonthlyCap: m.monthlyCap}
+
+	spend, err := m.MonthSpend()
+	if err != nil {
+		return nil, err
+	}
+	summary.MonthSpend = round(spend)
+	if m.monthlyCap > 0 {
+		remaining := round(math.Max(m.monthlyCap-spend, 0))
+		summary.Remaining = &remaining
+	}
+
+	summary.Daily, err = m.totals(`date(created_at)`, today.AddDate(0, 0, -(days-1)))
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Monthly, err = m.totals(`strftime('%Y-%m', created_at)`, monthStart.AddDate(0, -(months-1), 0))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query(`
+		SELECT provider, model, COALESCE(purpose, ''), COUNT(*),
+			SUM(prompt_tokens), SUM(completion_tokens), SUM(cost)
+		FROM ai_usage
+		WHERE datetime(created_at) >= datetime(?)
+		GROUP BY provider, model, purpose
+		ORDER BY SUM(cost) DESC, COUNT(*) DESC
+	`, monthStart.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query AI usage breakdown: %w", err)
+	}
+	defer rows.Close()
+
+	summary.Breakdown = []UsageTotal{}
+	for rows.Next() {
+		var t UsageTotal
+		if err := rows.Scan(&t.Provider, &t.Model, &t.Purpose, &t.Calls, &t.PromptTokens, &t.CompletionTokens, &t.Cost); err != nil {
+			return nil, fmt.Errorf("failed to scan AI usage: %w", err)
+		}
+		t.Cost = round(t.Cost)
+		summary.Breakdown = append(summary.Breakdown, t)
+	}
+
+	return summary, rows.Err()
+}
+
+// totals sums usage since the given time, grouped by the period expression
+func (m *Meter) totals(period string, since time.Time) ([]UsageTotal, error) {
+	rows, err := m.db.Query(`
+		SELECT `+period+` AS period, COUNT(*),
+			SUM(prompt_tokens), SUM(completion_tokens), SUM(cost)
+		FROM ai_usage
+		WHERE datetime(created_at) >= datetime(?)
+		GROUP BY period
+		ORDER BY period DESC
+	`, since.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query AI usage: %w", err)
+	}
+	defer rows.Close()
+
+	totals := []UsageTotal{}
+	for rows.Next() {
+		var t UsageTotal
+		if err := rows.Scan(&t.Period, &t.Calls, &t.PromptTokens, &t.CompletionTokens, &t.Cost); err != nil {
+			return nil, fmt.Errorf("failed to scan AI usage: %w", err)
+		}
+		t.Cost = round(t.Cost)
+		totals = append(totals, t)
+	}
+
+	return totals, rows.Err()
+}
+
+func (m *Meter) monthStart() time.Time {
+	now := m.now().UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// round rounds a dollar amount to a millionth of a dollar
+func round(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
+
+// metered checks the spend cap before each call of the provider it wraps and records
+// the usage of each successful call and of each stream, even one cut short
+type metered struct {
+	Provider
+	meter *Meter
+}
+
+func (p *metered) Analyze(ctx context.Context, prompt string) (string, error) {
+	resp, err := p.Chat(ctx, Prompt(prompt))
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
+
+func (p *metered) Chat(ctx context.Context, req ChatRequest) (*Response, error) {
+	if err := p.meter.Check(); err != nil {
+		return nil, err
+	}
+
+	resp, err := p.Provider.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Usage.Provider = p.Name()
+	p.record(ctx, resp.Usage)
+	return resp, nil
+}
+
+func (p *metered) Stream(ctx context.Context, req ChatRequest) (<-chan Chunk, error) {
+	if err := p.meter.Check(); err != nil {
+		return nil, err
+	}
+
+	chunks, err := p.Provider.Stream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan Chunk)
+	go func() {
+		defer close(out)
+
+		// Streams that fail or are abandoned before the final chunk still cost what
+		// was sent and generated, so their usage is estimated instead
+		var reply strings.Builder
+		recorded := false
+		defer func() {
+			if !recorded {
+				p.record(ctx, p.estimate(req, reply.String()))
+			}
+		}()
+
+		for chunk := range chunks {
+			reply.WriteString(chunk.Content)
+			if chunk.Usage != nil {
+				chunk.Usage.Provider = p.Name()
+				p.record(ctx, *chunk.Usage)
+				recorded = true
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// estimate approximates the usage of a call from the length of its text, at about
+// four characters a token
+func (p *metered) estimate(req ChatRequest, reply string) Usage {
+	prompt := len(req.System)
+	for _, m := range req.Messages {
+		prompt += len(m.Content)
+	}
+
+	u := Usage{
+		Provider:         p.Name(),
+		PromptTokens:     (prompt + 3) / 4,
+		CompletionTokens: (len(reply) + 3) / 4,
+	}
+	if m, ok := p.Provider.(interface{ Model() string }); ok {
+		u.Model = m.Model()
+	}
+	return u
+}
+
+func (p *metered) record(ctx context.Context, u Usage) {
+	if _, err := p.meter.Record(purposeOf(ctx), u); err != nil {
+		log.Printf("%v", err)
+	}
+}
@@ -3,10 +3,16 @@ package alerts
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"mime"
+	"net"
 	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -41,10 +47,35 @@ type AlertPayload struct {
 	DetectedAt  time.Time `json:"detected_at"`
 }
 
+// ReportPayload is the digest sent when a scheduled report is generated
+type ReportPayload struct {
+	Event            string    `json:"event"` // Always "report"
+	Title            string    `json:"title"`
+	ReportID         int64     `json:"report_id"`
+	PeriodStart      time.Time `json:"period_start"`
+	PeriodEnd        time.Time `json:"period_end"`
+	OpportunityCount int       `json:"opportunity_count"`
+	// Summary is the start of the AI analysis, if the report was analyzed
+	Summary string `json:"summary,omitempty"`
+	// Top are the highest-scoring opportunities of the report
+	Top []AlertPayload `json:"top"`
+	URL string         `json:"url"`
+}
+
+// SMTPConfig holds the mail server email alerts are sent through
+type SMTPConfig struct {
+	Host     string
+	Port     int // Default: 587
+	Username string
+	Password string
+	From     string
+}
+
 // AlertService manages alerts
 type AlertService struct {
 	db     *sql.DB
 	client *http.Client
+	smtp   SMTPConfig
 }
 
 // NewAlertService creates a new alert service
@@ -57,6 +88,11 @@ func NewAlertService(db *sql.DB) *AlertService {
 	}
 }
 
+// UseSMTP sets the mail server email alerts are sent through
+func (s *AlertService) UseSMTP(cfg SMTPConfig) {
+	s.smtp = cfg
+}
+
 // GetAlerts returns all configured alerts
 func (s *AlertService) GetAlerts() ([]Alert, error) {
 	rows, err := s.db.Query(`
@@ -69,7 +105,7 @@ func (s *AlertService) GetAlerts() ([]Alert, error) {
 	}
 	defer rows.Close()
 
-	var alerts []Alert
+	alerts := []Alert{}
 	for rows.Next() {
 		var a Alert
 		var configJSON string
@@ -85,7 +121,7 @@ func (s *AlertService) GetAlerts() ([]Alert, error) {
 		alerts = append(alerts, a)
 	}
 
-	return alerts, nil
+	return alerts, rows.Err()
 }
 
 // CreateAlert creates a new alert
@@ -125,16 +161,65 @@ func (s *AlertService) Send(ctx context.Context, alert Alert, payload AlertPaylo
 	case AlertTypeWebhook:
 		return s.sendWebhook(ctx, alert.Destination, payload)
 	case AlertTypeSlack:
-		return s.sendSlack(ctx, alert.Destination, payload)
+		return s.sendSlack(ctx, alert.Destination, fmt.Sprintf("*New Opportunity Detected!*\n\n*%s*\n%s\n\nScore: %d | Source: %s\n<%s|View →>",
+			payload.Title,
+			payload.Description,
+			payload.Score,
+			payload.Source,
+			payload.URL,
+		))
 	case AlertTypeEmail:
-		// Email requires SMTP config - simplified for now
-		return fmt.Errorf("email alerts not yet implemented")
+		return s.sendEmail(ctx, alert.Destination, "New opportunity: "+payload.Title, fmt.Sprintf("%s\n\n%s\n\nScore: %d | Source: %s\n%s\n",
+			payload.Title,
+			payload.Description,
+			payload.Score,
+			payload.Source,
+			payload.URL,
+		))
 	default:
 		return fmt.Errorf("unknown alert type: %s", alert.Type)
 	}
 }
 
-func (s *AlertService) sendWebhook(ctx context.Context, url string, payload AlertPayload) error {
+// SendReport sends the digest of a generated report through an alert's channel
+func (s *AlertService) SendReport(ctx context.Context, alert Alert, payload ReportPayload) error {
+	payload.Event = "report"
+	period := fmt.Sprintf("%s to %s", payload.PeriodStart.Format("2006-01-02"), payload.PeriodEnd.Format("2006-01-02"))
+
+	switch alert.Type {
+	case AlertTypeWebhook:
+		return s.sendWebhook(ctx, alert.Destination, payload)
+	case AlertTypeSlack:
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "*%s*\n%s | %d opportunities\n", payload.Title, period, payload.OpportunityCount)
+		for _, opp := range payload.Top {
+			fmt.Fprintf(&sb, "\n• <%s|%s> (score %d, %s)", opp.URL, opp.Title, opp.Score, opp.Source)
+		}
+		if payload.Summary != "" {
+			fmt.Fprintf(&sb, "\n\n%s", payload.Summary)
+		}
+		fmt.Fprintf(&sb, "\n\n<%s|Open report →>", payload.URL)
+		return s.sendSlack(ctx, alert.Destination, sb.String())
+	case AlertTypeEmail:
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "%s\n%s | %d opportunities\n", payload.Title, period, payload.OpportunityCount)
+		if len(payload.Top) > 0 {
+			sb.WriteString("\nTop opportunities:\n")
+			for i, opp := range payload.Top {
+				fmt.Fprintf(&sb, "%d. %s (score %d, %s)\n   %s\n", i+1, opp.Title, opp.Score, opp.Source, opp.URL)
+			}
+		}
+		if payload.Summary != "" {
+			fmt.Fprintf(&sb, "\n%s\n", payload.Summary)
+		}
+		fmt.Fprintf(&sb, "\nOpen the report: %s\n", payload.URL)
+		return s.sendEmail(ctx, alert.Destination, payload.Title, sb.String())
+	default:
+		return fmt.Errorf("unknown alert type: %s", alert.Type)
+	}
+}
+
+func (s *AlertService) sendWebhook(ctx context.Context, url string, payload any) error {
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return err
@@ -159,15 +244,9 @@ func (s *AlertService) sendWebhook(ctx context.Context, url string, payload Aler
 	return nil
 }
 
-func (s *AlertService) sendSlack(ctx context.Context, webhookURL string, payload AlertPayload) error {
+func (s *AlertService) sendSlack(ctx context.Context, webhookURL string, text string) error {
 	slackPayload := map[string]any{
-		"text": fmt.Sprintf("*New Opportunity Detected!*\n\n*%s*\n%s\n\nScore: %d | Source: %s\n<%s|View →>",
-			payload.Title,
-			payload.Description,
-			payload.Score,
-			payload.Source,
-			payload.URL,
-		),
+		"text": text,
 	}
 
 	body, err := json.Marshal(slackPayload)
@@ -194,6 +273,120 @@ func (s *AlertService) sendSlack(ctx context.Context, webhookURL string, payload
 	return nil
 }
 
+// smtpTimeout bounds a whole SMTP conversation, from dialing to QUIT
+const smtpTimeout = 30 * time.Second
+
+// sendEmail sends a plain text email to a comma-separated list of addresses
+func (s *AlertService) sendEmail(ctx context.Context, to, subject, body string) error {
+	if s.smtp.Host == "" || s.smtp.From == "" {
+		return fmt.Errorf("email alerts require an SMTP host and from address")
+	}
+
+	var recipients []string
+	for _, addr := range strings.Split(to, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			recipients = append(recipients, addr)
+		}
+	}
+	if len(recipients) == 0 {
+		return fmt.Errorf("email alert has no recipients")
+	}
+
+	port := s.smtp.Port
+	if port == 0 {
+		port = 587
+	}
+
+	var auth smtp.Auth
+	if s.smtp.Username != "" {
+		auth = smtp.PlainAuth("", s.smtp.Username, s.smtp.Password, s.smtp.Host)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", s.smtp.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	addr := net.JoinHostPort(s.smtp.Host, strconv.Itoa(port))
+	if err := s.deliverMail(ctx, addr, auth, recipients, []byte(msg.String())); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// deliverMail does what smtp.SendMail does, but gives up after smtpTimeout or
+// when ctx is cancelled instead of waiting on an unresponsive server forever
+func (s *AlertService) deliverMail(ctx context.Context, addr string, auth smtp.Auth, recipients []string, msg []byte) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+	defer func() {
+		// Report why the connection was cut rather than the closed connection
+		if err != nil && ctx.Err() != nil {
+			err = ctx.Err()
+		}
+	}()
+
+	dialer := net.Dialer{Timeout: smtpTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	// Unblock any pending read or write as soon as ctx is cancelled
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, s.smtp.Host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.smtp.Host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return fmt.Errorf("smtp server doesn't support AUTH")
+		}
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(s.smtp.From); err != nil {
+		return err
+	}
+	for _, rcpt := range recipients {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
 // CheckAndSend checks if any alerts should be triggered for a new opportunity
 func (s *AlertService) CheckAndSend(ctx context.Context, payload AlertPayload) error {
 	alerts, err := s.GetAlerts()
@@ -0,0 +1,229 @@
+package alerts
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTP is a mail server that accepts one message per connection and records
+// the commands and message it received
+type fakeSMTP struct {
+	ln       net.Listener
+	commands chan string
+	messages chan string
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	s := &fakeSMTP{ln: ln, commands: make(chan string, 100), messages: make(chan string, 10)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+
+	tp.PrintfLine("220 fake ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		s.commands <- line
+
+		switch verb := strings.ToUpper(strings.Fields(line)[0]); verb {
+		case "EHLO", "HELO", "MAIL", "RCPT":
+			tp.PrintfLine("250 OK")
+		case "DATA":
+			tp.PrintfLine("354 Go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.messages <- string(data)
+			tp.PrintfLine("250 Queued")
+		case "QUIT":
+			tp.PrintfLine("221 Bye")
+			return
+		default:
+			tp.PrintfLine("502 Unknown command")
+		}
+	}
+}
+
+func (s *fakeSMTP) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestSendReportEmail(t *testing.T) {
+	server := newFakeSMTP(t)
+
+	service := NewAlertService(nil)
+	service.UseSMTP(SMTPConfig{Host: "127.0.0.1", Port: server.port(), From: "seer@example.com"})
+
+	alert := Alert{Type: AlertTypeEmail, Destination: "ana@example.com, bo@example.com"}
+	payload := ReportPayload{
+		Title:            "Seer report: Weekly",
+		PeriodStart:      time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:        time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC),
+		OpportunityCount: 12,
+		Summary:          "Invoicing keeps coming up.",
+		Top:              []AlertPayload{{Title: "Invoicing pain", Score: 90, Source: "hackernews", URL: "https://example.com/1"}},
+		URL:              "http://seer.test/api/prompts/3/export?format=html",
+	}
+	if err := service.SendReport(context.Background(), alert, payload); err != nil {
+		t.Fatalf("SendReport() error = %v", err)
+	}
+
+	var commands []string
+	for len(server.commands) > 0 {
+		commands = append(commands, <-server.commands)
+	}
+	joined := strings.Join(commands, "\n")
+	for _, want := range []string{"MAIL FROM:<seer@example.com>", "RCPT TO:<ana@example.com>", "RCPT TO:<bo@example.com>", "DATA", "QUIT"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected command %q, got %q", want, joined)
+		}
+	}
+
+	message := <-server.messages
+	for _, want := range []string{
+		"From: seer@example.com\n",
+		"To: ana@example.com, bo@example.com\n",
+		"Subject: Seer report: Weekly\n",
+		"Content-Type: text/plain; charset=utf-8\n",
+		"2026-03-02 to 2026-03-09 | 12 opportunities\n",
+		"1. Invoicing pain (score 90, hackernews)\n   https://example.com/1\n",
+		"Invoicing keeps coming up.\n",
+		"Open the report: http://seer.test/api/prompts/3/export?format=html\n",
+	} {
+		if !strings.Contains(message, want) {
+			t.Errorf("expected message to contain %q, got:\n%s", want, message)
+		}
+	}
+}
+
+func TestSendEmailEncodesSubject(t *testing.T) {
+	server := newFakeSMTP(t)
+
+	service := NewAlertService(nil)
+	service.UseSMTP(SMTPConfig{Host: "127.0.0.1", Port: server.port(), From: "seer@example.com"})
+
+	payload := AlertPayload{Title: "Café invoicing → done", Score: 80, Source: "devto", URL: "https://example.com/2"}
+	if err := service.Send(context.Background(), Alert{Type: AlertTypeEmail, Destination: "ana@example.com"}, payload); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	message := <-server.messages
+	if !strings.Contains(message, "Subject: =?utf-8?q?") || !strings.Contains(message, "Score: 80 | Source: devto\n") {
+		t.Errorf("expected an encoded subject and the alert body, got:\n%s", message)
+	}
+}
+
+func TestSendEmailStalledServer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	// Accept connections but never greet
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				bufio.NewReader(conn).ReadString('\n')
+			}()
+		}
+	}()
+
+	service := NewAlertService(nil)
+	service.UseSMTP(SMTPConfig{Host: "127.0.0.1", Port: ln.Addr().(*net.TCPAddr).Port, From: "seer@example.com"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	err = service.Send(ctx, Alert{Type: AlertTypeEmail, Destination: "ana@example.com"}, AlertPayload{Title: "Stuck"})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the cancelled context as the error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected to give up once cancelled, took %s", elapsed)
+	}
+}
+
+func TestSendEmailRequiresSMTP(t *testing.T) {
+	service := NewAlertService(nil)
+	if err := service.Send(context.Background(), Alert{Type: AlertTypeEmail, Destination: "ana@example.com"}, AlertPayload{}); err == nil {
+		t.Error("expected an error without an SMTP host")
+	}
+
+	service.UseSMTP(SMTPConfig{Host: "127.0.0.1", From: "seer@example.com"})
+	if err := service.Send(context.Background(), Alert{Type: AlertTypeEmail, Destination: " , "}, AlertPayload{}); err == nil {
+		t.Error("expected an error without recipients")
+	}
+}
+
+func TestSendReportSlack(t *testing.T) {
+	var text string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Text string `json:"text"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		text = body.Text
+	}))
+	defer server.Close()
+
+	payload := ReportPayload{
+		Title:            "Seer report: Weekly",
+		PeriodStart:      time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:        time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC),
+		OpportunityCount: 1,
+		Top:              []AlertPayload{{Title: "Invoicing pain", Score: 90, Source: "hackernews", URL: "https://example.com/1"}},
+		URL:              "http://seer.test/api/prompts/3/export?format=html",
+	}
+	if err := NewAlertService(nil).SendReport(context.Background(), Alert{Type: AlertTypeSlack, Destination: server.URL}, payload); err != nil {
+		t.Fatalf("SendReport() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"*Seer report: Weekly*\n2026-03-02 to 2026-03-09 | 1 opportunities",
+		"• <https://example.com/1|Invoicing pain> (score 90, hackernews)",
+		"<http://seer.test/api/prompts/3/export?format=html|Open report →>",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected Slack text to contain %q, got %q", want, text)
+		}
+	}
+}
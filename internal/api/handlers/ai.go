@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mx-seer/seer/internal/ai"
+)
+
+// AIProviderResponse represents stored AI provider settings in API responses
+type AIProviderResponse struct {
+	Type      string            `json:"type"`
+	BaseURL   string            `json:"base_url,omitempty"`
+	Model     string            `json:"model,omitempty"`
+	Options   map[string]string `json:"options,omitempty"`
+	IsDefault bool              `json:"is_default"`
+	APIKeySet bool              `json:"api_key_set"`
+	APIKey    string            `json:"api_key,omitempty"` // Masked
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// AIProviderRequest represents the request body for saving AI provider settings
+type AIProviderRequest struct {
+	APIKey    string            `json:"api_key"`
+	BaseURL   string            `json:"base_url"`
+	Model     string            `json:"model"`
+	Options   map[string]string `json:"options"`
+	IsDefault bool              `json:"is_default"`
+}
+
+func toAIProviderResponse(st *ai.Settings) AIProviderResponse {
+	return AIProviderResponse{
+		Type:      st.Type,
+		BaseURL:   st.BaseURL,
+		Model:     st.Model,
+		Options:   st.Options,
+		IsDefault: st.IsDefault,
+		APIKeySet: st.APIKey != "",
+		APIKey:    ai.MaskKey(st.APIKey),
+		UpdatedAt: st.UpdatedAt,
+	}
+}
+
+// AIHandler handles AI provider settings requests
+type AIHandler struct {
+	store *ai.Store
+}
+
+// NewAIHandler creates a new AI handler
+func NewAIHandler(store *ai.Store) *AIHandler {
+	return &AIHandler{store: store}
+}
+
+// ListProviders returns the available provider types and the configured ones
+func (h *AIHandler) ListProviders(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.store.List()
+	if err != nil {
+		http.Error(w, "Failed to load AI providers", http.StatusInternalServerError)
+		return
+	}
+
+	configured := make([]AIProviderResponse, len(settings))
+	for i := range settings {
+		configured[i] = toAIProviderResponse(&settings[i])
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"available":  ai.AvailableProviders(),
+		"configured": configured,
+	})
+}
+
+// GetProvider returns the settings of a single provider
+func (h *AIHandler) GetProvider(w http.ResponseWriter, r *http.Request) {
+	st, err := h.store.Get(r.PathValue("type"))
+	if err != nil {
+		http.Error(w, "Failed to load AI provider", http.StatusInternalServerError)
+		return
+	}
+	if st == nil {
+		http.Error(w, "AI provider not configured", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toAIProviderResponse(st))
+}
+
+// SaveProvider creates or updates the settings of a provider
+func (h *AIHandler) SaveProvider(w http.ResponseWriter, r *http.Request) {
+	var req AIProviderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	st := &ai.Settings{
+		Type:      r.PathValue("type"),
+		APIKey:    req.APIKey,
+		BaseURL:   req.BaseURL,
+		Model:     req.Model,
+		Options:   req.Options,
+		IsDefault: req.IsDefault,
+	}
+
+	if err := h.store.Save(st); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toAIProviderResponse(st))
+}
+
+// DeleteProvider removes the settings of a provider
+func (h *AIHandler) DeleteProvider(w http.ResponseWriter, r *http.Request) {
+	err := h.store.Delete(r.PathValue("type"))
+	if errors.Is(err, ai.ErrNotConfigured) {
+		http.Error(w, "AI provider not configured", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to delete AI provider", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// providerCheckTimeout bounds connectivity tests and model listings
+const providerCheckTimeout = 20 * time.Second
+
+// AIProviderTestResult reports the outcome of a minimal call to a provider
+type AIProviderTestResult struct {
+	OK         bool   `json:"ok"`
+	LatencyMS  int64  `json:"latency_ms"`
+	Model      string `json:"model,omitempty"`
+	Reply      string `json:"reply,omitempty"`
+	Error      string `json:"error,omitempty"`
+	ErrorKind  string `json:"error_kind,omitempty"` // retryable, auth, quota or invalid
+	StatusCode int    `json:"status_code,omitempty"`
+}
+
+// TestProvider makes a minimal call to a provider and reports latency and any error.
+// An optional body with unsaved settings is tried in place of the stored ones.
+func (h *AIHandler) TestProvider(w http.ResponseWriter, r *http.Request) {
+	var override *ai.Settings
+	var req AIProviderRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && err != io.EOF {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err == nil {
+		override = &ai.Settings{APIKey: req.APIKey, BaseURL: req.BaseURL, Model: req.Model, Options: req.Options}
+	}
+
+	provider, st, ok := h.direct(w, r.PathValue("type"), override)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ai.WithPurpose(r.Context(), "test"), providerCheckTimeout)
+	defer cancel()
+
+	chat := ai.Prompt("Reply with the single word OK.")
+	chat.MaxTokens = 16
+
+	start := time.Now()
+	resp, err := h.store.Meter().Wrap(provider).Chat(ctx, chat)
+	result := AIProviderTestResult{LatencyMS: time.Since(start).Milliseconds(), Model: st.Model}
+
+	if err != nil {
+		result.Error = err.Error()
+		result.ErrorKind = string(ai.KindOf(err))
+		var aiErr *ai.Error
+		if errors.As(err, &aiErr) {
+			result.StatusCode = aiErr.StatusCode
+		}
+	} else {
+		result.OK = true
+		result.Reply = resp.Content
+		if resp.Usage.Model != "" {
+			result.Model = resp.Usage.Model
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// ListModels returns the models offered by a configured provider
+func (h *AIHandler) ListModels(w http.ResponseWriter, r *http.Request) {
+	provider, _, ok := h.direct(w, r.PathValue("type"), nil)
+	if !ok {
+		return
+	}
+
+	lister, ok := provider.(ai.ModelLister)
+	if !ok {
+		http.Error(w, "Provider does not list models", http.StatusNotImplemented)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), providerCheckTimeout)
+	defer cancel()
+
+	models, err := lister.Models(ctx)
+	if err != nil {
+		http.Error(w, "Failed to list models: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"models": models})
+}
+
+// direct creates a provider on its own, writing an error response if it is unusable
+func (h *AIHandler) direct(w http.ResponseWriter, providerType string, override *ai.Settings) (ai.Provider, *ai.Settings, bool) {
+	provider, st, err := h.store.Direct(providerType, override)
+	if errors.Is(err, ai.ErrNotConfigured) {
+		http.Error(w, "AI provider not configured", http.StatusNotFound)
+		return nil, nil, false
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, nil, false
+	}
+	return provider, st, true
+}
+
+// AIFallbackRequest is the order in which providers are tried when the requested one fails
+type AIFallbackRequest struct {
+	Providers []string `json:"providers"`
+}
+
+// GetFallback returns the provider fallback order
+func (h *AIHandler) GetFallback(w http.ResponseWriter, r *http.Request) {
+	providers, err := h.store.Fallback()
+	if err != nil {
+		http.Error(w, "Failed to load AI fallback order", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(AIFallbackRequest{Providers: providers})
+}
+
+// SetFallback replaces the provider fallback order
+func (h *AIHandler) SetFallback(w http.ResponseWriter, r *http.Request) {
+	var req AIFallbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.store.SetFallback(req.Providers); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.GetFallback(w, r)
+}
+
+// Usage returns AI spend against the monthly cap with daily and monthly totals
+func (h *AIHandler) Usage(w http.ResponseWriter, r *http.Request) {
+	days, months := 30, 12
+	if v, err := strconv.Atoi(r.URL.Query().Get("days")); err == nil && v > 0 && v <= 366 {
+		days = v
+	}
+	if v, err := strconv.Atoi(r.URL.Query().Get("months")); err == nil && v > 0 && v <= 36 {
+		months = v
+	}
+
+	summary, err := h.store.Meter().Summary(days, months)
+	if err != nil {
+		http.Error(w, "Failed to load AI usage", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
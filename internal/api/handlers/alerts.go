@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mx-seer/seer/internal/alerts"
+)
+
+// AlertsHandler handles alert channel requests
+type AlertsHandler struct {
+	service *alerts.AlertService
+}
+
+// NewAlertsHandler creates a new alerts handler
+func NewAlertsHandler(service *alerts.AlertService) *AlertsHandler {
+	return &AlertsHandler{service: service}
+}
+
+// List returns all alerts
+func (h *AlertsHandler) List(w http.ResponseWriter, r *http.Request) {
+	list, err := h.service.GetAlerts()
+	if err != nil {
+		http.Error(w, "Failed to get alerts", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// Create creates a new alert
+func (h *AlertsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var alert alerts.Alert
+	if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	alert.Name = strings.TrimSpace(alert.Name)
+	alert.Destination = strings.TrimSpace(alert.Destination)
+	switch alert.Type {
+	case alerts.AlertTypeEmail, alerts.AlertTypeWebhook, alerts.AlertTypeSlack:
+	default:
+		http.Error(w, "Type must be email, webhook or slack", http.StatusBadRequest)
+		return
+	}
+	if alert.Name == "" || alert.Destination == "" {
+		http.Error(w, "Name and destination are required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.CreateAlert(&alert); err != nil {
+		http.Error(w, "Failed to create alert", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(alert)
+}
+
+// Delete deletes an alert
+func (h *AlertsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteAlert(id); err != nil {
+		http.Error(w, "Failed to delete alert", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Toggle enables or disables an alert
+func (h *AlertsHandler) Toggle(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.ToggleAlert(id); err != nil {
+		http.Error(w, "Failed to toggle alert", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ClusterMember is an opportunity belonging to a cluster
+type ClusterMember struct {
+	ID         int64     `json:"id"`
+	Title      string    `json:"title"`
+	SourceType string    `json:"source_type"`
+	SourceURL  string    `json:"source_url"`
+	Score      int       `json:"score"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// ClusterResponse represents a group of opportunities about the same thing
+type ClusterResponse struct {
+	ID      int64           `json:"id"`
+	Size    int             `json:"size"`
+	Sources []string        `json:"sources"`
+	Best    ClusterMember   `json:"best"`
+	Members []ClusterMember `json:"members"`
+}
+
+// ClustersHandler handles cluster-related requests
+type ClustersHandler struct {
+	db *sql.DB
+}
+
+// NewClustersHandler creates a new clusters handler
+func NewClustersHandler(db *sql.DB) *ClustersHandler {
+	return &ClustersHandler{db: db}
+}
+
+// List returns clusters with more than one member, best clusters first
+func (h *ClustersHandler) List(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 && v <= 500 {
+		limit = v
+	}
+
+	offset := 0
+	if v, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && v >= 0 {
+		offset = v
+	}
+
+	minSize := 2
+	if v, err := strconv.Atoi(r.URL.Query().Get("min_size")); err == nil && v > 0 {
+		minSize = v
+	}
+
+	rows, err := h.db.Query(`
+		SELECT cluster_id
+		FROM opportunities
+		WHERE cluster_id IS NOT NULL
+		GROUP BY cluster_id
+		HAVING COUNT(*) >= ?
+		ORDER BY MAX(score) DESC, COUNT(*) DESC
+		LIMIT ? OFFSET ?
+	`, minSize, limit, offset)
+	if err != nil {
+		http.Error(w, "Failed to query clusters", http.StatusInternalServerError)
+		return
+	}
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if rows.Scan(&id) == nil {
+			ids = append(ids, id)
+		}
+	}
+	rows.Close()
+
+	clusters := []ClusterResponse{}
+	for _, id := range ids {
+		cluster, err := h.load(id)
+		if err != nil {
+			http.Error(w, "Failed to load cluster", http.StatusInternalServerError)
+			return
+		}
+		if cluster != nil {
+			clusters = append(clusters, *cluster)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(clusters)
+}
+
+// Get returns a single cluster by ID
+func (h *ClustersHandler) Get(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	cluster, err := h.load(id)
+	if err != nil {
+		http.Error(w, "Failed to load cluster", http.StatusInternalServerError)
+		return
+	}
+	if cluster == nil {
+		http.Error(w, "Cluster not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cluster)
+}
+
+// load reads a cluster and its members, best-scored first
+func (h *ClustersHandler) load(id int64) (*ClusterResponse, error) {
+	rows, err := h.db.Query(`
+		SELECT id, title, source, source_url, score, detected_at
+		FROM opportunities
+		WHERE cluster_id = ?
+		ORDER BY score DESC, detected_at DESC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cluster := &ClusterResponse{ID: id, Sources: []string{}}
+	seenSources := make(map[string]bool)
+	for rows.Next() {
+		var m ClusterMember
+		var sourceURL sql.NullString
+		if err := rows.Scan(&m.ID, &m.Title, &m.SourceType, &sourceURL, &m.Score, &m.DetectedAt); err != nil {
+			return nil, err
+		}
+		m.SourceURL = sourceURL.String
+
+		if !seenSources[m.SourceType] {
+			seenSources[m.SourceType] = true
+			cluster.Sources = append(cluster.Sources, m.SourceType)
+		}
+		cluster.Members = append(cluster.Members, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(cluster.Members) == 0 {
+		return nil, nil
+	}
+
+	cluster.Size = len(cluster.Members)
+	cluster.Best = cluster.Members[0]
+
+	return cluster, nil
+}
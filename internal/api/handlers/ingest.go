@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/mx-seer/seer/internal/sources"
+)
+
+// ingestMaxBody bounds the size of a webhook delivery
+const ingestMaxBody = 5 << 20
+
+// IngestHandler receives opportunities pushed to webhook sources
+type IngestHandler struct {
+	repo    *sources.Repository
+	manager *sources.Manager
+}
+
+// NewIngestHandler creates a new ingest handler
+func NewIngestHandler(db *sql.DB, manager *sources.Manager) *IngestHandler {
+	return &IngestHandler{
+		repo:    sources.NewRepository(db),
+		manager: manager,
+	}
+}
+
+// Ingest stores a single opportunity or a batch delivered to a webhook source,
+// responding with the recorded fetch run
+func (h *IngestHandler) Ingest(w http.ResponseWriter, r *http.Request) {
+	if h.manager == nil {
+		http.Error(w, "Source manager not available", http.StatusInternalServerError)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("source_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	rec, err := h.repo.GetByID(id)
+	if err != nil {
+		http.Error(w, "Failed to get source", http.StatusInternalServerError)
+		return
+	}
+	if rec == nil || rec.Type != sources.WebhookType {
+		http.Error(w, "Webhook source not found", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, ingestMaxBody))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	// Authenticate before anything about the source's state is revealed
+	webhook, err := sources.NewWebhook(rec.ToConfig())
+	if err != nil {
+		http.Error(w, "Webhook source has no token or secret", http.StatusForbidden)
+		return
+	}
+	if err := webhook.Verify(r.Header, body); err != nil {
+		http.Error(w, "Invalid token or signature", http.StatusUnauthorized)
+		return
+	}
+
+	if !rec.Enabled {
+		http.Error(w, "Source is disabled", http.StatusConflict)
+		return
+	}
+
+	opportunities, err := sources.DecodeWebhookPayload(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	run, err := h.manager.Ingest(*rec, opportunities)
+	if err != nil {
+		log.Printf("Failed to ingest into %s: %v", rec.Name, err)
+		http.Error(w, "Failed to ingest opportunities", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(run)
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mx-seer/seer/internal/sources"
+)
+
+// KeywordsPreviewResponse describes the effect of a proposed keywords configuration
+type KeywordsPreviewResponse struct {
+	Days int `json:"days"`
+	sources.KeywordsPreview
+}
+
+// KeywordsHandler handles custom keyword configuration
+type KeywordsHandler struct {
+	db   *sql.DB
+	repo *sources.KeywordsRepository
+}
+
+// NewKeywordsHandler creates a new keywords handler
+func NewKeywordsHandler(db *sql.DB) *KeywordsHandler {
+	return &KeywordsHandler{
+		db:   db,
+		repo: sources.NewKeywordsRepository(db),
+	}
+}
+
+// Get returns the current keywords configuration
+func (h *KeywordsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	config, err := h.repo.GetKeywords()
+	if err != nil {
+		http.Error(w, "Failed to get keywords", http.StatusInternalServerError)
+		return
+	}
+
+	// Normalize so clients always receive arrays rather than null
+	config.Normalize()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(config)
+}
+
+// Update validates and replaces the keywords configuration
+func (h *KeywordsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var config sources.KeywordsConfig
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := config.Normalize(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.SaveKeywords(&config); err != nil {
+		http.Error(w, "Failed to save keywords", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(config)
+}
+
+// Preview reports how many recent opportunities a proposed configuration would keep, drop or boost
+func (h *KeywordsHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	days := 7
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		if v, err := strconv.Atoi(daysStr); err == nil && v > 0 && v <= 365 {
+			days = v
+		}
+	}
+
+	var config sources.KeywordsConfig
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := config.Normalize(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	since := time.Now().AddDate(0, 0, -days).UTC().Format(time.RFC3339)
+	rows, err := h.db.Query(`
+		SELECT title, description, source
+		FROM opportunities
+		WHERE detected_at >= ?
+	`, since)
+	if err != nil {
+		http.Error(w, "Failed to query opportunities", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	var opportunities []sources.Opportunity
+	for rows.Next() {
+		var opp sources.Opportunity
+		var description sql.NullString
+		if err := rows.Scan(&opp.Title, &description, &opp.SourceType); err != nil {
+			continue
+		}
+		opp.Description = description.String
+		opportunities = append(opportunities, opp)
+	}
+
+	response := KeywordsPreviewResponse{
+		Days:            days,
+		KeywordsPreview: sources.PreviewKeywords(opportunities, &config),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
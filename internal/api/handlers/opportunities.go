@@ -5,21 +5,112 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/mx-seer/seer/internal/enrich"
+	"github.com/mx-seer/seer/internal/scoring"
 )
 
 // OpportunityResponse represents an opportunity in API responses
 type OpportunityResponse struct {
-	ID               int64     `json:"id"`
-	Title            string    `json:"title"`
-	Description      string    `json:"description"`
-	SourceType       string    `json:"source_type"`
-	SourceURL        string    `json:"source_url"`
-	SourceIDExternal string    `json:"source_id_external"`
-	Score            int       `json:"score"`
-	Signals          []string  `json:"signals"`
-	DetectedAt       time.Time `json:"detected_at"`
-	CreatedAt        time.Time `json:"created_at"`
+	ID               int64           `json:"id"`
+	Title            string          `json:"title"`
+	Description      string          `json:"description"`
+	SourceType       string          `json:"source_type"`
+	SourceURL        string          `json:"source_url"`
+	SourceIDExternal string          `json:"source_id_external"`
+	Score            int             `json:"score"`
+	Signals          []string        `json:"signals"`
+	KeywordBoost     int             `json:"keyword_boost"`
+	Metadata         map[string]any  `json:"metadata,omitempty"`
+	Feedback         string          `json:"feedback,omitempty"`
+	Watchlists       []string        `json:"watchlists"`
+	Topics           []string        `json:"topics"`
+	ClusterID        int64           `json:"cluster_id,omitempty"`
+	ClusterSize      int             `json:"cluster_size,omitempty"`
+	AIStatus         string          `json:"ai_status,omitempty"`
+	AIAnalysis       *enrich.Verdict `json:"ai_analysis,omitempty"`
+	AIProvider       string          `json:"ai_provider,omitempty"`
+	AIError          string          `json:"ai_error,omitempty"`
+	DetectedAt       time.Time       `json:"detected_at"`
+	CreatedAt        time.Time       `json:"created_at"`
+}
+
+// opportunityColumns is the column list read by scanOpportunity
+const opportunityColumns = `
+	o.id, o.title, o.description, o.source, o.source_url, o.source_id_external,
+	o.score, o.signals, COALESCE(o.keyword_boost, 0), o.metadata, f.label,
+	(SELECT json_group_array(w.name) FROM opportunity_watchlists ow
+		JOIN watchlists w ON w.id = ow.watchlist_id
+		WHERE ow.opportunity_id = o.id) AS watchlists,
+	(SELECT json_group_array(topic) FROM (
+		SELECT ot.topic FROM opportunity_topics ot
+		WHERE ot.opportunity_id = o.id ORDER BY ot.weight DESC
+	)) AS topics,
+	o.cluster_id,
+	(SELECT COUNT(*) FROM opportunities c WHERE c.cluster_id = o.cluster_id) AS cluster_size,
+	o.ai_status, o.ai_analysis, o.ai_provider, o.ai_error,
+	o.detected_at, o.created_at
+`
+
+// watchlistFilter restricts a query to opportunities tagged with a watchlist given by ID or name
+func watchlistFilter(idColumn, watchlist string) (string, []any) {
+	clause := " AND " + idColumn + ` IN (
+		SELECT ow.opportunity_id FROM opportunity_watchlists ow
+		JOIN watchlists w ON w.id = ow.watchlist_id
+		WHERE w.name = ? OR CAST(w.id AS TEXT) = ?
+	)`
+	return clause, []any{watchlist, watchlist}
+}
+
+// scanOpportunity scans a row selected with opportunityColumns
+func scanOpportunity(row interface{ Scan(...any) error }) (OpportunityResponse, error) {
+	var opp OpportunityResponse
+	var signalsJSON, watchlistsJSON, topicsJSON string
+	var description, sourceURL, metadataJSON, feedback sql.NullString
+	var clusterID sql.NullInt64
+	var aiStatus, aiAnalysis, aiProvider, aiError sql.NullString
+
+	err := row.Scan(
+		&opp.ID, &opp.Title, &description, &opp.SourceType,
+		&sourceURL, &opp.SourceIDExternal, &opp.Score,
+		&signalsJSON, &opp.KeywordBoost, &metadataJSON, &feedback, &watchlistsJSON, &topicsJSON,
+		&clusterID, &opp.ClusterSize,
+		&aiStatus, &aiAnalysis, &aiProvider, &aiError,
+		&opp.DetectedAt, &opp.CreatedAt,
+	)
+	if err != nil {
+		return opp, err
+	}
+
+	opp.Description = description.String
+	opp.SourceURL = sourceURL.String
+	opp.Feedback = feedback.String
+	opp.ClusterID = clusterID.Int64
+	opp.AIStatus = aiStatus.String
+	opp.AIProvider = aiProvider.String
+	opp.AIError = aiError.String
+
+	// Parse signals JSON
+	json.Unmarshal([]byte(signalsJSON), &opp.Signals)
+	if opp.Signals == nil {
+		opp.Signals = []string{}
+	}
+	json.Unmarshal([]byte(metadataJSON.String), &opp.Metadata)
+	json.Unmarshal([]byte(watchlistsJSON), &opp.Watchlists)
+	if opp.Watchlists == nil {
+		opp.Watchlists = []string{}
+	}
+	if aiAnalysis.String != "" {
+		json.Unmarshal([]byte(aiAnalysis.String), &opp.AIAnalysis)
+	}
+	json.Unmarshal([]byte(topicsJSON), &opp.Topics)
+	if opp.Topics == nil {
+		opp.Topics = []string{}
+	}
+
+	return opp, nil
 }
 
 // OpportunitiesHandler handles opportunity-related requests
@@ -39,6 +130,10 @@ func (h *OpportunitiesHandler) List(w http.ResponseWriter, r *http.Request) {
 	minScoreStr := r.URL.Query().Get("min_score")
 	limitStr := r.URL.Query().Get("limit")
 	offsetStr := r.URL.Query().Get("offset")
+	feedback := r.URL.Query().Get("feedback")
+	watchlist := r.URL.Query().Get("watchlist")
+	topic := r.URL.Query().Get("topic")
+	collapse := r.URL.Query().Get("collapse") == "true"
 
 	minScore := 0
 	if minScoreStr != "" {
@@ -61,20 +156,60 @@ func (h *OpportunitiesHandler) List(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Build query
-	query := `
-		SELECT id, title, description, source, source_url, source_id_external, score, signals, detected_at, created_at
-		FROM opportunities
-		WHERE score >= ?
-	`
-	args := []any{minScore}
+	// Build filters
+	filters := "o.score >= ?"
+	filterArgs := []any{minScore}
 
 	if sourceType != "" {
-		query += " AND source = ?"
-		args = append(args, sourceType)
+		filters += " AND o.source = ?"
+		filterArgs = append(filterArgs, sourceType)
+	}
+
+	if watchlist != "" {
+		clause, clauseArgs := watchlistFilter("o.id", watchlist)
+		filters += clause
+		filterArgs = append(filterArgs, clauseArgs...)
+	}
+
+	if topic != "" {
+		filters += " AND o.id IN (SELECT opportunity_id FROM opportunity_topics WHERE topic = ?)"
+		filterArgs = append(filterArgs, strings.ToLower(topic))
+	}
+
+	switch feedback {
+	case "":
+	case "none":
+		filters += " AND f.label IS NULL"
+	default:
+		filters += " AND f.label = ?"
+		filterArgs = append(filterArgs, feedback)
+	}
+
+	query := `
+		SELECT ` + opportunityColumns + `
+		FROM opportunities o
+		LEFT JOIN opportunity_feedback f ON f.opportunity_id = o.id
+		WHERE ` + filters
+	args := append([]any{}, filterArgs...)
+
+	// Keep only the best-scored member of each cluster among the filtered rows
+	if collapse {
+		query += `
+		AND o.id IN (
+			SELECT id FROM (
+				SELECT o.id, ROW_NUMBER() OVER (
+					PARTITION BY COALESCE(o.cluster_id, -o.id)
+					ORDER BY o.score DESC, o.detected_at DESC
+				) AS position
+				FROM opportunities o
+				LEFT JOIN opportunity_feedback f ON f.opportunity_id = o.id
+				WHERE ` + filters + `
+			) WHERE position = 1
+		)`
+		args = append(args, filterArgs...)
 	}
 
-	query += " ORDER BY score DESC, detected_at DESC LIMIT ? OFFSET ?"
+	query += " ORDER BY o.score DESC, o.detected_at DESC LIMIT ? OFFSET ?"
 	args = append(args, limit, offset)
 
 	rows, err := h.db.Query(query, args...)
@@ -86,28 +221,11 @@ func (h *OpportunitiesHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	var opportunities []OpportunityResponse
 	for rows.Next() {
-		var opp OpportunityResponse
-		var signalsJSON string
-		var description, sourceURL sql.NullString
-
-		err := rows.Scan(
-			&opp.ID, &opp.Title, &description, &opp.SourceType,
-			&sourceURL, &opp.SourceIDExternal, &opp.Score,
-			&signalsJSON, &opp.DetectedAt, &opp.CreatedAt,
-		)
+		opp, err := scanOpportunity(rows)
 		if err != nil {
 			continue
 		}
 
-		opp.Description = description.String
-		opp.SourceURL = sourceURL.String
-
-		// Parse signals JSON
-		json.Unmarshal([]byte(signalsJSON), &opp.Signals)
-		if opp.Signals == nil {
-			opp.Signals = []string{}
-		}
-
 		opportunities = append(opportunities, opp)
 	}
 
@@ -128,19 +246,12 @@ func (h *OpportunitiesHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var opp OpportunityResponse
-	var signalsJSON string
-	var description, sourceURL sql.NullString
-
-	err = h.db.QueryRow(`
-		SELECT id, title, description, source, source_url, source_id_external, score, signals, detected_at, created_at
-		FROM opportunities
-		WHERE id = ?
-	`, id).Scan(
-		&opp.ID, &opp.Title, &description, &opp.SourceType,
-		&sourceURL, &opp.SourceIDExternal, &opp.Score,
-		&signalsJSON, &opp.DetectedAt, &opp.CreatedAt,
-	)
+	opp, err := scanOpportunity(h.db.QueryRow(`
+		SELECT `+opportunityColumns+`
+		FROM opportunities o
+		LEFT JOIN opportunity_feedback f ON f.opportunity_id = o.id
+		WHERE o.id = ?
+	`, id))
 
 	if err == sql.ErrNoRows {
 		http.Error(w, "Opportunity not found", http.StatusNotFound)
@@ -151,13 +262,6 @@ func (h *OpportunitiesHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	opp.Description = description.String
-	opp.SourceURL = sourceURL.String
-	json.Unmarshal([]byte(signalsJSON), &opp.Signals)
-	if opp.Signals == nil {
-		opp.Signals = []string{}
-	}
-
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(opp)
 }
@@ -167,6 +271,7 @@ func (h *OpportunitiesHandler) Stats(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters for filtering
 	sourceType := r.URL.Query().Get("source")
 	minScoreStr := r.URL.Query().Get("min_score")
+	watchlist := r.URL.Query().Get("watchlist")
 
 	minScore := 0
 	if minScoreStr != "" {
@@ -178,10 +283,12 @@ func (h *OpportunitiesHandler) Stats(w http.ResponseWriter, r *http.Request) {
 	stats := struct {
 		Total        int            `json:"total"`
 		BySource     map[string]int `json:"by_source"`
+		ByWatchlist  map[string]int `json:"by_watchlist"`
 		AverageScore float64        `json:"average_score"`
 		Today        int            `json:"today"`
 	}{
-		BySource: make(map[string]int),
+		BySource:    make(map[string]int),
+		ByWatchlist: make(map[string]int),
 	}
 
 	// Build WHERE clause
@@ -193,6 +300,12 @@ func (h *OpportunitiesHandler) Stats(w http.ResponseWriter, r *http.Request) {
 		args = append(args, sourceType)
 	}
 
+	if watchlist != "" {
+		clause, clauseArgs := watchlistFilter("opportunities.id", watchlist)
+		whereClause += clause
+		args = append(args, clauseArgs...)
+	}
+
 	// Total count (filtered)
 	h.db.QueryRow("SELECT COUNT(*) FROM opportunities "+whereClause, args...).Scan(&stats.Total)
 
@@ -215,6 +328,89 @@ func (h *OpportunitiesHandler) Stats(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// By watchlist (filtered)
+	wlRows, err := h.db.Query(`
+		SELECT w.name, COUNT(*) FROM opportunities
+		JOIN opportunity_watchlists ow ON ow.opportunity_id = opportunities.id
+		JOIN watchlists w ON w.id = ow.watchlist_id
+		`+whereClause+`
+		GROUP BY w.name`, args...)
+	if err == nil {
+		defer wlRows.Close()
+		for wlRows.Next() {
+			var name string
+			var count int
+			if wlRows.Scan(&name, &count) == nil {
+				stats.ByWatchlist[name] = count
+			}
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(stats)
 }
+
+// FeedbackRequest represents a triage label for an opportunity
+type FeedbackRequest struct {
+	Label string `json:"label"`
+}
+
+// SetFeedback records whether an opportunity was saved or dismissed
+func (h *OpportunitiesHandler) SetFeedback(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var req FeedbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Label != scoring.LabelSaved && req.Label != scoring.LabelDismissed {
+		http.Error(w, "label must be 'saved' or 'dismissed'", http.StatusBadRequest)
+		return
+	}
+
+	var exists bool
+	h.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM opportunities WHERE id = ?)`, id).Scan(&exists)
+	if !exists {
+		http.Error(w, "Opportunity not found", http.StatusNotFound)
+		return
+	}
+
+	_, err = h.db.Exec(`
+		INSERT INTO opportunity_feedback (opportunity_id, label) VALUES (?, ?)
+		ON CONFLICT(opportunity_id) DO UPDATE SET label = excluded.label, created_at = CURRENT_TIMESTAMP
+	`, id, req.Label)
+	if err != nil {
+		http.Error(w, "Failed to save feedback", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"opportunity_id": id,
+		"label":          req.Label,
+	})
+}
+
+// ClearFeedback removes the triage label from an opportunity
+func (h *OpportunitiesHandler) ClearFeedback(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.db.Exec(`DELETE FROM opportunity_feedback WHERE opportunity_id = ?`, id); err != nil {
+		http.Error(w, "Failed to clear feedback", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
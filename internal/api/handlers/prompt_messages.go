@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mx-seer/seer/internal/ai"
+)
+
+// reportChatSystemPrompt frames follow-up questions about a report
+const reportChatSystemPrompt = "You are a product analyst helping evaluate market opportunities collected by Seer. " +
+	"The conversation starts with a report and your analysis of it. Answer follow-up questions using the report, " +
+	"and say so when the report does not contain the answer."
+
+// ReportMessage represents a turn in a follow-up conversation about a report
+type ReportMessage struct {
+	ID         int64     `json:"id"`
+	Role       string    `json:"role"`
+	Content    string    `json:"content"`
+	AIProvider string    `json:"ai_provider,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SendMessageRequest represents a follow-up question about a report
+type SendMessageRequest struct {
+	Content string `json:"content"`
+	AIOptions
+}
+
+// Messages returns the follow-up conversation about a report
+func (h *PromptsHandler) Messages(w http.ResponseWriter, r *http.Request) {
+	id, err := json.Number(r.PathValue("id")).Int64()
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	messages, err := h.messages(id)
+	if err != nil {
+		http.Error(w, "Failed to query messages", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(messages)
+}
+
+// SendMessage asks a follow-up question about an analyzed report and stores both the
+// question and the answer. ?stream=true streams the answer as server-sent events.
+func (h *PromptsHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
+	id, err := json.Number(r.PathValue("id")).Int64()
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var req SendMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	req.Content = strings.TrimSpace(req.Content)
+	if req.Content == "" {
+		http.Error(w, "content is required", http.StatusBadRequest)
+		return
+	}
+
+	p, err := h.load(id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Prompt not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get prompt", http.StatusInternalServerError)
+		return
+	}
+	if p.AIAnalysis == "" {
+		http.Error(w, "Analyze the report before asking follow-up questions", http.StatusConflict)
+		return
+	}
+
+	history, err := h.messages(id)
+	if err != nil {
+		http.Error(w, "Failed to query messages", http.StatusInternalServerError)
+		return
+	}
+
+	provider, ok := h.provider(w, req.Provider)
+	if !ok {
+		return
+	}
+
+	// The report and its analysis open the conversation, followed by earlier turns
+	chat := ai.ChatRequest{
+		System: reportChatSystemPrompt,
+		Messages: []ai.Message{
+			{Role: "user", Content: p.ContentPrompt},
+			{Role: "assistant", Content: p.AIAnalysis},
+		},
+	}
+	for _, m := range history {
+		chat.Messages = append(chat.Messages, ai.Message{Role: m.Role, Content: m.Content})
+	}
+	chat.Messages = append(chat.Messages, ai.Message{Role: "user", Content: req.Content})
+
+	replyAI(w, r, "chat", provider, req.AIOptions.apply(chat), func(answer string, usage ai.Usage) (any, error) {
+		tx, err := h.db.Begin()
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.Exec(`
+			INSERT INTO report_messages (report_id, role, content) VALUES (?, 'user', ?)
+		`, id, req.Content); err != nil {
+			return nil, err
+		}
+
+		reply := ReportMessage{Role: "assistant", Content: answer, AIProvider: usage.Provider, CreatedAt: time.Now().UTC()}
+		result, err := tx.Exec(`
+			INSERT INTO report_messages (report_id, role, content, ai_provider, created_at) VALUES (?, ?, ?, ?, ?)
+		`, id, reply.Role, reply.Content, reply.AIProvider, reply.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		reply.ID, _ = result.LastInsertId()
+
+		return reply, tx.Commit()
+	})
+}
+
+// ClearMessages deletes the follow-up conversation about a report
+func (h *PromptsHandler) ClearMessages(w http.ResponseWriter, r *http.Request) {
+	id, err := json.Number(r.PathValue("id")).Int64()
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.db.Exec(`DELETE FROM report_messages WHERE report_id = ?`, id); err != nil {
+		http.Error(w, "Failed to clear messages", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// messages reads the conversation about a report, oldest first
+func (h *PromptsHandler) messages(reportID int64) ([]ReportMessage, error) {
+	rows, err := h.db.Query(`
+		SELECT id, role, content, ai_provider, created_at
+		FROM report_messages
+		WHERE report_id = ?
+		ORDER BY id ASC
+	`, reportID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []ReportMessage{}
+	for rows.Next() {
+		var m ReportMessage
+		var provider sql.NullString
+		if err := rows.Scan(&m.ID, &m.Role, &m.Content, &provider, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		m.AIProvider = provider.String
+		messages = append(messages, m)
+	}
+
+	return messages, rows.Err()
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mx-seer/seer/internal/report"
+)
+
+// PromptTemplatesHandler handles prompt template requests
+type PromptTemplatesHandler struct {
+	store *report.Store
+}
+
+// NewPromptTemplatesHandler creates a new prompt templates handler
+func NewPromptTemplatesHandler(db *sql.DB) *PromptTemplatesHandler {
+	return &PromptTemplatesHandler{store: report.NewStore(db)}
+}
+
+// PreviewRequest is a template to render without saving a report. Body takes
+// precedence over Name, which refers to a saved or the built-in template.
+type PreviewRequest struct {
+	Name string `json:"name"`
+	Body string `json:"body"`
+}
+
+// PreviewResponse is a rendered template
+type PreviewResponse struct {
+	Template         string `json:"template"`
+	OpportunityCount int    `json:"opportunity_count"`
+	Content          string `json:"content"`
+}
+
+// List returns the built-in template and all saved templates
+func (h *PromptTemplatesHandler) List(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.store.Templates()
+	if err != nil {
+		http.Error(w, "Failed to get prompt templates", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(templates)
+}
+
+// Get returns a single saved template by ID
+func (h *PromptTemplatesHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	t, err := h.store.GetTemplate(id)
+	if err != nil {
+		http.Error(w, "Failed to get prompt template", http.StatusInternalServerError)
+		return
+	}
+	if t == nil {
+		http.Error(w, "Prompt template not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(t)
+}
+
+// Create saves a new template
+func (h *PromptTemplatesHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var t report.PromptTemplate
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	t.Builtin = false
+	if err := t.Normalize(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.store.CreateTemplate(&t); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			http.Error(w, "A prompt template with this name already exists", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed to create prompt template", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(t)
+}
+
+// Update replaces a saved template
+func (h *PromptTemplatesHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	existing, err := h.store.GetTemplate(id)
+	if err != nil {
+		http.Error(w, "Failed to get prompt template", http.StatusInternalServerError)
+		return
+	}
+	if existing == nil {
+		http.Error(w, "Prompt template not found", http.StatusNotFound)
+		return
+	}
+
+	var t report.PromptTemplate
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	t.ID = existing.ID
+	t.CreatedAt = existing.CreatedAt
+	t.Builtin = false
+	if err := t.Normalize(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.store.UpdateTemplate(&t); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			http.Error(w, "A prompt template with this name already exists", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed to update prompt template", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(t)
+}
+
+// Delete deletes a saved template
+func (h *PromptTemplatesHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.store.DeleteTemplate(id)
+	if errors.Is(err, report.ErrTemplateNotFound) {
+		http.Error(w, "Prompt template not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to delete prompt template", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Preview renders a template against the opportunities of a period without saving a
+// report. The period is chosen with ?start= and ?end= as for generating prompts.
+func (h *PromptTemplatesHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	var req PreviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var tmpl *report.Template
+	var err error
+	if strings.TrimSpace(req.Body) != "" {
+		name := req.Name
+		if name == "" {
+			name = "preview"
+		}
+		tmpl, err = report.ParseTemplate(name, req.Body)
+		if err != nil {
+			http.Error(w, "Invalid template: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		tmpl, err = h.store.Template(req.Name)
+		if errors.Is(err, report.ErrTemplateNotFound) {
+			http.Error(w, "Prompt template not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Failed to load prompt template", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	start, end := parsePeriod(r)
+	opportunities, watchlists, err := h.store.Load(start, end)
+	if err != nil {
+		http.Error(w, "Failed to query opportunities", http.StatusInternalServerError)
+		return
+	}
+
+	rep, err := report.New().GenerateWith(tmpl, opportunities, watchlists, start, end)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(PreviewResponse{
+		Template:         rep.Template,
+		OpportunityCount: rep.OpportunityCount,
+		Content:          rep.ContentPrompt,
+	})
+}
@@ -1,105 +1,85 @@
 package handlers
 
 import (
+	"bytes"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/mx-seer/seer/internal/ai"
 	"github.com/mx-seer/seer/internal/report"
 )
 
 // PromptResponse represents a prompt in API responses
 type PromptResponse struct {
-	ID               int64     `json:"id"`
-	PeriodStart      time.Time `json:"period_start"`
-	PeriodEnd        time.Time `json:"period_end"`
-	OpportunityCount int       `json:"opportunity_count"`
-	ContentHuman     string    `json:"content_human"`
-	ContentPrompt    string    `json:"content_prompt"`
-	AIAnalysis       string    `json:"ai_analysis,omitempty"`
-	CreatedAt        time.Time `json:"created_at"`
+	ID               int64      `json:"id"`
+	PeriodStart      time.Time  `json:"period_start"`
+	PeriodEnd        time.Time  `json:"period_end"`
+	OpportunityCount int        `json:"opportunity_count"`
+	ContentHuman     string     `json:"content_human"`
+	ContentPrompt    string     `json:"content_prompt"`
+	AIAnalysis       string     `json:"ai_analysis,omitempty"`
+	AIProvider       string     `json:"ai_provider,omitempty"`
+	AIModel          string     `json:"ai_model,omitempty"`
+	AIAnalyzedAt     *time.Time `json:"ai_analyzed_at,omitempty"`
+	Template         string     `json:"template,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
 }
 
 // PromptsHandler handles prompt-related requests
 type PromptsHandler struct {
 	db        *sql.DB
 	generator *report.Generator
+	reports   *report.Store
+	providers *ai.Store
 }
 
 // NewPromptsHandler creates a new prompts handler
-func NewPromptsHandler(db *sql.DB) *PromptsHandler {
+func NewPromptsHandler(db *sql.DB, providers *ai.Store) *PromptsHandler {
 	return &PromptsHandler{
 		db:        db,
 		generator: report.New(),
+		reports:   report.NewStore(db),
+		providers: providers,
 	}
 }
 
-// Generate generates a new prompt
+// Generate generates a new prompt from the opportunities of a period, rendered with
+// the template named by ?template= (default: the built-in template)
 func (h *PromptsHandler) Generate(w http.ResponseWriter, r *http.Request) {
-	// Default to last 24 hours
-	periodEnd := time.Now()
-	periodStart := periodEnd.Add(-24 * time.Hour)
-
-	// Allow custom period via query params
-	if startStr := r.URL.Query().Get("start"); startStr != "" {
-		if t, err := time.Parse("2006-01-02", startStr); err == nil {
-			periodStart = t
-		}
+	tmpl, err := h.reports.Template(r.URL.Query().Get("template"))
+	if errors.Is(err, report.ErrTemplateNotFound) {
+		http.Error(w, "Prompt template not found", http.StatusNotFound)
+		return
 	}
-	if endStr := r.URL.Query().Get("end"); endStr != "" {
-		if t, err := time.Parse("2006-01-02", endStr); err == nil {
-			periodEnd = t.Add(24*time.Hour - time.Second) // End of day
-		}
+	if err != nil {
+		http.Error(w, "Failed to load prompt template", http.StatusInternalServerError)
+		return
 	}
 
+	periodStart, periodEnd := parsePeriod(r)
+
 	// Get opportunities from the period
-	rows, err := h.db.Query(`
-		SELECT id, title, description, source, source_url, score, signals, detected_at
-		FROM opportunities
-		WHERE detected_at BETWEEN ? AND ?
-		ORDER BY score DESC
-	`, periodStart, periodEnd)
+	opportunities, watchlists, err := h.reports.Load(periodStart, periodEnd)
 	if err != nil {
 		http.Error(w, "Failed to query opportunities", http.StatusInternalServerError)
 		return
 	}
-	defer rows.Close()
-
-	var opportunities []report.Opportunity
-	for rows.Next() {
-		var opp report.Opportunity
-		var signalsJSON string
-		var description, sourceURL sql.NullString
-
-		err := rows.Scan(
-			&opp.ID, &opp.Title, &description, &opp.SourceType,
-			&sourceURL, &opp.Score, &signalsJSON, &opp.DetectedAt,
-		)
-		if err != nil {
-			continue
-		}
-
-		opp.Description = description.String
-		opp.SourceURL = sourceURL.String
-		json.Unmarshal([]byte(signalsJSON), &opp.Signals)
-
-		opportunities = append(opportunities, opp)
-	}
 
 	// Generate prompt
-	rep := h.generator.Generate(opportunities, periodStart, periodEnd)
+	rep, err := h.generator.GenerateWith(tmpl, opportunities, watchlists, periodStart, periodEnd)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	// Save to database (using reports table for backward compatibility)
-	result, err := h.db.Exec(`
-		INSERT INTO reports (period_start, period_end, opportunity_count, content_human, content_prompt)
-		VALUES (?, ?, ?, ?, ?)
-	`, rep.PeriodStart, rep.PeriodEnd, rep.OpportunityCount, rep.ContentHuman, rep.ContentPrompt)
-
-	var promptID int64
-	if err == nil {
-		promptID, _ = result.LastInsertId()
-	}
+	promptID, _ := h.reports.Save(rep)
 
 	response := PromptResponse{
 		ID:               promptID,
@@ -108,6 +88,7 @@ func (h *PromptsHandler) Generate(w http.ResponseWriter, r *http.Request) {
 		OpportunityCount: rep.OpportunityCount,
 		ContentHuman:     rep.ContentHuman,
 		ContentPrompt:    rep.ContentPrompt,
+		Template:         rep.Template,
 		CreatedAt:        time.Now(),
 	}
 
@@ -115,6 +96,26 @@ func (h *PromptsHandler) Generate(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// parsePeriod reads the report period from the start and end query params (YYYY-MM-DD),
+// defaulting to the last 24 hours
+func parsePeriod(r *http.Request) (time.Time, time.Time) {
+	periodEnd := time.Now()
+	periodStart := periodEnd.Add(-24 * time.Hour)
+
+	if startStr := r.URL.Query().Get("start"); startStr != "" {
+		if t, err := time.Parse("2006-01-02", startStr); err == nil {
+			periodStart = t
+		}
+	}
+	if endStr := r.URL.Query().Get("end"); endStr != "" {
+		if t, err := time.Parse("2006-01-02", endStr); err == nil {
+			periodEnd = t.Add(24*time.Hour - time.Second) // End of day
+		}
+	}
+
+	return periodStart, periodEnd
+}
+
 // List returns recent prompts
 func (h *PromptsHandler) List(w http.ResponseWriter, r *http.Request) {
 	rows, err := h.db.Query(`
@@ -155,18 +156,70 @@ func (h *PromptsHandler) Get(w http.ResponseWriter, r *http.Request) {
 		id, _ = json.Number(idStr).Int64()
 	}
 
+	p, err := h.load(id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Prompt not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get prompt", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p)
+}
+
+// load reads a single prompt with its content and AI analysis
+func (h *PromptsHandler) load(id int64) (*PromptResponse, error) {
 	var p PromptResponse
-	var contentHuman, contentPrompt, aiAnalysis sql.NullString
+	var contentHuman, contentPrompt, aiAnalysis, aiProvider, aiModel, template sql.NullString
+	var aiAnalyzedAt sql.NullTime
 
 	err := h.db.QueryRow(`
-		SELECT id, period_start, period_end, opportunity_count, content_human, content_prompt, ai_analysis, created_at
+		SELECT id, period_start, period_end, opportunity_count, content_human, content_prompt,
+			ai_analysis, ai_provider, ai_model, ai_analyzed_at, template, created_at
 		FROM reports
 		WHERE id = ?
 	`, id).Scan(
 		&p.ID, &p.PeriodStart, &p.PeriodEnd, &p.OpportunityCount,
-		&contentHuman, &contentPrompt, &aiAnalysis, &p.CreatedAt,
+		&contentHuman, &contentPrompt, &aiAnalysis, &aiProvider, &aiModel, &aiAnalyzedAt, &template, &p.CreatedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	p.ContentHuman = contentHuman.String
+	p.ContentPrompt = contentPrompt.String
+	p.AIAnalysis = aiAnalysis.String
+	p.AIProvider = aiProvider.String
+	p.AIModel = aiModel.String
+	p.Template = template.String
+	if aiAnalyzedAt.Valid {
+		p.AIAnalyzedAt = &aiAnalyzedAt.Time
+	}
+
+	return &p, nil
+}
+
+// Analyze sends a prompt to an AI provider and stores the analysis on the report.
+// The body may choose the provider and generation settings; ?stream=true streams the
+// analysis as server-sent events.
+func (h *PromptsHandler) Analyze(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := json.Number(idStr).Int64()
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var opts AIOptions
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil && err != io.EOF {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
 
+	p, err := h.load(id)
 	if err == sql.ErrNoRows {
 		http.Error(w, "Prompt not found", http.StatusNotFound)
 		return
@@ -175,13 +228,43 @@ func (h *PromptsHandler) Get(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to get prompt", http.StatusInternalServerError)
 		return
 	}
+	if p.ContentPrompt == "" {
+		http.Error(w, "Prompt has no content to analyze", http.StatusBadRequest)
+		return
+	}
 
-	p.ContentHuman = contentHuman.String
-	p.ContentPrompt = contentPrompt.String
-	p.AIAnalysis = aiAnalysis.String
+	provider, ok := h.provider(w, opts.Provider)
+	if !ok {
+		return
+	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(p)
+	replyAI(w, r, "analyze", provider, opts.apply(ai.Prompt(p.ContentPrompt)), func(analysis string, usage ai.Usage) (any, error) {
+		// A new analysis starts a new conversation
+		analyzedAt := time.Now().UTC()
+		if err := h.reports.SaveAnalysis(id, analysis, usage.Provider, usage.Model, analyzedAt); err != nil {
+			return nil, err
+		}
+
+		p.AIAnalysis = analysis
+		p.AIProvider = usage.Provider
+		p.AIModel = usage.Model
+		p.AIAnalyzedAt = &analyzedAt
+		return p, nil
+	})
+}
+
+// provider loads the requested AI provider, writing an error response if it is unusable
+func (h *PromptsHandler) provider(w http.ResponseWriter, providerType string) (ai.Provider, bool) {
+	provider, err := h.providers.Provider(providerType)
+	if errors.Is(err, ai.ErrNotConfigured) {
+		http.Error(w, "AI provider not configured", http.StatusBadRequest)
+		return nil, false
+	}
+	if err != nil {
+		http.Error(w, "Failed to load AI provider: "+err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	return provider, true
 }
 
 // GetContent returns just the prompt content for copying
@@ -208,6 +291,120 @@ func (h *PromptsHandler) GetContent(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(contentPrompt))
 }
 
+// Export returns a report as a file in the format chosen with ?format=md|html|json|csv
+// (default: md). HTML is a self-contained page styled for printing to PDF.
+func (h *PromptsHandler) Export(w http.ResponseWriter, r *http.Request) {
+	id, err := json.Number(r.PathValue("id")).Int64()
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	format, err := report.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	export, err := h.reports.Export(id)
+	if err != nil {
+		http.Error(w, "Failed to get prompt", http.StatusInternalServerError)
+		return
+	}
+	if export == nil {
+		http.Error(w, "Prompt not found", http.StatusNotFound)
+		return
+	}
+
+	// The print view opens in the browser; the other formats are downloads
+	disposition := "attachment"
+	if format == report.FormatHTML {
+		disposition = "inline"
+	}
+
+	var buf bytes.Buffer
+	if err := export.Write(&buf, format); err != nil {
+		http.Error(w, "Failed to export prompt", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, export.Filename(format)))
+	w.Write(buf.Bytes())
+}
+
+// DiffReport identifies a report in a comparison
+type DiffReport struct {
+	ID               int64     `json:"id"`
+	PeriodStart      time.Time `json:"period_start"`
+	PeriodEnd        time.Time `json:"period_end"`
+	OpportunityCount int       `json:"opportunity_count"`
+	Snapshot         bool      `json:"snapshot"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// PromptDiffResponse is how the opportunities of one report changed in another
+type PromptDiffResponse struct {
+	From DiffReport `json:"from"`
+	To   DiffReport `json:"to"`
+	*report.Diff
+}
+
+// Diff compares the opportunities captured by two reports: new and dropped entries
+// and score movers from the first to the second. Score changes smaller than
+// ?min_change= (default: 1) are ignored.
+func (h *PromptsHandler) Diff(w http.ResponseWriter, r *http.Request) {
+	fromID, err := json.Number(r.PathValue("id")).Int64()
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+	toID, err := json.Number(r.PathValue("other")).Int64()
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	minChange := 1
+	if v := r.URL.Query().Get("min_change"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			minChange = n
+		}
+	}
+
+	var reports [2]*report.Export
+	for i, id := range []int64{fromID, toID} {
+		reports[i], err = h.reports.Export(id)
+		if err != nil {
+			http.Error(w, "Failed to get prompt", http.StatusInternalServerError)
+			return
+		}
+		if reports[i] == nil {
+			http.Error(w, fmt.Sprintf("Prompt %d not found", id), http.StatusNotFound)
+			return
+		}
+	}
+	from, to := reports[0], reports[1]
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(PromptDiffResponse{
+		From: diffReport(from),
+		To:   diffReport(to),
+		Diff: report.Compare(from.Opportunities, to.Opportunities, minChange),
+	})
+}
+
+func diffReport(e *report.Export) DiffReport {
+	return DiffReport{
+		ID:               e.ID,
+		PeriodStart:      e.PeriodStart,
+		PeriodEnd:        e.PeriodEnd,
+		OpportunityCount: len(e.Opportunities),
+		Snapshot:         e.Snapshot,
+		CreatedAt:        e.CreatedAt,
+	}
+}
+
 // CreatePromptRequest represents the request body for creating a prompt
 type CreatePromptRequest struct {
 	OpportunityCount int    `json:"opportunity_count"`
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mx-seer/seer/internal/report"
+	"github.com/mx-seer/seer/internal/schedule"
+)
+
+// ReportSchedulesHandler handles report schedule requests
+type ReportSchedulesHandler struct {
+	scheduler *schedule.Scheduler
+	store     *schedule.Store
+	reports   *report.Store
+}
+
+// NewReportSchedulesHandler creates a new report schedules handler
+func NewReportSchedulesHandler(scheduler *schedule.Scheduler, reports *report.Store) *ReportSchedulesHandler {
+	return &ReportSchedulesHandler{
+		scheduler: scheduler,
+		store:     scheduler.Store(),
+		reports:   reports,
+	}
+}
+
+// List returns all report schedules with their next run time
+func (h *ReportSchedulesHandler) List(w http.ResponseWriter, r *http.Request) {
+	schedules, err := h.store.List()
+	if err != nil {
+		http.Error(w, "Failed to get report schedules", http.StatusInternalServerError)
+		return
+	}
+
+	for i := range schedules {
+		schedules[i].NextRunAt = h.scheduler.Next(schedules[i].ID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schedules)
+}
+
+// Get returns a single report schedule by ID
+func (h *ReportSchedulesHandler) Get(w http.ResponseWriter, r *http.Request) {
+	sc, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sc)
+}
+
+// Create saves a new report schedule and schedules it
+func (h *ReportSchedulesHandler) Create(w http.ResponseWriter, r *http.Request) {
+	sc := schedule.Schedule{Enabled: true}
+	if err := json.NewDecoder(r.Body).Decode(&sc); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if !h.validate(w, &sc) {
+		return
+	}
+
+	if err := h.store.Create(&sc); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			http.Error(w, "A report schedule with this name already exists", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed to create report schedule", http.StatusInternalServerError)
+		return
+	}
+
+	h.reload()
+	sc.NextRunAt = h.scheduler.Next(sc.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sc)
+}
+
+// Update replaces the settings of a report schedule
+func (h *ReportSchedulesHandler) Update(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	sc := *existing
+	if err := json.NewDecoder(r.Body).Decode(&sc); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	sc.ID = existing.ID
+
+	if !h.validate(w, &sc) {
+		return
+	}
+
+	if err := h.store.Update(&sc); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			http.Error(w, "A report schedule with this name already exists", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed to update report schedule", http.StatusInternalServerError)
+		return
+	}
+
+	h.reload()
+	sc.NextRunAt = h.scheduler.Next(sc.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sc)
+}
+
+// Delete deletes a report schedule
+func (h *ReportSchedulesHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.store.Delete(id)
+	if errors.Is(err, schedule.ErrNotFound) {
+		http.Error(w, "Report schedule not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to delete report schedule", http.StatusInternalServerError)
+		return
+	}
+
+	h.reload()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Run runs a report schedule now, whether or not it is enabled
+func (h *ReportSchedulesHandler) Run(w http.ResponseWriter, r *http.Request) {
+	sc, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	run, err := h.scheduler.Run(r.Context(), sc)
+	if err != nil {
+		http.Error(w, "Failed to generate report: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(run)
+}
+
+// load reads the schedule named by the id path value, writing an error response if
+// there is none
+func (h *ReportSchedulesHandler) load(w http.ResponseWriter, r *http.Request) (*schedule.Schedule, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	sc, err := h.store.Get(id)
+	if err != nil {
+		http.Error(w, "Failed to get report schedule", http.StatusInternalServerError)
+		return nil, false
+	}
+	if sc == nil {
+		http.Error(w, "Report schedule not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	sc.NextRunAt = h.scheduler.Next(sc.ID)
+	return sc, true
+}
+
+// validate checks a schedule and its template, writing an error response if it is invalid
+func (h *ReportSchedulesHandler) validate(w http.ResponseWriter, sc *schedule.Schedule) bool {
+	if err := sc.Normalize(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+
+	if _, err := h.reports.Template(sc.Template); err != nil {
+		if errors.Is(err, report.ErrTemplateNotFound) {
+			http.Error(w, "Prompt template not found", http.StatusBadRequest)
+			return false
+		}
+		http.Error(w, "Failed to load prompt template", http.StatusInternalServerError)
+		return false
+	}
+
+	return true
+}
+
+// reload reschedules the stored schedules after a change
+func (h *ReportSchedulesHandler) reload() {
+	if err := h.scheduler.Reload(); err != nil {
+		log.Printf("Failed to reload report schedules: %v", err)
+	}
+}
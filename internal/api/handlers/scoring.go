@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/mx-seer/seer/internal/scoring"
+)
+
+// ScoringModelResponse describes the active and candidate scoring models
+type ScoringModelResponse struct {
+	Active    *scoring.Model `json:"active"`
+	Candidate *scoring.Model `json:"candidate"`
+}
+
+// ScoringHandler handles training and promotion of learned scoring weights
+type ScoringHandler struct {
+	store *scoring.ModelStore
+}
+
+// NewScoringHandler creates a new scoring handler
+func NewScoringHandler(db *sql.DB) *ScoringHandler {
+	return &ScoringHandler{
+		store: scoring.NewModelStore(db),
+	}
+}
+
+// Model returns the active and candidate models
+func (h *ScoringHandler) Model(w http.ResponseWriter, r *http.Request) {
+	active, err := h.store.Active()
+	if err != nil {
+		http.Error(w, "Failed to load active model", http.StatusInternalServerError)
+		return
+	}
+
+	candidate, err := h.store.Candidate()
+	if err != nil {
+		http.Error(w, "Failed to load candidate model", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ScoringModelResponse{
+		Active:    active,
+		Candidate: candidate,
+	})
+}
+
+// Train fits a candidate model on the current feedback labels
+func (h *ScoringHandler) Train(w http.ResponseWriter, r *http.Request) {
+	examples, err := h.store.Examples()
+	if err != nil {
+		http.Error(w, "Failed to load feedback", http.StatusInternalServerError)
+		return
+	}
+
+	model, err := scoring.Train(examples, scoring.DefaultTrainOptions())
+	if errors.Is(err, scoring.ErrNotEnoughFeedback) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to train model", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.store.SaveCandidate(model); err != nil {
+		http.Error(w, "Failed to save candidate model", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(model)
+}
+
+// Promote makes the candidate model the active scorer
+func (h *ScoringHandler) Promote(w http.ResponseWriter, r *http.Request) {
+	model, err := h.store.Promote()
+	if errors.Is(err, scoring.ErrNoCandidate) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to promote model", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(model)
+}
+
+// Reset restores the default signal weights
+func (h *ScoringHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	if err := h.store.Reset(); err != nil {
+		http.Error(w, "Failed to reset model", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
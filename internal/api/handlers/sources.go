@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/mx-seer/seer/internal/sources"
@@ -12,13 +13,14 @@ import (
 
 // SourceResponse represents a source in API responses
 type SourceResponse struct {
-	ID        int64     `json:"id"`
-	Type      string    `json:"type"`
-	Name      string    `json:"name"`
-	URL       string    `json:"url,omitempty"`
-	Enabled   bool      `json:"enabled"`
-	IsBuiltin bool      `json:"is_builtin"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        int64             `json:"id"`
+	Type      string            `json:"type"`
+	Name      string            `json:"name"`
+	URL       string            `json:"url,omitempty"`
+	Enabled   bool              `json:"enabled"`
+	IsBuiltin bool              `json:"is_builtin"`
+	Config    map[string]string `json:"config,omitempty"` // secret values are masked
+	CreatedAt time.Time         `json:"created_at"`
 }
 
 // SourceRequest represents a request to create/update a source
@@ -27,6 +29,69 @@ type SourceRequest struct {
 	Name    string `json:"name"`
 	URL     string `json:"url,omitempty"`
 	Enabled *bool  `json:"enabled,omitempty"`
+	// Config replaces the type-specific settings on update; a masked secret value
+	// keeps the stored one, unless the update also changes the URL
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// secretMask replaces secret config values in responses
+const secretMask = "********"
+
+// isSecretConfigKey reports whether a config key holds a credential, such as
+// "token" or "api_key"
+func isSecretConfigKey(key string) bool {
+	parts := strings.Split(strings.ToLower(key), "_")
+	switch parts[len(parts)-1] {
+	case "token", "key", "apikey", "secret", "password":
+		return true
+	}
+	return false
+}
+
+// keepsStoredSecret reports whether an update leaves a stored secret in place,
+// either by not replacing the config or by sending a secret back masked
+func keepsStoredSecret(previous, config map[string]string) bool {
+	if config == nil {
+		for key, value := range previous {
+			if value != "" && isSecretConfigKey(key) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for key, value := range config {
+		if value == secretMask && previous[key] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// sameURL compares source URLs, ignoring surrounding space and trailing slashes
+func sameURL(a, b string) bool {
+	return strings.TrimRight(strings.TrimSpace(a), "/") == strings.TrimRight(strings.TrimSpace(b), "/")
+}
+
+// newSourceResponse converts a source record, masking its secret config values
+func newSourceResponse(rec *sources.SourceRecord) SourceResponse {
+	config := rec.ToConfig().Config
+	for key, value := range config {
+		if value != "" && isSecretConfigKey(key) {
+			config[key] = secretMask
+		}
+	}
+
+	return SourceResponse{
+		ID:        rec.ID,
+		Type:      rec.Type,
+		Name:      rec.Name,
+		URL:       rec.URL,
+		Enabled:   rec.Enabled,
+		IsBuiltin: rec.IsBuiltin,
+		Config:    config,
+		CreatedAt: rec.CreatedAt,
+	}
 }
 
 // SourcesHandler handles source-related requests
@@ -50,16 +115,8 @@ func (h *SourcesHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := make([]SourceResponse, len(records))
-	for i, rec := range records {
-		response[i] = SourceResponse{
-			ID:        rec.ID,
-			Type:      rec.Type,
-			Name:      rec.Name,
-			URL:       rec.URL,
-			Enabled:   rec.Enabled,
-			IsBuiltin: rec.IsBuiltin,
-			CreatedAt: rec.CreatedAt,
-		}
+	for i := range records {
+		response[i] = newSourceResponse(&records[i])
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -85,15 +142,7 @@ func (h *SourcesHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := SourceResponse{
-		ID:        rec.ID,
-		Type:      rec.Type,
-		Name:      rec.Name,
-		URL:       rec.URL,
-		Enabled:   rec.Enabled,
-		IsBuiltin: rec.IsBuiltin,
-		CreatedAt: rec.CreatedAt,
-	}
+	response := newSourceResponse(rec)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
@@ -140,21 +189,17 @@ func (h *SourcesHandler) Create(w http.ResponseWriter, r *http.Request) {
 		IsBuiltin: false,
 		Config:    "{}",
 	}
+	if len(req.Config) > 0 {
+		data, _ := json.Marshal(req.Config)
+		record.Config = string(data)
+	}
 
 	if err := h.repo.Create(record); err != nil {
 		http.Error(w, "Failed to create source", http.StatusInternalServerError)
 		return
 	}
 
-	response := SourceResponse{
-		ID:        record.ID,
-		Type:      record.Type,
-		Name:      record.Name,
-		URL:       record.URL,
-		Enabled:   record.Enabled,
-		IsBuiltin: record.IsBuiltin,
-		CreatedAt: record.CreatedAt,
-	}
+	response := newSourceResponse(record)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -191,6 +236,13 @@ func (h *SourcesHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Stored secrets are never sent to a new URL; they have to be entered again
+	previous := existing.ToConfig().Config
+	if req.URL != "" && !sameURL(req.URL, existing.URL) && keepsStoredSecret(previous, req.Config) {
+		http.Error(w, "Secret config values must be entered again when changing the URL", http.StatusBadRequest)
+		return
+	}
+
 	// Update fields
 	if req.Name != "" {
 		existing.Name = req.Name
@@ -201,21 +253,22 @@ func (h *SourcesHandler) Update(w http.ResponseWriter, r *http.Request) {
 	if req.Enabled != nil {
 		existing.Enabled = *req.Enabled
 	}
+	if req.Config != nil {
+		for key, value := range req.Config {
+			if value == secretMask {
+				req.Config[key] = previous[key]
+			}
+		}
+		data, _ := json.Marshal(req.Config)
+		existing.Config = string(data)
+	}
 
 	if err := h.repo.Update(existing); err != nil {
 		http.Error(w, "Failed to update source", http.StatusInternalServerError)
 		return
 	}
 
-	response := SourceResponse{
-		ID:        existing.ID,
-		Type:      existing.Type,
-		Name:      existing.Name,
-		URL:       existing.URL,
-		Enabled:   existing.Enabled,
-		IsBuiltin: existing.IsBuiltin,
-		CreatedAt: existing.CreatedAt,
-	}
+	response := newSourceResponse(existing)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
@@ -279,20 +332,46 @@ func (h *SourcesHandler) Toggle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	existing.Enabled = newEnabled
-	response := SourceResponse{
-		ID:        existing.ID,
-		Type:      existing.Type,
-		Name:      existing.Name,
-		URL:       existing.URL,
-		Enabled:   existing.Enabled,
-		IsBuiltin: existing.IsBuiltin,
-		CreatedAt: existing.CreatedAt,
-	}
+	response := newSourceResponse(existing)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+// Runs returns the most recent fetch runs of a source, newest first
+func (h *SourcesHandler) Runs(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	existing, err := h.repo.GetByID(id)
+	if err != nil {
+		http.Error(w, "Failed to get source", http.StatusInternalServerError)
+		return
+	}
+	if existing == nil {
+		http.Error(w, "Source not found", http.StatusNotFound)
+		return
+	}
+
+	limit := 20
+	if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 && v <= 50 {
+		limit = v
+	}
+
+	runs, err := h.repo.GetRuns(id, limit)
+	if err != nil {
+		http.Error(w, "Failed to get fetch runs", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(runs)
+}
+
 // AvailableTypes returns the available source types
 func (h *SourcesHandler) AvailableTypes(w http.ResponseWriter, r *http.Request) {
 	types := sources.GetAvailableTypes()
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/mx-seer/seer/internal/ai"
+)
+
+// AIOptions are the optional generation settings accepted by AI endpoints
+type AIOptions struct {
+	// Provider is the AI provider type to use; empty uses the default provider
+	Provider    string   `json:"provider"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	MaxTokens   int      `json:"max_tokens,omitempty"`
+}
+
+// apply copies the generation settings onto a chat request
+func (o AIOptions) apply(req ai.ChatRequest) ai.ChatRequest {
+	req.Temperature = o.Temperature
+	req.MaxTokens = o.MaxTokens
+	return req
+}
+
+// wantsStream reports whether the client asked for a server-sent event stream
+func wantsStream(r *http.Request) bool {
+	stream, _ := strconv.ParseBool(r.URL.Query().Get("stream"))
+	return stream
+}
+
+// replyAI sends a chat request to a provider, recording its usage under purpose, and
+// passes the full reply and the usage naming the provider that answered to save, whose
+// result is returned to the client. With ?stream=true the reply is sent as server-sent
+// events while it is generated: "chunk" events carry {"content": ...}, then a "done"
+// event carries the saved result, or an "error" event carries {"error": ...}.
+func replyAI(w http.ResponseWriter, r *http.Request, purpose string, provider ai.Provider, req ai.ChatRequest, save func(content string, usage ai.Usage) (any, error)) {
+	ctx := ai.WithPurpose(r.Context(), purpose)
+
+	if !wantsStream(r) {
+		resp, err := provider.Chat(ctx, req)
+		if err != nil {
+			aiError(w, err)
+			return
+		}
+
+		result, err := save(resp.Content, resp.Usage)
+		if err != nil {
+			http.Error(w, "Failed to save AI reply", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	chunks, err := provider.Stream(ctx, req)
+	if err != nil {
+		aiError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	var content []byte
+	var usage ai.Usage
+	for chunk := range chunks {
+		if chunk.Err != nil {
+			writeEvent(w, flusher, "error", map[string]string{"error": chunk.Err.Error()})
+			return
+		}
+		if chunk.Usage != nil {
+			usage = *chunk.Usage
+		}
+		if chunk.Content == "" {
+			continue
+		}
+		content = append(content, chunk.Content...)
+		writeEvent(w, flusher, "chunk", map[string]string{"content": chunk.Content})
+	}
+
+	// A client that went away gets nothing saved from the partial reply
+	if r.Context().Err() != nil {
+		return
+	}
+
+	result, err := save(string(content), usage)
+	if err != nil {
+		writeEvent(w, flusher, "error", map[string]string{"error": "Failed to save AI reply"})
+		return
+	}
+	writeEvent(w, flusher, "done", result)
+}
+
+// aiError writes the response for a failed provider call
+func aiError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ai.ErrBudgetExceeded) {
+		http.Error(w, "Monthly AI spend cap reached", http.StatusTooManyRequests)
+		return
+	}
+	http.Error(w, "AI request failed: "+err.Error(), http.StatusBadGateway)
+}
+
+// writeEvent writes a single server-sent event with a JSON payload
+func writeEvent(w http.ResponseWriter, flusher http.Flusher, event string, data any) {
+	payload, _ := json.Marshal(data)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	flusher.Flush()
+}
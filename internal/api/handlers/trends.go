@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/mx-seer/seer/internal/topics"
+)
+
+// TrendsHandler handles topic trend requests
+type TrendsHandler struct {
+	store *topics.Store
+}
+
+// NewTrendsHandler creates a new trends handler
+func NewTrendsHandler(db *sql.DB) *TrendsHandler {
+	return &TrendsHandler{store: topics.NewStore(db)}
+}
+
+// List returns the topics gaining the most volume and score period over period
+func (h *TrendsHandler) List(w http.ResponseWriter, r *http.Request) {
+	opts := topics.TrendOptions{
+		Days:     7,
+		MinCount: 2,
+		Limit:    20,
+		Sort:     r.URL.Query().Get("sort"),
+		Source:   r.URL.Query().Get("source"),
+	}
+
+	if v, err := strconv.Atoi(r.URL.Query().Get("days")); err == nil && v > 0 && v <= 90 {
+		opts.Days = v
+	}
+	if v, err := strconv.Atoi(r.URL.Query().Get("min_count")); err == nil && v > 0 {
+		opts.MinCount = v
+	}
+	if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 && v <= 100 {
+		opts.Limit = v
+	}
+
+	switch opts.Sort {
+	case "", "volume", "growth", "score":
+	default:
+		http.Error(w, "Invalid sort: must be volume, growth or score", http.StatusBadRequest)
+		return
+	}
+
+	trends, err := h.store.Trends(opts)
+	if err != nil {
+		http.Error(w, "Failed to compute trends", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"days":   opts.Days,
+		"trends": trends,
+	})
+}
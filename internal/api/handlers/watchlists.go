@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mx-seer/seer/internal/sources"
+)
+
+// WatchlistsHandler handles watchlist-related requests
+type WatchlistsHandler struct {
+	repo *sources.WatchlistRepository
+}
+
+// NewWatchlistsHandler creates a new watchlists handler
+func NewWatchlistsHandler(db *sql.DB) *WatchlistsHandler {
+	return &WatchlistsHandler{
+		repo: sources.NewWatchlistRepository(db),
+	}
+}
+
+// List returns all watchlists
+func (h *WatchlistsHandler) List(w http.ResponseWriter, r *http.Request) {
+	watchlists, err := h.repo.GetAll()
+	if err != nil {
+		http.Error(w, "Failed to get watchlists", http.StatusInternalServerError)
+		return
+	}
+
+	if watchlists == nil {
+		watchlists = []sources.Watchlist{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(watchlists)
+}
+
+// Get returns a single watchlist by ID
+func (h *WatchlistsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	watchlist, err := h.repo.GetByID(id)
+	if err != nil {
+		http.Error(w, "Failed to get watchlist", http.StatusInternalServerError)
+		return
+	}
+	if watchlist == nil {
+		http.Error(w, "Watchlist not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(watchlist)
+}
+
+// Create creates a new watchlist
+func (h *WatchlistsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var watchlist sources.Watchlist
+	if err := json.NewDecoder(r.Body).Decode(&watchlist); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := watchlist.Normalize(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.Create(&watchlist); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			http.Error(w, "A watchlist with this name already exists", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed to create watchlist", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(watchlist)
+}
+
+// Update replaces an existing watchlist
+func (h *WatchlistsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	existing, err := h.repo.GetByID(id)
+	if err != nil {
+		http.Error(w, "Failed to get watchlist", http.StatusInternalServerError)
+		return
+	}
+	if existing == nil {
+		http.Error(w, "Watchlist not found", http.StatusNotFound)
+		return
+	}
+
+	var watchlist sources.Watchlist
+	if err := json.NewDecoder(r.Body).Decode(&watchlist); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	watchlist.ID = existing.ID
+	watchlist.CreatedAt = existing.CreatedAt
+	if err := watchlist.Normalize(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.Update(&watchlist); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			http.Error(w, "A watchlist with this name already exists", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed to update watchlist", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(watchlist)
+}
+
+// Delete deletes a watchlist
+func (h *WatchlistsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.Delete(id); err != nil {
+		http.Error(w, "Watchlist not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
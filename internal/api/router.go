@@ -1,30 +1,45 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"log"
 	"net/http"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/mx-seer/seer/internal/ai"
+	"github.com/mx-seer/seer/internal/alerts"
 	"github.com/mx-seer/seer/internal/api/handlers"
 	"github.com/mx-seer/seer/internal/db"
+	"github.com/mx-seer/seer/internal/report"
+	"github.com/mx-seer/seer/internal/schedule"
 	"github.com/mx-seer/seer/internal/sources"
 )
 
+// requestTimeout bounds regular API requests; AI calls are bounded by provider timeouts instead
+const requestTimeout = 30 * time.Second
+
 // Server holds the HTTP server dependencies
 type Server struct {
 	db            *db.DB
 	router        *chi.Mux
 	sourceManager *sources.Manager
+	aiProviders   *ai.Store
+	alerts        *alerts.AlertService
+	scheduler     *schedule.Scheduler
 }
 
 // NewServer creates a new API server
-func NewServer(database *db.DB, sourceManager *sources.Manager) *Server {
+func NewServer(database *db.DB, sourceManager *sources.Manager, aiProviders *ai.Store, alertService *alerts.AlertService, scheduler *schedule.Scheduler) *Server {
 	s := &Server{
 		db:            database,
 		router:        chi.NewRouter(),
 		sourceManager: sourceManager,
+		aiProviders:   aiProviders,
+		alerts:        alertService,
+		scheduler:     scheduler,
 	}
 
 	s.setupMiddleware()
@@ -39,14 +54,13 @@ func (s *Server) setupMiddleware() {
 	s.router.Use(middleware.RealIP)
 	s.router.Use(middleware.Logger)
 	s.router.Use(middleware.Recoverer)
-	s.router.Use(middleware.Timeout(30 * time.Second))
 
 	// CORS for development
 	s.router.Use(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Seer-Signature")
 
 			if r.Method == "OPTIONS" {
 				w.WriteHeader(http.StatusOK)
@@ -61,43 +75,132 @@ func (s *Server) setupMiddleware() {
 // setupRoutes configures all API routes
 func (s *Server) setupRoutes() {
 	// Health check at root
-	s.router.Get("/health", s.handleHealth)
+	s.router.With(middleware.Timeout(requestTimeout)).Get("/health", s.handleHealth)
 
 	// API routes
 	s.router.Route("/api", func(r chi.Router) {
-		r.Get("/health", s.handleHealth)
-
-		// Opportunities
-		oppHandler := handlers.NewOpportunitiesHandler(s.db.DB)
-		r.Get("/opportunities", oppHandler.List)
-		r.Get("/opportunities/stats", oppHandler.Stats)
-		r.Get("/opportunities/{id}", oppHandler.Get)
-
-		// Sources
-		srcHandler := handlers.NewSourcesHandler(s.db.DB)
-		r.Get("/sources", srcHandler.List)
-		r.Get("/sources/types", srcHandler.AvailableTypes)
-		r.Get("/sources/{id}", srcHandler.Get)
-		r.Post("/sources", srcHandler.Create)
-		r.Put("/sources/{id}", srcHandler.Update)
-		r.Delete("/sources/{id}", srcHandler.Delete)
-		r.Post("/sources/{id}/toggle", srcHandler.Toggle)
-		r.Post("/sources/fetch", s.handleFetchSources)
-
-		// Prompts
-		promptHandler := handlers.NewPromptsHandler(s.db.DB)
-		r.Get("/prompts", promptHandler.List)
-		r.Post("/prompts", promptHandler.Create)
-		r.Post("/prompts/generate", promptHandler.Generate)
-		r.Get("/prompts/{id}", promptHandler.Get)
-		r.Get("/prompts/{id}/content", promptHandler.GetContent)
+		promptHandler := handlers.NewPromptsHandler(s.db.DB, s.aiProviders)
+		scheduleHandler := handlers.NewReportSchedulesHandler(s.scheduler, report.NewStore(s.db.DB))
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Timeout(requestTimeout))
+
+			r.Get("/health", s.handleHealth)
+
+			// Opportunities
+			oppHandler := handlers.NewOpportunitiesHandler(s.db.DB)
+			r.Get("/opportunities", oppHandler.List)
+			r.Get("/opportunities/stats", oppHandler.Stats)
+			r.Get("/opportunities/{id}", oppHandler.Get)
+			r.Put("/opportunities/{id}/feedback", oppHandler.SetFeedback)
+			r.Delete("/opportunities/{id}/feedback", oppHandler.ClearFeedback)
+
+			// Clusters
+			clusterHandler := handlers.NewClustersHandler(s.db.DB)
+			r.Get("/clusters", clusterHandler.List)
+			r.Get("/clusters/{id}", clusterHandler.Get)
+
+			// Topic trends
+			trendsHandler := handlers.NewTrendsHandler(s.db.DB)
+			r.Get("/trends", trendsHandler.List)
+
+			// Learned scoring
+			scoringHandler := handlers.NewScoringHandler(s.db.DB)
+			r.Get("/scoring/model", scoringHandler.Model)
+			r.Post("/scoring/train", scoringHandler.Train)
+			r.Post("/scoring/promote", scoringHandler.Promote)
+			r.Delete("/scoring/model", scoringHandler.Reset)
+
+			// Sources
+			srcHandler := handlers.NewSourcesHandler(s.db.DB)
+			r.Get("/sources", srcHandler.List)
+			r.Get("/sources/types", srcHandler.AvailableTypes)
+			r.Get("/sources/{id}", srcHandler.Get)
+			r.Post("/sources", srcHandler.Create)
+			r.Put("/sources/{id}", srcHandler.Update)
+			r.Delete("/sources/{id}", srcHandler.Delete)
+			r.Post("/sources/{id}/toggle", srcHandler.Toggle)
+			r.Get("/sources/{id}/runs", srcHandler.Runs)
+			r.Post("/sources/fetch", s.handleFetchSources)
+
+			// Opportunities pushed to webhook sources
+			ingestHandler := handlers.NewIngestHandler(s.db.DB, s.sourceManager)
+			r.Post("/ingest/{source_id}", ingestHandler.Ingest)
+
+			// Custom keywords
+			keywordsHandler := handlers.NewKeywordsHandler(s.db.DB)
+			r.Get("/keywords", keywordsHandler.Get)
+			r.Put("/keywords", keywordsHandler.Update)
+			r.Post("/keywords/preview", keywordsHandler.Preview)
+
+			// Watchlists
+			watchlistHandler := handlers.NewWatchlistsHandler(s.db.DB)
+			r.Get("/watchlists", watchlistHandler.List)
+			r.Post("/watchlists", watchlistHandler.Create)
+			r.Get("/watchlists/{id}", watchlistHandler.Get)
+			r.Put("/watchlists/{id}", watchlistHandler.Update)
+			r.Delete("/watchlists/{id}", watchlistHandler.Delete)
+
+			// Prompts
+			r.Get("/prompts", promptHandler.List)
+			r.Post("/prompts", promptHandler.Create)
+			r.Post("/prompts/generate", promptHandler.Generate)
+			r.Get("/prompts/{id}", promptHandler.Get)
+			r.Get("/prompts/{id}/content", promptHandler.GetContent)
+			r.Get("/prompts/{id}/export", promptHandler.Export)
+			r.Get("/prompts/{id}/diff/{other}", promptHandler.Diff)
+			r.Get("/prompts/{id}/messages", promptHandler.Messages)
+			r.Delete("/prompts/{id}/messages", promptHandler.ClearMessages)
+
+			// Prompt templates
+			templateHandler := handlers.NewPromptTemplatesHandler(s.db.DB)
+			r.Get("/prompt-templates", templateHandler.List)
+			r.Post("/prompt-templates", templateHandler.Create)
+			r.Post("/prompt-templates/preview", templateHandler.Preview)
+			r.Get("/prompt-templates/{id}", templateHandler.Get)
+			r.Put("/prompt-templates/{id}", templateHandler.Update)
+			r.Delete("/prompt-templates/{id}", templateHandler.Delete)
+
+			// Report schedules
+			r.Get("/report-schedules", scheduleHandler.List)
+			r.Post("/report-schedules", scheduleHandler.Create)
+			r.Get("/report-schedules/{id}", scheduleHandler.Get)
+			r.Put("/report-schedules/{id}", scheduleHandler.Update)
+			r.Delete("/report-schedules/{id}", scheduleHandler.Delete)
+
+			// Alert channels
+			alertHandler := handlers.NewAlertsHandler(s.alerts)
+			r.Get("/alerts", alertHandler.List)
+			r.Post("/alerts", alertHandler.Create)
+			r.Delete("/alerts/{id}", alertHandler.Delete)
+			r.Post("/alerts/{id}/toggle", alertHandler.Toggle)
+
+			// AI provider settings
+			aiHandler := handlers.NewAIHandler(s.aiProviders)
+			r.Get("/ai/providers", aiHandler.ListProviders)
+			r.Get("/ai/providers/{type}", aiHandler.GetProvider)
+			r.Put("/ai/providers/{type}", aiHandler.SaveProvider)
+			r.Delete("/ai/providers/{type}", aiHandler.DeleteProvider)
+			r.Post("/ai/providers/{type}/test", aiHandler.TestProvider)
+			r.Get("/ai/providers/{type}/models", aiHandler.ListModels)
+			r.Get("/ai/fallback", aiHandler.GetFallback)
+			r.Put("/ai/fallback", aiHandler.SetFallback)
+			r.Get("/ai/usage", aiHandler.Usage)
+		})
+
+		// Long-running AI requests, bounded by the provider client timeouts
+		r.Group(func(r chi.Router) {
+			r.Post("/prompts/{id}/analyze", promptHandler.Analyze)
+			r.Post("/prompts/{id}/messages", promptHandler.SendMessage)
+			r.Post("/report-schedules/{id}/run", scheduleHandler.Run)
+		})
 	})
 
 	// Serve embedded frontend
 	staticFS := StaticFS()
 	fileServer := http.FileServer(staticFS)
 
-	s.router.Get("/*", func(w http.ResponseWriter, r *http.Request) {
+	s.router.With(middleware.Timeout(requestTimeout)).Get("/*", func(w http.ResponseWriter, r *http.Request) {
 		// Try to serve the file directly
 		path := r.URL.Path
 		if path == "/" {
@@ -137,8 +240,13 @@ func (s *Server) handleFetchSources(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Run fetch in background and return immediately
-	go s.sourceManager.FetchAll(r.Context())
+	// Run fetch in background and return immediately; the request's context is
+	// cancelled as soon as this handler returns, so the fetch must not inherit it
+	go func() {
+		if err := s.sourceManager.FetchAll(context.WithoutCancel(r.Context())); err != nil {
+			log.Printf("Error fetching sources: %v", err)
+		}
+	}()
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{
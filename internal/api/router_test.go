@@ -1,18 +1,37 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/mx-seer/seer/internal/ai"
+	"github.com/mx-seer/seer/internal/alerts"
 	"github.com/mx-seer/seer/internal/db"
+	"github.com/mx-seer/seer/internal/schedule"
+	"github.com/mx-seer/seer/internal/sources"
+	"github.com/robfig/cron/v3"
 )
 
 func setupTestServer(t *testing.T) *Server {
 	t.Helper()
 
+	return setupMeteredServer(t, nil, 0)
+}
+
+// setupMeteredServer creates a test server whose AI calls are priced by prices and
+// capped at monthlyCap dollars a month
+func setupMeteredServer(t *testing.T, prices ai.Prices, monthlyCap float64) *Server {
+	t.Helper()
+
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
 
@@ -25,7 +44,16 @@ func setupTestServer(t *testing.T) *Server {
 		database.Close()
 	})
 
-	return NewServer(database, nil)
+	cipher, err := ai.NewCipher(make([]byte, 32))
+	if err != nil {
+		t.Fatalf("failed to create cipher: %v", err)
+	}
+
+	providers := ai.NewStore(database.DB, cipher, ai.NewMeter(database.DB, prices, monthlyCap))
+	alertService := alerts.NewAlertService(database.DB)
+	scheduler := schedule.NewScheduler(database.DB, providers, alertService, cron.New(), "http://seer.test")
+
+	return NewServer(database, nil, providers, alertService, scheduler)
 }
 
 func TestHealthEndpoint(t *testing.T) {
@@ -104,3 +132,1351 @@ func TestAPINotFound(t *testing.T) {
 		t.Errorf("expected status 404 for API not found, got %d", rec.Code)
 	}
 }
+
+func TestOpportunityFeedback(t *testing.T) {
+	server := setupTestServer(t)
+
+	if _, err := server.db.Exec(`
+		INSERT INTO opportunities (title, source, source_url, source_id_external)
+		VALUES ('Test', 'hackernews', 'https://example.com', 'fb-1')
+	`); err != nil {
+		t.Fatalf("failed to insert opportunity: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/api/opportunities/1/feedback", strings.NewReader(`{"label":"saved"}`))
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/opportunities?feedback=saved", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	var opps []map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&opps); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(opps) != 1 || opps[0]["feedback"] != "saved" {
+		t.Errorf("expected one saved opportunity, got %v", opps)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/api/opportunities/1/feedback", strings.NewReader(`{"label":"maybe"}`))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid label, got %d", rec.Code)
+	}
+}
+
+func TestScoringTrain_NotEnoughFeedback(t *testing.T) {
+	server := setupTestServer(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/scoring/train", nil)
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422, got %d", rec.Code)
+	}
+}
+
+func TestWatchlistFilterAndStats(t *testing.T) {
+	server := setupTestServer(t)
+
+	for i, title := range []string{"Invoicing for freelancers", "A new game engine"} {
+		if _, err := server.db.Exec(`
+			INSERT INTO opportunities (title, source, source_url, source_id_external)
+			VALUES (?, 'hackernews', 'https://example.com', ?)
+		`, title, i); err != nil {
+			t.Fatalf("failed to insert opportunity: %v", err)
+		}
+	}
+
+	body := `{"name":"invoicing","include_keywords":["invoicing"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/watchlists", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/opportunities?watchlist=invoicing", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	var opps []map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&opps); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(opps) != 1 || opps[0]["title"] != "Invoicing for freelancers" {
+		t.Errorf("expected only the invoicing opportunity, got %v", opps)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/opportunities/stats", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	var stats struct {
+		ByWatchlist map[string]int `json:"by_watchlist"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&stats); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if stats.ByWatchlist["invoicing"] != 1 {
+		t.Errorf("expected 1 opportunity in invoicing watchlist, got %v", stats.ByWatchlist)
+	}
+}
+
+func TestClustersAndCollapse(t *testing.T) {
+	server := setupTestServer(t)
+
+	for i, row := range []struct {
+		title string
+		score int
+	}{{"Widget on HN", 40}, {"acme/widget", 70}, {"Unrelated", 10}} {
+		if _, err := server.db.Exec(`
+			INSERT INTO opportunities (title, source, source_url, source_id_external, score)
+			VALUES (?, 'hackernews', 'https://example.com', ?, ?)
+		`, row.title, i, row.score); err != nil {
+			t.Fatalf("failed to insert opportunity: %v", err)
+		}
+	}
+	if _, err := server.db.Exec(`UPDATE opportunities SET cluster_id = 1 WHERE id IN (1, 2)`); err != nil {
+		t.Fatalf("failed to cluster opportunities: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/opportunities?collapse=true", nil)
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	var opps []map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&opps); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(opps) != 2 || opps[0]["title"] != "acme/widget" || opps[0]["cluster_size"] != float64(2) {
+		t.Errorf("expected best cluster member and unrelated opportunity, got %v", opps)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/clusters", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	var clusters []struct {
+		ID   int64 `json:"id"`
+		Size int   `json:"size"`
+		Best struct {
+			Title string `json:"title"`
+		} `json:"best"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&clusters); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(clusters) != 1 || clusters[0].Size != 2 || clusters[0].Best.Title != "acme/widget" {
+		t.Errorf("expected one cluster of two led by acme/widget, got %+v", clusters)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/clusters/99", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", rec.Code)
+	}
+}
+
+func TestTrends(t *testing.T) {
+	server := setupTestServer(t)
+
+	if _, err := server.db.Exec(`
+		INSERT INTO opportunities (title, source, source_url, source_id_external, score)
+		VALUES ('Hosting MCP servers', 'hackernews', 'https://example.com', '1', 50)
+	`); err != nil {
+		t.Fatalf("failed to insert opportunity: %v", err)
+	}
+	if _, err := server.db.Exec(`
+		INSERT INTO opportunity_topics (opportunity_id, topic, weight) VALUES (1, 'mcp servers', 2)
+	`); err != nil {
+		t.Fatalf("failed to insert topic: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/trends?min_count=1", nil)
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Days   int `json:"days"`
+		Trends []struct {
+			Topic string `json:"topic"`
+			Count int    `json:"count"`
+		} `json:"trends"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Days != 7 || len(resp.Trends) != 1 || resp.Trends[0].Topic != "mcp servers" || resp.Trends[0].Count != 1 {
+		t.Errorf("unexpected trends response: %+v", resp)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/opportunities?topic=MCP+servers", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	var opps []map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&opps); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(opps) != 1 || len(opps[0]["topics"].([]any)) != 1 {
+		t.Errorf("expected the tagged opportunity with its topics, got %v", opps)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/trends?sort=bogus", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestAIProviderSettings(t *testing.T) {
+	server := setupTestServer(t)
+
+	body := `{"api_key":"sk-test-1234567890","model":"gpt-4o-mini"}`
+	req := httptest.NewRequest(http.MethodPut, "/api/ai/providers/openai", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), "sk-test-1234567890") {
+		t.Errorf("expected API key to be masked, got %s", rec.Body.String())
+	}
+
+	var stored string
+	server.db.QueryRow(`SELECT api_key FROM ai_providers WHERE type = 'openai'`).Scan(&stored)
+	if stored == "" || strings.Contains(stored, "sk-test") {
+		t.Errorf("expected API key encrypted at rest, got %q", stored)
+	}
+
+	// Updating without a key keeps the stored one
+	req = httptest.NewRequest(http.MethodPut, "/api/ai/providers/openai", strings.NewReader(`{"model":"gpt-4o"}`))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	var resp struct {
+		Model     string `json:"model"`
+		APIKeySet bool   `json:"api_key_set"`
+		APIKey    string `json:"api_key"`
+		IsDefault bool   `json:"is_default"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Model != "gpt-4o" || !resp.APIKeySet || resp.APIKey != "••••7890" || !resp.IsDefault {
+		t.Errorf("unexpected provider settings: %+v", resp)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/api/ai/providers/unknown", strings.NewReader(`{}`))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for unknown provider, got %d", rec.Code)
+	}
+}
+
+func TestPromptAnalyze(t *testing.T) {
+	server := setupTestServer(t)
+
+	ollama := newFakeOllama(t)
+	defer ollama.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/prompts", strings.NewReader(`{"content_prompt":"Find themes"}`))
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	// Analyzing without a configured provider is a client error
+	req = httptest.NewRequest(http.MethodPost, "/api/prompts/1/analyze", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 without provider, got %d", rec.Code)
+	}
+
+	body := `{"base_url":"` + ollama.URL + `","model":"llama3"}`
+	req = httptest.NewRequest(http.MethodPut, "/api/ai/providers/ollama", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/prompts/1/analyze", strings.NewReader(`{"provider":"ollama"}`))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/prompts/1", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	var prompt struct {
+		AIAnalysis string `json:"ai_analysis"`
+		AIProvider string `json:"ai_provider"`
+		AIModel    string `json:"ai_model"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&prompt); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if prompt.AIAnalysis != "Analysis of: Find themes" || prompt.AIProvider != "ollama" || prompt.AIModel != "llama3" {
+		t.Errorf("expected stored analysis, got %+v", prompt)
+	}
+}
+
+// newFakeOllama serves /api/chat, answering "Analysis of: " plus the last message,
+// split into two chunks when streaming, and lists two models on /api/tags
+func newFakeOllama(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/tags" {
+			w.Write([]byte(`{"models":[{"name":"mistral:latest"},{"name":"llama3:latest"}]}`))
+			return
+		}
+
+		var req struct {
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+			Stream bool `json:"stream"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		last := req.Messages[len(req.Messages)-1].Content
+
+		if !req.Stream {
+			json.NewEncoder(w).Encode(map[string]any{
+				"message":           map[string]string{"role": "assistant", "content": "Analysis of: " + last},
+				"done":              true,
+				"prompt_eval_count": 10,
+				"eval_count":        5,
+			})
+			return
+		}
+
+		enc := json.NewEncoder(w)
+		enc.Encode(map[string]any{"message": map[string]string{"role": "assistant", "content": "Analysis of: "}})
+		enc.Encode(map[string]any{
+			"message":           map[string]string{"role": "assistant", "content": last},
+			"done":              true,
+			"prompt_eval_count": 10,
+			"eval_count":        5,
+		})
+	}))
+}
+
+func TestPromptAnalyzeStreamAndFollowUp(t *testing.T) {
+	server := setupTestServer(t)
+
+	ollama := newFakeOllama(t)
+	defer ollama.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/prompts", strings.NewReader(`{"content_prompt":"Find themes"}`))
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	body := `{"base_url":"` + ollama.URL + `","model":"llama3"}`
+	req = httptest.NewRequest(http.MethodPut, "/api/ai/providers/ollama", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	// Follow-ups need an analysis to follow up on
+	req = httptest.NewRequest(http.MethodPost, "/api/prompts/1/messages", strings.NewReader(`{"content":"Which is biggest?"}`))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected status 409 before analysis, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/prompts/1/analyze?stream=true", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected event stream, got %q", ct)
+	}
+
+	stream := rec.Body.String()
+	if strings.Count(stream, "event: chunk\n") != 2 {
+		t.Errorf("expected two chunk events, got:\n%s", stream)
+	}
+	if !strings.Contains(stream, "event: done\n") || !strings.Contains(stream, `"ai_analysis":"Analysis of: Find themes"`) {
+		t.Errorf("expected done event with saved analysis, got:\n%s", stream)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/prompts/1/messages", strings.NewReader(`{"content":"Which is biggest?"}`))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/prompts/1/messages", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	var messages []struct {
+		Role       string `json:"role"`
+		Content    string `json:"content"`
+		AIProvider string `json:"ai_provider"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&messages); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(messages) != 2 || messages[0].Role != "user" || messages[1].Content != "Analysis of: Which is biggest?" || messages[1].AIProvider != "ollama" {
+		t.Errorf("unexpected conversation: %+v", messages)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/prompts/1/messages", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected status 204, got %d", rec.Code)
+	}
+}
+
+func TestAIUsageAndMonthlyCap(t *testing.T) {
+	// A dollar per token makes every call cost 15 against a cap of 20
+	prices := ai.Prices{"ollama": {"llama3": {Input: 1e6, Output: 1e6}}}
+	server := setupMeteredServer(t, prices, 20)
+
+	ollama := newFakeOllama(t)
+	defer ollama.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/prompts", strings.NewReader(`{"content_prompt":"Find themes"}`))
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	body := `{"base_url":"` + ollama.URL + `","model":"llama3"}`
+	req = httptest.NewRequest(http.MethodPut, "/api/ai/providers/ollama", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	// The second call starts under the cap and takes spend over it
+	for i, path := range []string{"/api/prompts/1/analyze", "/api/prompts/1/analyze?stream=true"} {
+		req = httptest.NewRequest(http.MethodPost, path, nil)
+		rec = httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("call %d: expected status 200, got %d: %s", i, rec.Code, rec.Body.String())
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/prompts/1/analyze", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429 over the cap, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/ai/usage", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var usage ai.UsageSummary
+	if err := json.NewDecoder(rec.Body).Decode(&usage); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if usage.MonthSpend != 30 || usage.Remaining == nil || *usage.Remaining != 0 {
+		t.Errorf("expected spend of 30 with nothing remaining, got %+v", usage)
+	}
+	if len(usage.Daily) != 1 || usage.Daily[0].Calls != 2 || usage.Daily[0].PromptTokens != 20 {
+		t.Errorf("expected one day with two calls, got %+v", usage.Daily)
+	}
+	if len(usage.Monthly) != 1 || usage.Monthly[0].Cost != 30 {
+		t.Errorf("expected one month costing 30, got %+v", usage.Monthly)
+	}
+	if len(usage.Breakdown) != 1 || usage.Breakdown[0].Purpose != "analyze" || usage.Breakdown[0].Model != "llama3" {
+		t.Errorf("unexpected breakdown: %+v", usage.Breakdown)
+	}
+}
+
+func TestAIProviderFallback(t *testing.T) {
+	server := setupTestServer(t)
+
+	// OpenAI rejects the key, which is not worth retrying
+	var openaiCalls atomic.Int32
+	openai := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		openaiCalls.Add(1)
+		http.Error(w, `{"error":{"message":"invalid api key"}}`, http.StatusUnauthorized)
+	}))
+	defer openai.Close()
+
+	// Ollama is briefly unavailable, then answers
+	var ollamaCalls atomic.Int32
+	fake := newFakeOllama(t)
+	defer fake.Close()
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ollamaCalls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+			return
+		}
+		fake.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer ollama.Close()
+
+	for _, body := range []string{
+		`{"type":"openai","api_key":"sk-test","base_url":"` + openai.URL + `"}`,
+		`{"type":"ollama","base_url":"` + ollama.URL + `","model":"llama3"}`,
+	} {
+		var provider struct {
+			Type string `json:"type"`
+		}
+		json.Unmarshal([]byte(body), &provider)
+
+		req := httptest.NewRequest(http.MethodPut, "/api/ai/providers/"+provider.Type, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("failed to save %s: %d %s", provider.Type, rec.Code, rec.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/api/ai/fallback", strings.NewReader(`{"providers":["mistral"]}`))
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for unconfigured fallback, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/api/ai/fallback", strings.NewReader(`{"providers":["ollama"]}`))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/prompts", strings.NewReader(`{"content_prompt":"Find themes"}`))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	// OpenAI is the default, as the first provider saved
+	req = httptest.NewRequest(http.MethodPost, "/api/prompts/1/analyze", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var prompt struct {
+		AIAnalysis string `json:"ai_analysis"`
+		AIProvider string `json:"ai_provider"`
+		AIModel    string `json:"ai_model"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&prompt); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if prompt.AIProvider != "ollama" || prompt.AIModel != "llama3" || prompt.AIAnalysis != "Analysis of: Find themes" {
+		t.Errorf("expected the fallback to answer, got %+v", prompt)
+	}
+	if openaiCalls.Load() != 1 || ollamaCalls.Load() != 2 {
+		t.Errorf("expected 1 openai call and 2 ollama calls, got %d and %d", openaiCalls.Load(), ollamaCalls.Load())
+	}
+}
+
+func TestAIProviderTestAndModels(t *testing.T) {
+	server := setupTestServer(t)
+
+	ollama := newFakeOllama(t)
+	defer ollama.Close()
+
+	openai := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"message":"invalid api key"}}`, http.StatusUnauthorized)
+	}))
+	defer openai.Close()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ai/providers/ollama/models", nil)
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unconfigured provider, got %d", rec.Code)
+	}
+
+	body := `{"base_url":"` + ollama.URL + `","model":"llama3"}`
+	req = httptest.NewRequest(http.MethodPut, "/api/ai/providers/ollama", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/ai/providers/ollama/models", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	var list struct {
+		Models []ai.Model `json:"models"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(list.Models) != 2 || list.Models[0].ID != "llama3:latest" {
+		t.Errorf("expected sorted models from /api/tags, got %+v", list.Models)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/ai/providers/ollama/test", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	var result struct {
+		OK        bool   `json:"ok"`
+		Model     string `json:"model"`
+		ErrorKind string `json:"error_kind"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !result.OK || result.Model != "llama3" {
+		t.Errorf("expected a successful test, got %+v", result)
+	}
+
+	// Unsaved settings can be tried before saving them
+	body = `{"api_key":"sk-wrong","base_url":"` + openai.URL + `"}`
+	req = httptest.NewRequest(http.MethodPost, "/api/ai/providers/openai/test", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	result.OK = true
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.OK || result.ErrorKind != "auth" {
+		t.Errorf("expected an auth failure, got %+v", result)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/ai/providers/openai", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected testing not to save settings, got status %d", rec.Code)
+	}
+}
+
+func TestAIProviderKeyStaysWithBaseURL(t *testing.T) {
+	server := setupTestServer(t)
+
+	var leaked atomic.Int32
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.Header.Get("Authorization"), "sk-stored") {
+			leaked.Add(1)
+		}
+		http.Error(w, `{"error":{"message":"nope"}}`, http.StatusUnauthorized)
+	}))
+	defer other.Close()
+
+	body := `{"api_key":"sk-stored","base_url":"https://api.openai.com/v1","model":"gpt-4o-mini"}`
+	req := httptest.NewRequest(http.MethodPut, "/api/ai/providers/openai", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Testing another base URL without a key must not send the stored one there
+	body = `{"base_url":"` + other.URL + `"}`
+	req = httptest.NewRequest(http.MethodPost, "/api/ai/providers/openai/test", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for a new base URL without a key, got %d", rec.Code)
+	}
+
+	// Nor may saving a new base URL keep the stored key
+	req = httptest.NewRequest(http.MethodPut, "/api/ai/providers/openai", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 when saving a new base URL without a key, got %d", rec.Code)
+	}
+
+	// With its own key the new base URL can be tried
+	body = `{"api_key":"sk-other","base_url":"` + other.URL + `"}`
+	req = httptest.NewRequest(http.MethodPost, "/api/ai/providers/openai/test", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", rec.Code)
+	}
+
+	if leaked.Load() != 0 {
+		t.Errorf("expected the stored key never to reach another host, got %d requests", leaked.Load())
+	}
+}
+
+func TestPromptTemplates(t *testing.T) {
+	server := setupTestServer(t)
+
+	for i, title := range []string{"Invoicing pain", "Need a CRM"} {
+		if _, err := server.db.Exec(`
+			INSERT INTO opportunities (title, source, source_url, source_id_external, score)
+			VALUES (?, 'hackernews', 'https://example.com', ?, ?)
+		`, title, fmt.Sprint(i), 80-i*10); err != nil {
+			t.Fatalf("failed to insert opportunity: %v", err)
+		}
+	}
+	if _, err := server.db.Exec(`INSERT INTO watchlists (name) VALUES ('billing')`); err != nil {
+		t.Fatalf("failed to insert watchlist: %v", err)
+	}
+	if _, err := server.db.Exec(`INSERT INTO opportunity_watchlists (opportunity_id, watchlist_id) VALUES (1, 1)`); err != nil {
+		t.Fatalf("failed to tag opportunity: %v", err)
+	}
+
+	for _, body := range []string{
+		`{"name":"default","body":"x"}`,
+		`{"name":"broken","body":"{{.Nope}}"}`,
+		`{"name":"","body":"x"}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/prompt-templates", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400 for %s, got %d", body, rec.Code)
+		}
+	}
+
+	tmpl := `{{.Stats.Total}} ideas over {{.Period.Days}} day(s){{range .Watchlists}}; {{.Name}}: {{range .Opportunities}}{{.Title}}{{end}}{{end}}`
+	body, _ := json.Marshal(map[string]string{"name": "weekend", "body": tmpl})
+	req := httptest.NewRequest(http.MethodPost, "/api/prompt-templates", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/prompt-templates", bytes.NewReader(body))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("expected status 409 for duplicate name, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/prompt-templates", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	var templates []struct {
+		Name    string `json:"name"`
+		Builtin bool   `json:"builtin"`
+	}
+	json.NewDecoder(rec.Body).Decode(&templates)
+	if len(templates) != 2 || !templates[0].Builtin || templates[1].Name != "weekend" {
+		t.Errorf("expected built-in and saved templates, got %+v", templates)
+	}
+
+	want := "2 ideas over 1 day(s); billing: Invoicing pain"
+
+	req = httptest.NewRequest(http.MethodPost, "/api/prompt-templates/preview", strings.NewReader(`{"name":"weekend"}`))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	var preview struct {
+		Content string `json:"content"`
+	}
+	json.NewDecoder(rec.Body).Decode(&preview)
+	if preview.Content != want {
+		t.Errorf("expected preview %q, got %q", want, preview.Content)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/prompts/generate?template=weekend", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	var prompt struct {
+		ContentPrompt string `json:"content_prompt"`
+		Template      string `json:"template"`
+	}
+	json.NewDecoder(rec.Body).Decode(&prompt)
+	if prompt.ContentPrompt != want || prompt.Template != "weekend" {
+		t.Errorf("expected prompt rendered from template, got %+v", prompt)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/prompts/generate?template=missing", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown template, got %d", rec.Code)
+	}
+}
+
+func TestOpportunityAIAnalysis(t *testing.T) {
+	server := setupTestServer(t)
+
+	if _, err := server.db.Exec(`
+		INSERT INTO opportunities (title, source, source_url, source_id_external, ai_status, ai_provider, ai_analysis)
+		VALUES ('Invoicing pain', 'hackernews', 'https://example.com', '1', 'done', 'openai',
+			'{"problem":"p","audience":"a","competitors":["X"],"monetization":"high","effort":"low","score":80}')
+	`); err != nil {
+		t.Fatalf("failed to insert opportunity: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/opportunities/1", nil)
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	var opp struct {
+		AIStatus   string `json:"ai_status"`
+		AIProvider string `json:"ai_provider"`
+		AIAnalysis struct {
+			Monetization string   `json:"monetization"`
+			Competitors  []string `json:"competitors"`
+			Score        int      `json:"score"`
+		} `json:"ai_analysis"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&opp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if opp.AIStatus != "done" || opp.AIProvider != "openai" || opp.AIAnalysis.Score != 80 || len(opp.AIAnalysis.Competitors) != 1 {
+		t.Errorf("expected AI verdict in response, got %+v", opp)
+	}
+}
+
+func TestReportSchedules(t *testing.T) {
+	server := setupTestServer(t)
+
+	ollama := newFakeOllama(t)
+	defer ollama.Close()
+
+	received := make(chan alerts.ReportPayload, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload alerts.ReportPayload
+		json.NewDecoder(r.Body).Decode(&payload)
+		received <- payload
+	}))
+	defer hook.Close()
+
+	req := httptest.NewRequest(http.MethodPut, "/api/ai/providers/ollama", strings.NewReader(`{"base_url":"`+ollama.URL+`","model":"llama3"}`))
+	server.Handler().ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/alerts", strings.NewReader(`{"type":"webhook","name":"Team","destination":"`+hook.URL+`","enabled":true}`))
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201 creating alert, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	for i, score := range []int{40, 90} {
+		if _, err := server.db.Exec(`
+			INSERT INTO opportunities (title, source, source_url, source_id_external, score)
+			VALUES (?, 'hackernews', 'https://example.com', ?, ?)
+		`, fmt.Sprintf("Opportunity %d", i), i, score); err != nil {
+			t.Fatalf("failed to insert opportunity: %v", err)
+		}
+	}
+
+	for _, body := range []string{
+		`{"name":"Weekly","cron":"not a cron"}`,
+		`{"name":"Weekly","cron":"@weekly","template":"missing"}`,
+		`{"name":"Weekly","cron":"@weekly","ai_provider":"nope"}`,
+	} {
+		req = httptest.NewRequest(http.MethodPost, "/api/report-schedules", strings.NewReader(body))
+		rec = httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400 for %s, got %d", body, rec.Code)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/report-schedules", strings.NewReader(`{"name":"Monday digest","cron":"0 8 * * 1","ai_provider":"ollama"}`))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var created schedule.Schedule
+	json.NewDecoder(rec.Body).Decode(&created)
+	if created.PeriodHours != schedule.DefaultPeriodHours || !created.Enabled {
+		t.Errorf("expected enabled schedule with default period, got %+v", created)
+	}
+	if created.NextRunAt == nil || created.NextRunAt.Weekday() != 1 || created.NextRunAt.Hour() != 8 {
+		t.Errorf("expected next run on Monday at 8:00, got %v", created.NextRunAt)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/report-schedules/%d/run", created.ID), nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var run schedule.Run
+	json.NewDecoder(rec.Body).Decode(&run)
+	if run.OpportunityCount != 2 || !run.Analyzed || run.Delivered != 1 || len(run.Errors) != 0 {
+		t.Errorf("expected analyzed report delivered once, got %+v", run)
+	}
+
+	payload := <-received
+	if payload.Event != "report" || payload.URL != fmt.Sprintf("http://seer.test/api/prompts/%d/export?format=html", run.ReportID) {
+		t.Errorf("expected report link in digest, got %+v", payload)
+	}
+
+	// The link opens the report's print view
+	req = httptest.NewRequest(http.MethodGet, strings.TrimPrefix(payload.URL, "http://seer.test"), nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+		t.Errorf("expected the report link to open the report, got %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if len(payload.Top) != 2 || payload.Top[0].Score != 90 || !strings.HasPrefix(payload.Summary, "Analysis of: ") {
+		t.Errorf("expected top opportunities and analysis summary, got %+v", payload)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/report-schedules/%d", created.ID), nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	var stored schedule.Schedule
+	json.NewDecoder(rec.Body).Decode(&stored)
+	if stored.LastRunAt == nil || stored.LastReportID == nil || *stored.LastReportID != run.ReportID || stored.LastError != "" {
+		t.Errorf("expected recorded run, got %+v", stored)
+	}
+
+	// Disabled schedules are not scheduled
+	req = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/report-schedules/%d", created.ID), strings.NewReader(`{"enabled":false}`))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	var updated schedule.Schedule
+	json.NewDecoder(rec.Body).Decode(&updated)
+	if rec.Code != http.StatusOK || updated.NextRunAt != nil || updated.Cron != "0 8 * * 1" {
+		t.Errorf("expected disabled schedule keeping its cron, got %d %+v", rec.Code, updated)
+	}
+}
+
+func TestPromptExport(t *testing.T) {
+	server := setupTestServer(t)
+
+	if _, err := server.db.Exec(`
+		INSERT INTO opportunities (title, source, source_url, source_id_external, score, signals)
+		VALUES ('Spreadsheet pain', 'hackernews', 'https://example.com', '1', 70, '["pain"]')
+	`); err != nil {
+		t.Fatalf("failed to insert opportunity: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/prompts/generate", nil)
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	var prompt struct {
+		ID int64 `json:"id"`
+	}
+	json.NewDecoder(rec.Body).Decode(&prompt)
+
+	tests := []struct {
+		format      string
+		contentType string
+		disposition string
+		contains    string
+	}{
+		{"", "text/markdown; charset=utf-8", "attachment", "# Seer Opportunity Report"},
+		{"html", "text/html; charset=utf-8", "inline", "Spreadsheet pain"},
+		{"json", "application/json", "attachment", `"pain"`},
+		{"csv", "text/csv; charset=utf-8", "attachment", "1,1,Spreadsheet pain,hackernews,70,https://example.com,pain"},
+	}
+	for _, tt := range tests {
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/prompts/%d/export?format=%s", prompt.ID, tt.format), nil)
+		rec = httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("format %q: expected status 200, got %d", tt.format, rec.Code)
+		}
+		if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+			t.Errorf("format %q: expected content type %s, got %s", tt.format, tt.contentType, got)
+		}
+		if got := rec.Header().Get("Content-Disposition"); !strings.HasPrefix(got, tt.disposition+"; filename=\"seer-report-") {
+			t.Errorf("format %q: unexpected disposition %s", tt.format, got)
+		}
+		if !strings.Contains(rec.Body.String(), tt.contains) {
+			t.Errorf("format %q: expected body to contain %q, got %s", tt.format, tt.contains, rec.Body.String())
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/prompts/%d/export?format=pdf", prompt.ID), nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for unknown format, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/prompts/999/export", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for missing prompt, got %d", rec.Code)
+	}
+}
+
+func TestPromptDiff(t *testing.T) {
+	server := setupTestServer(t)
+
+	generate := func() int64 {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/prompts/generate", nil)
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+
+		var prompt struct {
+			ID int64 `json:"id"`
+		}
+		json.NewDecoder(rec.Body).Decode(&prompt)
+		return prompt.ID
+	}
+
+	for i, row := range []struct {
+		title string
+		score int
+	}{{"Steady", 50}, {"Rising", 40}, {"Gone", 70}} {
+		if _, err := server.db.Exec(`
+			INSERT INTO opportunities (title, source, source_url, source_id_external, score)
+			VALUES (?, 'hackernews', 'https://example.com', ?, ?)
+		`, row.title, i, row.score); err != nil {
+			t.Fatalf("failed to insert opportunity: %v", err)
+		}
+	}
+	first := generate()
+
+	if _, err := server.db.Exec(`UPDATE opportunities SET score = 65 WHERE title = 'Rising'`); err != nil {
+		t.Fatalf("failed to update score: %v", err)
+	}
+	if _, err := server.db.Exec(`DELETE FROM opportunities WHERE title = 'Gone'`); err != nil {
+		t.Fatalf("failed to delete opportunity: %v", err)
+	}
+	if _, err := server.db.Exec(`
+		INSERT INTO opportunities (title, source, source_url, source_id_external, score)
+		VALUES ('Fresh', 'github', 'https://example.com', 'x', 30)
+	`); err != nil {
+		t.Fatalf("failed to insert opportunity: %v", err)
+	}
+	second := generate()
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/prompts/%d/diff/%d", first, second), nil)
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var diff struct {
+		From struct {
+			OpportunityCount int  `json:"opportunity_count"`
+			Snapshot         bool `json:"snapshot"`
+		} `json:"from"`
+		New []struct {
+			Title string `json:"title"`
+		} `json:"new"`
+		Dropped []struct {
+			Title string `json:"title"`
+		} `json:"dropped"`
+		Movers []struct {
+			Opportunity struct {
+				Title string `json:"title"`
+			} `json:"opportunity"`
+			Change int `json:"change"`
+		} `json:"movers"`
+		Unchanged int `json:"unchanged"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&diff); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	// The first report keeps the deleted opportunity and the old score
+	if diff.From.OpportunityCount != 3 || !diff.From.Snapshot {
+		t.Errorf("expected snapshot of three opportunities, got %+v", diff.From)
+	}
+	if len(diff.New) != 1 || diff.New[0].Title != "Fresh" {
+		t.Errorf("expected new opportunity, got %+v", diff.New)
+	}
+	if len(diff.Dropped) != 1 || diff.Dropped[0].Title != "Gone" {
+		t.Errorf("expected dropped opportunity, got %+v", diff.Dropped)
+	}
+	if len(diff.Movers) != 1 || diff.Movers[0].Opportunity.Title != "Rising" || diff.Movers[0].Change != 25 {
+		t.Errorf("expected score mover, got %+v", diff.Movers)
+	}
+	if diff.Unchanged != 1 {
+		t.Errorf("expected one unchanged opportunity, got %d", diff.Unchanged)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/prompts/%d/diff/999", first), nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for missing prompt, got %d", rec.Code)
+	}
+}
+
+func TestSourceSecretStaysWithURL(t *testing.T) {
+	server := setupTestServer(t)
+
+	body := `{"type": "discourse", "name": "Forum", "url": "https://forum.example.com", "config": {"api_key": "admin-key"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/sources", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var source struct {
+		ID int64 `json:"id"`
+	}
+	json.NewDecoder(rec.Body).Decode(&source)
+	path := fmt.Sprintf("/api/sources/%d", source.ID)
+
+	update := func(body string) int {
+		req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+		return rec.Code
+	}
+	stored := func() (url, config string) {
+		server.db.QueryRow(`SELECT url, config FROM sources WHERE id = ?`, source.ID).Scan(&url, &config)
+		return url, config
+	}
+
+	// A stored key is not carried over to another host, masked or untouched
+	for _, body := range []string{
+		`{"url": "https://attacker.example", "config": {"api_key": "********"}}`,
+		`{"url": "https://attacker.example"}`,
+	} {
+		if code := update(body); code != http.StatusBadRequest {
+			t.Errorf("expected status 400 for %s, got %d", body, code)
+		}
+	}
+	if url, config := stored(); url != "https://forum.example.com" || config != `{"api_key":"admin-key"}` {
+		t.Errorf("expected the source unchanged, got %s %s", url, config)
+	}
+
+	// The masked key still works on the same URL, and a new URL takes a new key
+	if code := update(`{"url": "https://forum.example.com/", "config": {"api_key": "********"}}`); code != http.StatusOK {
+		t.Errorf("expected status 200 on the same URL, got %d", code)
+	}
+	if code := update(`{"url": "https://other.example", "config": {"api_key": "other-key"}}`); code != http.StatusOK {
+		t.Errorf("expected status 200 with a new key, got %d", code)
+	}
+	if url, config := stored(); url != "https://other.example" || config != `{"api_key":"other-key"}` {
+		t.Errorf("expected the new URL and key, got %s %s", url, config)
+	}
+}
+
+func TestSourceConfigAndRuns(t *testing.T) {
+	server := setupTestServer(t)
+
+	body := `{"type": "github", "name": "GitHub with token", "config": {"token": "ghp_secret", "queries": "topic:cli"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/sources", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var source struct {
+		ID     int64             `json:"id"`
+		Config map[string]string `json:"config"`
+	}
+	json.NewDecoder(rec.Body).Decode(&source)
+	if source.Config["token"] != "********" || source.Config["queries"] != "topic:cli" {
+		t.Errorf("expected masked token, got %v", source.Config)
+	}
+
+	// Sending the masked token back keeps the stored one
+	body = `{"config": {"token": "********", "queries": "topic:api"}}`
+	req = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/sources/%d", source.ID), strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var stored string
+	server.db.QueryRow(`SELECT config FROM sources WHERE id = ?`, source.ID).Scan(&stored)
+	if stored != `{"queries":"topic:api","token":"ghp_secret"}` {
+		t.Errorf("expected token to be kept, got %s", stored)
+	}
+
+	if _, err := server.db.Exec(`
+		INSERT INTO fetch_runs (source_id, started_at, finished_at, fetched, kept, warnings)
+		VALUES (?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, 12, 10, '["GitHub rate limit exhausted"]')
+	`, source.ID); err != nil {
+		t.Fatalf("failed to insert fetch run: %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/sources/%d/runs", source.ID), nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var runs []struct {
+		Fetched  int      `json:"fetched"`
+		Kept     int      `json:"kept"`
+		Warnings []string `json:"warnings"`
+	}
+	json.NewDecoder(rec.Body).Decode(&runs)
+	if len(runs) != 1 || runs[0].Fetched != 12 || runs[0].Kept != 10 || len(runs[0].Warnings) != 1 {
+		t.Errorf("unexpected runs %+v", runs)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/sources/999/runs", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for missing source, got %d", rec.Code)
+	}
+}
+
+func TestIngestWebhook(t *testing.T) {
+	base := setupTestServer(t)
+	server := NewServer(base.db, sources.NewManager(base.db.DB, 60), base.aiProviders, base.alerts, base.scheduler)
+
+	body := `{"type": "webhook", "name": "n8n", "config": {"token": "hook-token"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/sources", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var source struct {
+		ID int64 `json:"id"`
+	}
+	json.NewDecoder(rec.Body).Decode(&source)
+	path := fmt.Sprintf("/api/ingest/%d", source.ID)
+
+	payload := `[{"title": "Wish there was a simple uptime monitor", "source_url": "https://example.com/1"}, {"description": "no title"}]`
+
+	req = httptest.NewRequest(http.MethodPost, path, strings.NewReader(payload))
+	req.Header.Set("Authorization", "Bearer wrong")
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 for a wrong token, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, path, strings.NewReader(payload))
+	req.Header.Set("Authorization", "Bearer hook-token")
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var run struct {
+		Fetched  int      `json:"fetched"`
+		Kept     int      `json:"kept"`
+		Warnings []string `json:"warnings"`
+	}
+	json.NewDecoder(rec.Body).Decode(&run)
+	if run.Fetched != 1 || run.Kept != 1 || len(run.Warnings) != 1 {
+		t.Errorf("unexpected run %+v", run)
+	}
+
+	var count int
+	server.db.QueryRow(`SELECT COUNT(*) FROM opportunities WHERE source = 'webhook'`).Scan(&count)
+	if count != 1 {
+		t.Errorf("expected 1 stored opportunity, got %d", count)
+	}
+
+	// Only webhook sources receive deliveries
+	body = `{"type": "feed", "name": "Blog", "url": "https://example.com/feed.xml", "config": {"token": "hook-token"}}`
+	req = httptest.NewRequest(http.MethodPost, "/api/sources", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	json.NewDecoder(rec.Body).Decode(&source)
+
+	req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/ingest/%d", source.ID), strings.NewReader(payload))
+	req.Header.Set("Authorization", "Bearer hook-token")
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for a feed source, got %d", rec.Code)
+	}
+}
+
+// slowSource reports the state of the context its fetch ran with
+type slowSource struct {
+	done chan error
+}
+
+func (s *slowSource) Type() string { return "test-slow" }
+func (s *slowSource) Name() string { return "Slow" }
+
+func (s *slowSource) Fetch(ctx context.Context) ([]sources.Opportunity, error) {
+	time.Sleep(50 * time.Millisecond)
+	s.done <- ctx.Err()
+	return nil, ctx.Err()
+}
+
+func TestFetchSourcesOutlivesRequest(t *testing.T) {
+	base := setupTestServer(t)
+	manager := sources.NewManager(base.db.DB, 60)
+	server := NewServer(base.db, manager, base.aiProviders, base.alerts, base.scheduler)
+
+	slow := &slowSource{done: make(chan error, 1)}
+	manager.RegisterFactory("test-slow", func(cfg sources.SourceConfig) (sources.Source, error) {
+		return slow, nil
+	})
+	if err := sources.NewRepository(base.db.DB).Create(&sources.SourceRecord{Type: "test-slow", Name: "Slow", Config: "{}", Enabled: true}); err != nil {
+		t.Fatalf("failed to create source: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodPost, "/api/sources/fetch", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	cancel() // As the server does once the response is written
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	select {
+	case err := <-slow.done:
+		if err != nil {
+			t.Errorf("expected the fetch to outlive the request, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("fetch never ran")
+	}
+}
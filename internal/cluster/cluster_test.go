@@ -0,0 +1,220 @@
+package cluster
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/mx-seer/seer/internal/db"
+)
+
+func TestCanonicalURL(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"github repo", "https://github.com/Acme/Widget", "github.com/acme/widget"},
+		{"github deep link", "https://www.github.com/acme/widget/tree/main/docs", "github.com/acme/widget"},
+		{"github issue", "https://github.com/Acme/Widget/issues/12#issuecomment-1", "github.com/acme/widget/issues/12"},
+		{"github pull request", "https://github.com/acme/widget/pull/7/files", "github.com/acme/widget/pull/7"},
+		{"github discussion", "https://github.com/acme/widget/discussions/3", "github.com/acme/widget/discussions/3"},
+		{"github issue list", "https://github.com/acme/widget/issues", "github.com/acme/widget"},
+		{"gitlab merge request", "https://gitlab.com/acme/widget/-/merge_requests/5", "gitlab.com/acme/widget/merge_requests/5"},
+		{"bitbucket pull request", "https://bitbucket.org/acme/widget/pull-requests/9", "bitbucket.org/acme/widget/pull-requests/9"},
+		{"npm repository field", "git+https://github.com/acme/widget.git", "github.com/acme/widget"},
+		{"tracking params", "https://widget.dev/launch?utm_source=hn&ref=x", "widget.dev/launch"},
+		{"kept params", "https://widget.dev/?id=1", "widget.dev?id=1"},
+		{"discussion host", "https://news.ycombinator.com/item?id=1", ""},
+		{"code host without repo", "https://github.com/acme", ""},
+		{"not a url", "widget", ""},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanonicalURL(tt.raw); got != tt.want {
+				t.Errorf("CanonicalURL(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignatureSimilarity(t *testing.T) {
+	a := NewSignature("Show HN: Widget – an open source invoicing tool for freelancers")
+	b := NewSignature("Widget, an open-source invoicing tool for freelancers")
+	c := NewSignature("Why we rewrote our database in Rust")
+
+	if !a.Similar(b) {
+		t.Errorf("expected near-duplicate titles to be similar, got %.2f", a.Similarity(b))
+	}
+	if a.Similar(c) {
+		t.Errorf("expected unrelated titles not to be similar, got %.2f", a.Similarity(c))
+	}
+
+	if got := ParseSignature(a.String()); got.Similarity(a) != 1 {
+		t.Error("expected signature to round-trip through its encoding")
+	}
+
+	if NewSignature("ab") != nil {
+		t.Error("expected no signature for a title shorter than a shingle")
+	}
+}
+
+func TestStoreAssign(t *testing.T) {
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	defer database.Close()
+
+	store := NewStore(database.DB)
+
+	insert := func(title, source string) int64 {
+		t.Helper()
+		result, err := database.Exec(`
+			INSERT INTO opportunities (title, source, source_url, source_id_external)
+			VALUES (?, ?, '', ?)
+		`, title, source, title)
+		if err != nil {
+			t.Fatalf("failed to insert opportunity: %v", err)
+		}
+		id, _ := result.LastInsertId()
+		return id
+	}
+
+	hn := insert("Show HN: Widget", "hackernews")
+	gh := insert("acme/widget", "github")
+	devto := insert("Widget: an open source invoicing tool for freelancers", "devto")
+	other := insert("Why we rewrote our database in Rust", "devto")
+	npm := insert("Widget: an open source invoicing tool for freelancers!", "npm")
+
+	// Different titles, same project link
+	hnCluster, err := store.Assign(hn, "Show HN: Widget", []string{"https://widget.dev", "https://github.com/acme/widget"})
+	if err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	ghCluster, err := store.Assign(gh, "acme/widget", []string{"https://github.com/Acme/widget"})
+	if err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	if hnCluster != hn || ghCluster != hn {
+		t.Errorf("expected shared link to join cluster %d, got %d and %d", hn, hnCluster, ghCluster)
+	}
+
+	// Unrelated opportunities stay on their own
+	otherCluster, err := store.Assign(other, "Why we rewrote our database in Rust", nil)
+	if err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	if otherCluster != other {
+		t.Errorf("expected unrelated opportunity in its own cluster, got %d", otherCluster)
+	}
+
+	// Near-duplicate titles join, and a link match merges the two clusters
+	if _, err := store.Assign(devto, "Widget: an open source invoicing tool for freelancers", nil); err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	npmCluster, err := store.Assign(npm, "Widget: an open source invoicing tool for freelancers!", []string{"git+https://github.com/acme/widget.git"})
+	if err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	if npmCluster != hn {
+		t.Errorf("expected merged cluster %d, got %d", hn, npmCluster)
+	}
+
+	var size int
+	database.QueryRow(`SELECT COUNT(*) FROM opportunities WHERE cluster_id = ?`, hn).Scan(&size)
+	if size != 4 {
+		t.Errorf("expected 4 opportunities in merged cluster, got %d", size)
+	}
+}
+
+func TestStoreAssignKeepsIssuesApart(t *testing.T) {
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	defer database.Close()
+
+	store := NewStore(database.DB)
+
+	assign := func(title, url string) (int64, int64) {
+		t.Helper()
+		result, err := database.Exec(`
+			INSERT INTO opportunities (title, source, source_url, source_id_external)
+			VALUES (?, 'github_issues', ?, ?)
+		`, title, url, url)
+		if err != nil {
+			t.Fatalf("failed to insert opportunity: %v", err)
+		}
+		id, _ := result.LastInsertId()
+		cluster, err := store.Assign(id, title, []string{url})
+		if err != nil {
+			t.Fatalf("Assign() error = %v", err)
+		}
+		return id, cluster
+	}
+
+	repo, repoCluster := assign("acme/widget", "https://github.com/acme/widget")
+	first, firstCluster := assign("Support offline sync", "https://github.com/acme/widget/issues/12")
+	second, secondCluster := assign("Add a dark theme", "https://github.com/acme/widget/issues/40")
+
+	if repoCluster != repo || firstCluster != first || secondCluster != second {
+		t.Errorf("expected each issue in its own cluster, got %d, %d and %d", repoCluster, firstCluster, secondCluster)
+	}
+
+	// The same issue seen again still joins its own cluster
+	_, again := assign("Offline sync support?", "https://github.com/acme/widget/issues/12#issuecomment-9")
+	if again != first {
+		t.Errorf("expected the repeated issue to join cluster %d, got %d", first, again)
+	}
+}
+
+func TestStoreAssignConcurrent(t *testing.T) {
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	defer database.Close()
+
+	store := NewStore(database.DB)
+
+	const n = 8
+	ids := make([]int64, n)
+	for i := range ids {
+		result, err := database.Exec(`
+			INSERT INTO opportunities (title, source, source_url, source_id_external)
+			VALUES ('acme/widget', ?, 'https://github.com/acme/widget', ?)
+		`, fmt.Sprintf("source%d", i), fmt.Sprintf("id%d", i))
+		if err != nil {
+			t.Fatalf("failed to insert opportunity: %v", err)
+		}
+		ids[i], _ = result.LastInsertId()
+	}
+
+	// Saves of the same project from several sources at once share one cluster
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for _, id := range ids {
+		wg.Go(func() {
+			if _, err := store.Assign(id, "acme/widget", []string{"https://github.com/acme/widget"}); err != nil {
+				errs <- err
+			}
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Assign() error = %v", err)
+	}
+
+	var clusters int
+	if err := database.QueryRow(`SELECT COUNT(DISTINCT cluster_id) FROM opportunities`).Scan(&clusters); err != nil {
+		t.Fatalf("failed to count clusters: %v", err)
+	}
+	if clusters != 1 {
+		t.Errorf("expected a single cluster, got %d", clusters)
+	}
+}
@@ -0,0 +1,152 @@
+package cluster
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+const (
+	numHashes     = 32
+	numBands      = 8
+	rowsPerBand   = numHashes / numBands
+	shingleSize   = 3
+	minSimilarity = 0.6
+)
+
+// titlePrefixes are boilerplate prefixes that say nothing about the subject
+var titlePrefixes = []string{"show hn:", "ask hn:", "launch hn:", "tell hn:"}
+
+// Signature is a MinHash signature of a title's character shingles
+type Signature []uint64
+
+// NormalizeTitle lowercases a title, drops boilerplate prefixes and punctuation
+func NormalizeTitle(title string) string {
+	t := strings.ToLower(strings.TrimSpace(title))
+	for _, prefix := range titlePrefixes {
+		t = strings.TrimPrefix(t, prefix)
+	}
+
+	t = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, t)
+
+	return strings.Join(strings.Fields(t), " ")
+}
+
+// shingles returns the set of character n-grams of a normalized title
+func shingles(normalized string) map[string]bool {
+	runes := []rune(normalized)
+	set := make(map[string]bool)
+	for i := 0; i+shingleSize <= len(runes); i++ {
+		set[string(runes[i:i+shingleSize])] = true
+	}
+	return set
+}
+
+// NewSignature computes the MinHash signature of a title, or nil if it is too short
+func NewSignature(title string) Signature {
+	set := shingles(NormalizeTitle(title))
+	if len(set) == 0 {
+		return nil
+	}
+
+	sig := make(Signature, numHashes)
+	for i := range sig {
+		sig[i] = ^uint64(0)
+	}
+
+	for shingle := range set {
+		h := fnv.New64a()
+		h.Write([]byte(shingle))
+		base := h.Sum64()
+
+		for i := range sig {
+			if v := mix(base ^ uint64(i+1)*0x9e3779b97f4a7c15); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+
+	return sig
+}
+
+// Similarity estimates the Jaccard similarity of the titles behind two signatures
+func (s Signature) Similarity(other Signature) float64 {
+	if len(s) != numHashes || len(other) != numHashes {
+		return 0
+	}
+
+	equal := 0
+	for i := range s {
+		if s[i] == other[i] {
+			equal++
+		}
+	}
+
+	return float64(equal) / numHashes
+}
+
+// Similar reports whether two signatures are close enough to be clustered
+func (s Signature) Similar(other Signature) bool {
+	return s.Similarity(other) >= minSimilarity
+}
+
+// Bands returns the locality-sensitive hashing keys used to find candidate matches
+func (s Signature) Bands() []string {
+	if len(s) != numHashes {
+		return nil
+	}
+
+	bands := make([]string, numBands)
+	buf := make([]byte, 8)
+	for b := 0; b < numBands; b++ {
+		h := fnv.New64a()
+		for _, v := range s[b*rowsPerBand : (b+1)*rowsPerBand] {
+			binary.LittleEndian.PutUint64(buf, v)
+			h.Write(buf)
+		}
+		bands[b] = fmt.Sprintf("%d:%016x", b, h.Sum64())
+	}
+
+	return bands
+}
+
+// String encodes the signature for storage
+func (s Signature) String() string {
+	buf := make([]byte, 8*len(s))
+	for i, v := range s {
+		binary.LittleEndian.PutUint64(buf[i*8:], v)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// ParseSignature decodes a stored signature
+func ParseSignature(encoded string) Signature {
+	buf, err := hex.DecodeString(encoded)
+	if err != nil || len(buf) != 8*numHashes {
+		return nil
+	}
+
+	sig := make(Signature, numHashes)
+	for i := range sig {
+		sig[i] = binary.LittleEndian.Uint64(buf[i*8:])
+	}
+	return sig
+}
+
+// mix is the splitmix64 finalizer, used to derive independent hash functions
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
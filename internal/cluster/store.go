@@ -0,0 +1,236 @@
+package cluster
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Store assigns opportunities to clusters in the database
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates a new cluster store
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Assign links an opportunity to every stored opportunity that shares a canonical
+// URL or has a near-duplicate title, merging their clusters. Opportunities with no
+// match form a cluster of their own. It returns the resulting cluster ID.
+func (s *Store) Assign(id int64, title string, urls []string) (int64, error) {
+	links := canonicalSet(urls)
+	sig := NewSignature(title)
+	bands := sig.Bands()
+
+	// Matching and merging happen in one transaction so concurrent saves of
+	// duplicates see each other and end up in the same cluster
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := index(tx, id, sig, links, bands); err != nil {
+		return 0, err
+	}
+
+	matched, err := matchingLinks(tx, id, links)
+	if err != nil {
+		return 0, err
+	}
+
+	similar, err := similarTitles(tx, id, sig, bands)
+	if err != nil {
+		return 0, err
+	}
+	matched = append(matched, similar...)
+
+	// Collect every cluster touched by this opportunity and its matches
+	members := append([]int64{id}, matched...)
+	query := fmt.Sprintf(`SELECT DISTINCT COALESCE(cluster_id, id) FROM opportunities WHERE id IN (%s)`, placeholders(len(members)))
+	rows, err := tx.Query(query, int64Args(members)...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query clusters: %w", err)
+	}
+
+	target := id
+	var clusters []int64
+	for rows.Next() {
+		var clusterID int64
+		if err := rows.Scan(&clusterID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan cluster: %w", err)
+		}
+		clusters = append(clusters, clusterID)
+		if clusterID < target {
+			target = clusterID
+		}
+	}
+	rows.Close()
+
+	// The oldest opportunity's ID names the merged cluster
+	for _, m := range members {
+		if m < target {
+			target = m
+		}
+	}
+
+	query = fmt.Sprintf(`UPDATE opportunities SET cluster_id = ? WHERE id IN (%s) OR cluster_id IN (%s)`,
+		placeholders(len(members)), placeholders(len(clusters)))
+	args := append([]any{target}, int64Args(members)...)
+	args = append(args, int64Args(clusters)...)
+	if _, err := tx.Exec(query, args...); err != nil {
+		return 0, fmt.Errorf("failed to merge clusters: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return target, nil
+}
+
+// index replaces the stored links, title signature and LSH bands of an opportunity
+func index(tx *sql.Tx, id int64, sig Signature, links, bands []string) error {
+	if _, err := tx.Exec(`DELETE FROM opportunity_links WHERE opportunity_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to clear links: %w", err)
+	}
+	for _, link := range links {
+		if _, err := tx.Exec(`
+			INSERT OR IGNORE INTO opportunity_links (opportunity_id, canonical_url) VALUES (?, ?)
+		`, id, link); err != nil {
+			return fmt.Errorf("failed to save link: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM opportunity_title_bands WHERE opportunity_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to clear title bands: %w", err)
+	}
+	for _, band := range bands {
+		if _, err := tx.Exec(`
+			INSERT OR IGNORE INTO opportunity_title_bands (opportunity_id, band) VALUES (?, ?)
+		`, id, band); err != nil {
+			return fmt.Errorf("failed to save title band: %w", err)
+		}
+	}
+
+	var encoded sql.NullString
+	if sig != nil {
+		encoded = sql.NullString{String: sig.String(), Valid: true}
+	}
+	if _, err := tx.Exec(`UPDATE opportunities SET title_signature = ? WHERE id = ?`, encoded, id); err != nil {
+		return fmt.Errorf("failed to save title signature: %w", err)
+	}
+
+	return nil
+}
+
+// matchingLinks returns opportunities sharing any canonical URL
+func matchingLinks(tx *sql.Tx, id int64, links []string) ([]int64, error) {
+	if len(links) == 0 {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT DISTINCT opportunity_id FROM opportunity_links
+		WHERE canonical_url IN (%s) AND opportunity_id != ?
+	`, placeholders(len(links)))
+
+	args := make([]any, 0, len(links)+1)
+	for _, link := range links {
+		args = append(args, link)
+	}
+	args = append(args, id)
+
+	return queryIDs(tx, query, args...)
+}
+
+// similarTitles returns opportunities whose title signatures are near-duplicates
+func similarTitles(tx *sql.Tx, id int64, sig Signature, bands []string) ([]int64, error) {
+	if len(bands) == 0 {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT DISTINCT o.id, o.title_signature
+		FROM opportunity_title_bands b
+		JOIN opportunities o ON o.id = b.opportunity_id
+		WHERE b.band IN (%s) AND b.opportunity_id != ?
+	`, placeholders(len(bands)))
+
+	args := make([]any, 0, len(bands)+1)
+	for _, band := range bands {
+		args = append(args, band)
+	}
+	args = append(args, id)
+
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query title candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var similar []int64
+	for rows.Next() {
+		var candidate int64
+		var encoded sql.NullString
+		if err := rows.Scan(&candidate, &encoded); err != nil {
+			return nil, fmt.Errorf("failed to scan title candidate: %w", err)
+		}
+		// Band collisions are only candidates; confirm with the full signature
+		if sig.Similar(ParseSignature(encoded.String)) {
+			similar = append(similar, candidate)
+		}
+	}
+
+	return similar, rows.Err()
+}
+
+func queryIDs(tx *sql.Tx, query string, args ...any) ([]int64, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query matches: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan match: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// canonicalSet canonicalizes and de-duplicates URLs, dropping ones that identify nothing
+func canonicalSet(urls []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, u := range urls {
+		if c := CanonicalURL(u); c != "" && !seen[c] {
+			seen[c] = true
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func placeholders(n int) string {
+	if n == 0 {
+		return "NULL"
+	}
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+func int64Args(ids []int64) []any {
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return args
+}
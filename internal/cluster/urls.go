@@ -0,0 +1,101 @@
+package cluster
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// discussionHosts are hosts whose URLs identify a discussion rather than the
+// project being discussed, so they never link opportunities together
+var discussionHosts = map[string]bool{
+	"news.ycombinator.com": true,
+	"reddit.com":           true,
+	"twitter.com":          true,
+	"x.com":                true,
+}
+
+// codeHosts are hosts where only the owner/repo part of the path identifies a project
+var codeHosts = map[string]bool{
+	"github.com":    true,
+	"gitlab.com":    true,
+	"bitbucket.org": true,
+	"codeberg.org":  true,
+}
+
+// threadKinds are the code host path segments, followed by a number, that name an
+// issue, pull request or discussion; each thread is its own subject, not the project
+var threadKinds = map[string]bool{
+	"issues":         true,
+	"pull":           true,
+	"pulls":          true,
+	"pull-requests":  true,
+	"merge_requests": true,
+	"discussions":    true,
+}
+
+// CanonicalURL normalizes a URL so the same project links compare equal across sources.
+// It returns an empty string for URLs that cannot identify a project.
+func CanonicalURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimPrefix(raw, "git+")
+	if raw == "" {
+		return ""
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+
+	host := strings.ToLower(u.Hostname())
+	host = strings.TrimPrefix(host, "www.")
+	if discussionHosts[host] {
+		return ""
+	}
+
+	path := strings.Trim(u.Path, "/")
+	if codeHosts[host] {
+		parts := strings.Split(path, "/")
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return ""
+		}
+		path = strings.ToLower(parts[0] + "/" + strings.TrimSuffix(parts[1], ".git"))
+		if thread := codeHostThread(parts[2:]); thread != "" {
+			path += "/" + thread
+		}
+	}
+
+	// Tracking parameters and fragments do not change what a link points to
+	query := u.Query()
+	for key := range query {
+		if strings.HasPrefix(key, "utm_") || key == "ref" || key == "source" {
+			query.Del(key)
+		}
+	}
+
+	canonical := host
+	if path != "" {
+		canonical += "/" + path
+	}
+	if !codeHosts[host] && len(query) > 0 {
+		canonical += "?" + query.Encode()
+	}
+
+	return canonical
+}
+
+// codeHostThread returns the "kind/number" of an issue, pull request or discussion
+// path after owner/repo, or an empty string. GitLab puts a "-" segment before it.
+func codeHostThread(parts []string) string {
+	if len(parts) > 0 && parts[0] == "-" {
+		parts = parts[1:]
+	}
+	if len(parts) < 2 || !threadKinds[parts[0]] {
+		return ""
+	}
+	if _, err := strconv.Atoi(parts[1]); err != nil {
+		return ""
+	}
+	return parts[0] + "/" + parts[1]
+}
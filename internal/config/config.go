@@ -2,7 +2,11 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -12,17 +16,71 @@ type Config struct {
 	Server   ServerConfig   `yaml:"server"`
 	Database DatabaseConfig `yaml:"database"`
 	Sources  SourcesConfig  `yaml:"sources"`
+	AI       AIConfig       `yaml:"ai"`
+	Alerts   AlertsConfig   `yaml:"alerts"`
+}
+
+// AlertsConfig holds alert delivery settings
+type AlertsConfig struct {
+	SMTP SMTPConfig `yaml:"smtp"`
+}
+
+// SMTPConfig holds the mail server email alerts are sent through
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"` // Default: 587
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+// AIConfig holds AI integration settings
+type AIConfig struct {
+	// SecretKey encrypts stored provider API keys (SEER_SECRET_KEY overrides it)
+	SecretKey string `yaml:"secret_key"`
+	// KeyFile holds a generated secret key when SecretKey is not set
+	// (default: secret.key next to the database)
+	KeyFile string `yaml:"key_file"`
+
+	Enrichment EnrichmentConfig `yaml:"enrichment"`
+	// MonthlyCap is the AI spend in US dollars per calendar month after which provider
+	// calls fail; zero disables the cap
+	MonthlyCap float64 `yaml:"monthly_cap"`
+	// Prices overrides the built-in model prices, by provider type and model name
+	Prices map[string]map[string]PriceConfig `yaml:"prices"`
+}
+
+// PriceConfig is the price of a model in US dollars per million tokens
+type PriceConfig struct {
+	Input  float64 `yaml:"input"`
+	Output float64 `yaml:"output"`
+}
+
+// EnrichmentConfig holds settings for the background per-opportunity AI enrichment
+type EnrichmentConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	MinScore    int    `yaml:"min_score"`    // Lowest opportunity score that gets enriched
+	Concurrency int    `yaml:"concurrency"`  // Provider calls in flight at once
+	DailyBudget int    `yaml:"daily_budget"` // Maximum provider calls per day
+	Provider    string `yaml:"provider"`     // AI provider type (default: the default provider)
+	Interval    int    `yaml:"interval"`     // Minutes between enrichment runs
 }
 
 // SourcesConfig holds source fetching settings
 type SourcesConfig struct {
 	FetchInterval int `yaml:"fetch_interval"` // Interval in minutes between fetches
+	// AllowExec lets exec sources run local commands; anyone who can reach the API
+	// can then run commands on this host, so it is off by default
+	AllowExec bool `yaml:"allow_exec"`
 }
 
 // ServerConfig holds HTTP server settings
 type ServerConfig struct {
 	Host string `yaml:"host"`
 	Port int    `yaml:"port"`
+	// PublicURL is the address users reach Seer at, used for links in alerts
+	// (default: http://localhost:<port>)
+	PublicURL string `yaml:"public_url"`
 }
 
 // DatabaseConfig holds database settings
@@ -43,6 +101,14 @@ func Default() *Config {
 		Sources: SourcesConfig{
 			FetchInterval: 60, // Default: 1 hour
 		},
+		AI: AIConfig{
+			Enrichment: EnrichmentConfig{
+				MinScore:    60,
+				Concurrency: 2,
+				DailyBudget: 50,
+				Interval:    15,
+			},
+		},
 	}
 }
 
@@ -65,6 +131,35 @@ func Load(path string) (*Config, error) {
 	return cfg, nil
 }
 
+// SecretKey returns the configured secret key, preferring the SEER_SECRET_KEY environment variable
+func (c *Config) SecretKey() string {
+	if key := os.Getenv("SEER_SECRET_KEY"); key != "" {
+		return key
+	}
+	return c.AI.SecretKey
+}
+
+// KeyFile returns the path of the generated secret key file
+func (c *Config) KeyFile() string {
+	if c.AI.KeyFile != "" {
+		return c.AI.KeyFile
+	}
+	return filepath.Join(filepath.Dir(c.Database.Path), "secret.key")
+}
+
+// PublicURL returns the base URL of the web interface, without a trailing slash
+func (c *Config) PublicURL() string {
+	if c.Server.PublicURL != "" {
+		return strings.TrimRight(c.Server.PublicURL, "/")
+	}
+
+	host := c.Server.Host
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return fmt.Sprintf("http://%s", net.JoinHostPort(host, strconv.Itoa(c.Server.Port)))
+}
+
 // Address returns the server address in host:port format
 func (c *Config) Address() string {
 	return fmt.Sprintf("%s:%d", c.Server.Host, c.Server.Port)
@@ -104,3 +104,44 @@ func TestAddress(t *testing.T) {
 		t.Errorf("expected %s, got %s", expected, cfg.Address())
 	}
 }
+
+func TestPublicURL(t *testing.T) {
+	cfg := Default()
+
+	if got := cfg.PublicURL(); got != "http://localhost:8080" {
+		t.Errorf("expected localhost URL for a wildcard host, got %s", got)
+	}
+
+	cfg.Server.Host = "10.0.0.5"
+	if got := cfg.PublicURL(); got != "http://10.0.0.5:8080" {
+		t.Errorf("expected URL of the listen address, got %s", got)
+	}
+
+	cfg.Server.PublicURL = "https://seer.example.com/"
+	if got := cfg.PublicURL(); got != "https://seer.example.com" {
+		t.Errorf("expected configured URL without trailing slash, got %s", got)
+	}
+}
+
+func TestSecretKeyAndKeyFile(t *testing.T) {
+	cfg := Default()
+
+	if got := cfg.KeyFile(); got != filepath.Join("data", "secret.key") {
+		t.Errorf("expected key file next to the database, got %s", got)
+	}
+
+	cfg.AI.KeyFile = "/etc/seer/key"
+	if got := cfg.KeyFile(); got != "/etc/seer/key" {
+		t.Errorf("expected configured key file, got %s", got)
+	}
+
+	cfg.AI.SecretKey = "from-config"
+	if got := cfg.SecretKey(); got != "from-config" {
+		t.Errorf("expected configured secret key, got %s", got)
+	}
+
+	t.Setenv("SEER_SECRET_KEY", "from-env")
+	if got := cfg.SecretKey(); got != "from-env" {
+		t.Errorf("expected environment secret key, got %s", got)
+	}
+}
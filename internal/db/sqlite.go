@@ -86,6 +86,198 @@ var migrations = []string{
 		verified_at DATETIME,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);`,
+
+	// Migration 4: Opportunity metadata and triage feedback for learned scoring
+	`ALTER TABLE opportunities ADD COLUMN metadata TEXT DEFAULT '{}';`,
+
+	`CREATE TABLE IF NOT EXISTS opportunity_feedback (
+		opportunity_id INTEGER PRIMARY KEY REFERENCES opportunities(id) ON DELETE CASCADE,
+		label TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`,
+
+	// Migration 5: Named watchlists and opportunity tags
+	`CREATE TABLE IF NOT EXISTS watchlists (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE,
+		keywords TEXT DEFAULT '{}',
+		sources TEXT DEFAULT '[]',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`,
+
+	`CREATE TABLE IF NOT EXISTS opportunity_watchlists (
+		opportunity_id INTEGER NOT NULL REFERENCES opportunities(id) ON DELETE CASCADE,
+		watchlist_id INTEGER NOT NULL REFERENCES watchlists(id) ON DELETE CASCADE,
+		PRIMARY KEY (opportunity_id, watchlist_id)
+	);`,
+
+	`CREATE INDEX IF NOT EXISTS idx_opportunity_watchlists_watchlist ON opportunity_watchlists(watchlist_id);`,
+
+	// Migration 6: Cross-source clustering by canonical URL and title similarity
+	`ALTER TABLE opportunities ADD COLUMN cluster_id INTEGER;`,
+
+	`ALTER TABLE opportunities ADD COLUMN title_signature TEXT;`,
+
+	`CREATE INDEX IF NOT EXISTS idx_opportunities_cluster ON opportunities(cluster_id);`,
+
+	`CREATE TABLE IF NOT EXISTS opportunity_links (
+		opportunity_id INTEGER NOT NULL REFERENCES opportunities(id) ON DELETE CASCADE,
+		canonical_url TEXT NOT NULL,
+		PRIMARY KEY (opportunity_id, canonical_url)
+	);`,
+
+	`CREATE INDEX IF NOT EXISTS idx_opportunity_links_url ON opportunity_links(canonical_url);`,
+
+	`CREATE TABLE IF NOT EXISTS opportunity_title_bands (
+		opportunity_id INTEGER NOT NULL REFERENCES opportunities(id) ON DELETE CASCADE,
+		band TEXT NOT NULL,
+		PRIMARY KEY (opportunity_id, band)
+	);`,
+
+	`CREATE INDEX IF NOT EXISTS idx_opportunity_title_bands_band ON opportunity_title_bands(band);`,
+
+	// Migration 7: Extracted topics for trend tracking
+	`ALTER TABLE opportunities ADD COLUMN topics_at DATETIME;`,
+
+	`CREATE TABLE IF NOT EXISTS opportunity_topics (
+		opportunity_id INTEGER NOT NULL REFERENCES opportunities(id) ON DELETE CASCADE,
+		topic TEXT NOT NULL,
+		weight REAL NOT NULL DEFAULT 0,
+		PRIMARY KEY (opportunity_id, topic)
+	);`,
+
+	`CREATE INDEX IF NOT EXISTS idx_opportunity_topics_topic ON opportunity_topics(topic);`,
+
+	// Migration 8: AI provider settings and report analysis provenance
+	`CREATE TABLE IF NOT EXISTS ai_providers (
+		type TEXT PRIMARY KEY,
+		api_key TEXT,
+		base_url TEXT,
+		model TEXT,
+		options TEXT DEFAULT '{}',
+		is_default BOOLEAN DEFAULT false,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`,
+
+	`ALTER TABLE reports ADD COLUMN ai_provider TEXT;`,
+
+	`ALTER TABLE reports ADD COLUMN ai_model TEXT;`,
+
+	`ALTER TABLE reports ADD COLUMN ai_analyzed_at DATETIME;`,
+
+	// Migration 9: Per-opportunity AI enrichment state
+	`ALTER TABLE opportunities ADD COLUMN ai_status TEXT;`,
+
+	`ALTER TABLE opportunities ADD COLUMN ai_error TEXT;`,
+
+	`ALTER TABLE opportunities ADD COLUMN ai_attempts INTEGER DEFAULT 0;`,
+
+	`ALTER TABLE opportunities ADD COLUMN ai_attempted_at DATETIME;`,
+
+	`ALTER TABLE opportunities ADD COLUMN ai_provider TEXT;`,
+
+	`CREATE INDEX IF NOT EXISTS idx_opportunities_ai_status ON opportunities(ai_status, score);`,
+
+	// Migration 10: Follow-up conversations about reports
+	`CREATE TABLE IF NOT EXISTS report_messages (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		report_id INTEGER NOT NULL REFERENCES reports(id) ON DELETE CASCADE,
+		role TEXT NOT NULL,
+		content TEXT NOT NULL,
+		ai_provider TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`,
+
+	`CREATE INDEX IF NOT EXISTS idx_report_messages_report ON report_messages(report_id, id);`,
+
+	// Migration 11: Token usage and cost of AI provider calls
+	`CREATE TABLE IF NOT EXISTS ai_usage (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		provider TEXT NOT NULL,
+		model TEXT NOT NULL,
+		purpose TEXT,
+		prompt_tokens INTEGER NOT NULL DEFAULT 0,
+		completion_tokens INTEGER NOT NULL DEFAULT 0,
+		cost REAL NOT NULL DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`,
+
+	`CREATE INDEX IF NOT EXISTS idx_ai_usage_created ON ai_usage(created_at);`,
+
+	// Migration 12: Order in which AI providers are tried when the requested one fails
+	`ALTER TABLE ai_providers ADD COLUMN fallback_position INTEGER;`,
+
+	// Migration 13: Named prompt templates for report generation
+	`CREATE TABLE IF NOT EXISTS prompt_templates (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE,
+		description TEXT,
+		body TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`,
+
+	`ALTER TABLE reports ADD COLUMN template TEXT;`,
+
+	// Migration 14: Reports generated on a schedule and delivered through alerts
+	`CREATE TABLE IF NOT EXISTS report_schedules (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE,
+		cron TEXT NOT NULL,
+		period_hours INTEGER NOT NULL DEFAULT 168,
+		template TEXT,
+		ai_provider TEXT,
+		alert_ids TEXT DEFAULT '[]',
+		enabled BOOLEAN DEFAULT true,
+		last_run_at DATETIME,
+		last_report_id INTEGER,
+		last_error TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`,
+
+	// Migration 15: Opportunities captured by each report, as they were when it was generated.
+	// Rows outlive deleted opportunities, so opportunity_id has no foreign key.
+	`CREATE TABLE IF NOT EXISTS report_opportunities (
+		report_id INTEGER NOT NULL REFERENCES reports(id) ON DELETE CASCADE,
+		opportunity_id INTEGER NOT NULL,
+		position INTEGER NOT NULL,
+		title TEXT NOT NULL,
+		description TEXT,
+		source TEXT NOT NULL,
+		source_url TEXT,
+		score INTEGER NOT NULL,
+		signals TEXT DEFAULT '[]',
+		watchlists TEXT DEFAULT '[]',
+		detected_at DATETIME,
+		PRIMARY KEY (report_id, opportunity_id)
+	);`,
+
+	`ALTER TABLE reports ADD COLUMN snapshot BOOLEAN DEFAULT false;`,
+
+	// Migration 16: State sources keep between fetches, such as HTTP cache validators
+	`ALTER TABLE sources ADD COLUMN state TEXT DEFAULT '{}';`,
+
+	// Migration 17: Log of source fetches with their outcome and warnings
+	`CREATE TABLE IF NOT EXISTS fetch_runs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		source_id INTEGER NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
+		started_at DATETIME NOT NULL,
+		finished_at DATETIME NOT NULL,
+		fetched INTEGER DEFAULT 0,
+		kept INTEGER DEFAULT 0,
+		error TEXT,
+		warnings TEXT DEFAULT '[]'
+	);`,
+
+	`CREATE INDEX IF NOT EXISTS idx_fetch_runs_source ON fetch_runs(source_id, started_at);`,
+
+	// Migration 18: Points added by boost keywords, kept apart from the scorer's signals
+	`ALTER TABLE opportunities ADD COLUMN keyword_boost INTEGER DEFAULT 0;`,
+
+	`UPDATE opportunities SET signals = (
+		SELECT json_group_array(value) FROM json_each(opportunities.signals) WHERE value != 'keyword_boost'
+	) WHERE signals LIKE '%"keyword_boost"%';`,
 }
 
 // New creates a new database connection and runs migrations
@@ -96,8 +288,9 @@ func New(dbPath string) (*DB, error) {
 		return nil, fmt.Errorf("failed to create data directory: %w", err)
 	}
 
-	// Open database with WAL mode for better concurrency
-	db, err := sql.Open("sqlite", dbPath+"?_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)")
+	// Open database with WAL mode for better concurrency; sources save in
+	// parallel, so wait on locks instead of failing immediately
+	db, err := sql.Open("sqlite", dbPath+"?_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
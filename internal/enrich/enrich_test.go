@@ -0,0 +1,226 @@
+package enrich
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mx-seer/seer/internal/ai"
+	"github.com/mx-seer/seer/internal/db"
+)
+
+func TestParseVerdict(t *testing.T) {
+	response := "Here you go:\n```json\n" + `{
+		"problem": " Freelancers lose track of invoices ",
+		"audience": "Freelancers",
+		"competitors": ["FreshBooks", "freshbooks", ""],
+		"monetization": "High",
+		"effort": "medium",
+		"score": 72
+	}` + "\n```"
+
+	v, err := ParseVerdict(response)
+	if err != nil {
+		t.Fatalf("ParseVerdict() error = %v", err)
+	}
+	if v.Problem != "Freelancers lose track of invoices" || v.Monetization != "high" || v.Score != 72 {
+		t.Errorf("unexpected verdict: %+v", v)
+	}
+	if len(v.Competitors) != 1 {
+		t.Errorf("expected duplicate and empty competitors dropped, got %v", v.Competitors)
+	}
+
+	invalid := []string{
+		"no json here",
+		`{"problem": "x", "audience": "y", "monetization": "huge", "effort": "low", "score": 10}`,
+		`{"problem": "x", "audience": "y", "monetization": "low", "effort": "low", "score": 150}`,
+		`{"problem": "", "audience": "y", "monetization": "low", "effort": "low", "score": 10}`,
+	}
+	for _, response := range invalid {
+		if _, err := ParseVerdict(response); err == nil {
+			t.Errorf("expected error for %q", response)
+		}
+	}
+}
+
+// stubProvider answers with a fixed verdict, or garbage for titles containing "broken"
+type stubProvider struct {
+	calls *atomic.Int32
+}
+
+func (p *stubProvider) Name() string    { return "enrich-stub" }
+func (p *stubProvider) Available() bool { return true }
+
+func (p *stubProvider) Analyze(ctx context.Context, prompt string) (string, error) {
+	p.calls.Add(1)
+	if strings.Contains(prompt, "broken") {
+		return "I cannot help with that", nil
+	}
+	return `{"problem":"p","audience":"a","competitors":[],"monetization":"low","effort":"low","score":55}`, nil
+}
+
+func (p *stubProvider) Chat(ctx context.Context, req ai.ChatRequest) (*ai.Response, error) {
+	content, err := p.Analyze(ctx, req.Messages[len(req.Messages)-1].Content)
+	if err != nil {
+		return nil, err
+	}
+	return &ai.Response{Content: content}, nil
+}
+
+func (p *stubProvider) Stream(ctx context.Context, req ai.ChatRequest) (<-chan ai.Chunk, error) {
+	resp, err := p.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	chunks := make(chan ai.Chunk, 1)
+	chunks <- ai.Chunk{Content: resp.Content}
+	close(chunks)
+	return chunks, nil
+}
+
+func TestWorkerRunOnce(t *testing.T) {
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	defer database.Close()
+
+	calls := &atomic.Int32{}
+	ai.Register("enrich-stub", func(cfg ai.ProviderConfig) (ai.Provider, error) {
+		return &stubProvider{calls: calls}, nil
+	})
+
+	cipher, _ := ai.NewCipher(make([]byte, 32))
+	providers := ai.NewStore(database.DB, cipher, ai.NewMeter(database.DB, nil, 0))
+	if err := providers.Save(&ai.Settings{Type: "enrich-stub"}); err != nil {
+		t.Fatalf("failed to save provider: %v", err)
+	}
+
+	for i, row := range []struct {
+		title string
+		score int
+	}{{"Invoicing pain", 90}, {"broken response", 80}, {"Another idea", 70}, {"Low score", 10}} {
+		if _, err := database.Exec(`
+			INSERT INTO opportunities (title, source, source_url, source_id_external, score)
+			VALUES (?, 'hackernews', '', ?, ?)
+		`, row.title, fmt.Sprint(i), row.score); err != nil {
+			t.Fatalf("failed to insert opportunity: %v", err)
+		}
+	}
+
+	w := NewWorker(database.DB, providers, Options{MinScore: 50, DailyBudget: 2, Concurrency: 2})
+
+	result, err := w.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if result.Enriched != 1 || result.Failed != 1 || result.Remaining != 0 {
+		t.Errorf("expected the two best opportunities tried within budget, got %+v", result)
+	}
+
+	var status, analysis string
+	database.QueryRow(`SELECT ai_status, ai_analysis FROM opportunities WHERE id = 1`).Scan(&status, &analysis)
+	if status != StatusDone || !strings.Contains(analysis, `"score":55`) {
+		t.Errorf("expected stored verdict, got status %q analysis %q", status, analysis)
+	}
+
+	var failedError string
+	database.QueryRow(`SELECT ai_status, ai_error FROM opportunities WHERE id = 2`).Scan(&status, &failedError)
+	if status != StatusFailed || failedError == "" {
+		t.Errorf("expected invalid response recorded as failure, got status %q error %q", status, failedError)
+	}
+
+	// The budget is spent for today
+	result, err = w.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if result.Enriched+result.Failed != 0 || calls.Load() != 2 {
+		t.Errorf("expected no calls over budget, got %+v after %d calls", result, calls.Load())
+	}
+
+	// Work claimed by an interrupted run is released on start
+	database.Exec(`UPDATE opportunities SET ai_status = 'running', ai_attempts = 1, ai_attempted_at = '2020-01-01T00:00:00Z' WHERE id = 3`)
+	if err := w.release(); err != nil {
+		t.Fatalf("release() error = %v", err)
+	}
+	var attempts int
+	database.QueryRow(`SELECT COALESCE(ai_status, ''), ai_attempts FROM opportunities WHERE id = 3`).Scan(&status, &attempts)
+	if status != "" || attempts != 0 {
+		t.Errorf("expected interrupted opportunity back in the queue, got status %q attempts %d", status, attempts)
+	}
+}
+
+// blockingProvider waits for its call to be cancelled
+type blockingProvider struct {
+	stubProvider
+	started chan struct{}
+}
+
+func (p *blockingProvider) Chat(ctx context.Context, req ai.ChatRequest) (*ai.Response, error) {
+	close(p.started)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestWorkerStopCancelsRun(t *testing.T) {
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	defer database.Close()
+
+	started := make(chan struct{})
+	ai.Register("enrich-blocking", func(cfg ai.ProviderConfig) (ai.Provider, error) {
+		return &blockingProvider{stubProvider: stubProvider{calls: &atomic.Int32{}}, started: started}, nil
+	})
+
+	cipher, _ := ai.NewCipher(make([]byte, 32))
+	providers := ai.NewStore(database.DB, cipher, ai.NewMeter(database.DB, nil, 0))
+	if err := providers.Save(&ai.Settings{Type: "enrich-blocking"}); err != nil {
+		t.Fatalf("failed to save provider: %v", err)
+	}
+
+	if _, err := database.Exec(`
+		INSERT INTO opportunities (title, source, source_url, source_id_external, score)
+		VALUES ('Slow idea', 'hackernews', '', '1', 90)
+	`); err != nil {
+		t.Fatalf("failed to insert opportunity: %v", err)
+	}
+
+	w := NewWorker(database.DB, providers, Options{MinScore: 50, Concurrency: 1})
+
+	// A run started the way the cron starts it is aborted by Stop
+	done := make(chan *Result)
+	go func() {
+		result, _ := w.RunOnce(w.ctx)
+		done <- result
+	}()
+	<-started
+	w.Stop()
+
+	// A cancelled call is neither a failure nor charged to today's budget
+	result := <-done
+	if result == nil || result.Enriched != 0 || result.Failed != 0 || result.Remaining != 50 {
+		t.Errorf("expected the call to be released, got %+v", result)
+	}
+
+	var status string
+	var attempts int
+	var attemptedAt sql.NullString
+	database.QueryRow(`
+		SELECT COALESCE(ai_status, ''), ai_attempts, ai_attempted_at FROM opportunities WHERE id = 1
+	`).Scan(&status, &attempts, &attemptedAt)
+	if status != "" || attempts != 0 || attemptedAt.Valid {
+		t.Errorf("expected the cancelled opportunity back in the queue, got status %q attempts %d attempted at %q", status, attempts, attemptedAt.String)
+	}
+
+	used, err := w.usedToday()
+	if err != nil || used != 0 {
+		t.Errorf("expected no calls charged today, got %d, %v", used, err)
+	}
+}
@@ -0,0 +1,136 @@
+package enrich
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// levels are the accepted values for monetization potential and effort
+var levels = map[string]bool{"low": true, "medium": true, "high": true}
+
+// Verdict is the structured AI assessment of a single opportunity
+type Verdict struct {
+	Problem      string   `json:"problem"`
+	Audience     string   `json:"audience"`
+	Competitors  []string `json:"competitors"`
+	Monetization string   `json:"monetization"`
+	Effort       string   `json:"effort"`
+	Score        int      `json:"score"`
+}
+
+// Validate normalizes a verdict and checks that every field is usable
+func (v *Verdict) Validate() error {
+	v.Problem = strings.TrimSpace(v.Problem)
+	v.Audience = strings.TrimSpace(v.Audience)
+	v.Monetization = strings.ToLower(strings.TrimSpace(v.Monetization))
+	v.Effort = strings.ToLower(strings.TrimSpace(v.Effort))
+
+	if v.Problem == "" {
+		return fmt.Errorf("problem is required")
+	}
+	if v.Audience == "" {
+		return fmt.Errorf("audience is required")
+	}
+	if !levels[v.Monetization] {
+		return fmt.Errorf("monetization must be low, medium or high, got %q", v.Monetization)
+	}
+	if !levels[v.Effort] {
+		return fmt.Errorf("effort must be low, medium or high, got %q", v.Effort)
+	}
+	if v.Score < 0 || v.Score > 100 {
+		return fmt.Errorf("score must be between 0 and 100, got %d", v.Score)
+	}
+
+	competitors := []string{}
+	seen := make(map[string]bool)
+	for _, c := range v.Competitors {
+		c = strings.TrimSpace(c)
+		if c == "" || seen[strings.ToLower(c)] {
+			continue
+		}
+		seen[strings.ToLower(c)] = true
+		competitors = append(competitors, c)
+	}
+	v.Competitors = competitors
+
+	return nil
+}
+
+// ParseVerdict extracts and validates a verdict from a model response, tolerating
+// code fences and text around the JSON object
+func ParseVerdict(response string) (*Verdict, error) {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("response contains no JSON object")
+	}
+
+	var v Verdict
+	if err := json.Unmarshal([]byte(response[start:end+1]), &v); err != nil {
+		return nil, fmt.Errorf("invalid verdict JSON: %w", err)
+	}
+
+	if err := v.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid verdict: %w", err)
+	}
+
+	return &v, nil
+}
+
+// Opportunity is the opportunity data sent to the model
+type Opportunity struct {
+	ID          int64
+	Title       string
+	Description string
+	SourceType  string
+	SourceURL   string
+	Score       int
+	Signals     []string
+	Metadata    map[string]any
+}
+
+// BuildPrompt creates the prompt asking for a verdict on an opportunity
+func BuildPrompt(opp Opportunity) string {
+	var sb strings.Builder
+
+	sb.WriteString("You are evaluating a potential product opportunity found on " + opp.SourceType + ".\n\n")
+	sb.WriteString("Title: " + opp.Title + "\n")
+	if opp.Description != "" {
+		sb.WriteString("Description: " + opp.Description + "\n")
+	}
+	if opp.SourceURL != "" {
+		sb.WriteString("URL: " + opp.SourceURL + "\n")
+	}
+	sb.WriteString(fmt.Sprintf("Heuristic score: %d/100\n", opp.Score))
+	if len(opp.Signals) > 0 {
+		sb.WriteString("Signals: " + strings.Join(opp.Signals, ", ") + "\n")
+	}
+	if len(opp.Metadata) > 0 {
+		keys := make([]string, 0, len(opp.Metadata))
+		for k := range opp.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		sb.WriteString("Metadata:\n")
+		for _, k := range keys {
+			sb.WriteString(fmt.Sprintf("- %s: %v\n", k, opp.Metadata[k]))
+		}
+	}
+
+	sb.WriteString(`
+Respond with only a JSON object, no other text, using exactly these fields:
+{
+  "problem": "one sentence describing the underlying problem",
+  "audience": "who has this problem",
+  "competitors": ["existing products or projects mentioned or implied"],
+  "monetization": "low | medium | high",
+  "effort": "low | medium | high (effort to build a solution)",
+  "score": 0-100 (how promising this is as a product opportunity)
+}
+`)
+
+	return sb.String()
+}
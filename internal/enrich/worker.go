@@ -0,0 +1,325 @@
+package enrich
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mx-seer/seer/internal/ai"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// Status values stored in opportunities.ai_status
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+
+	// maxAttempts is how many times an opportunity is tried before it is marked failed
+	maxAttempts = 3
+
+	// retryAfter is how long a failed opportunity waits before it is tried again
+	retryAfter = 24 * time.Hour
+
+	// callTimeout bounds a single provider call
+	callTimeout = 2 * time.Minute
+)
+
+// errReleased marks calls that were cancelled or refused by the spend cap. Their
+// opportunities go back to the queue as if they had never been claimed.
+var errReleased = errors.New("returned to the queue")
+
+// Options configures the enrichment worker
+type Options struct {
+	// MinScore is the lowest opportunity score that gets enriched
+	MinScore int
+	// Concurrency is the number of provider calls in flight at once
+	Concurrency int
+	// DailyBudget is the maximum number of provider calls per UTC day
+	DailyBudget int
+	// Provider is the AI provider type to use; empty uses the default provider
+	Provider string
+	// Interval is the number of minutes between enrichment runs
+	Interval int
+}
+
+// Result summarizes an enrichment run
+type Result struct {
+	Enriched int `json:"enriched"`
+	Failed   int `json:"failed"`
+	// Remaining is the budget left for today after the run
+	Remaining int `json:"remaining"`
+}
+
+// Worker enriches high-scoring opportunities with an AI verdict in the background.
+// All progress is stored on the opportunity rows, so a restarted worker resumes
+// where it stopped.
+type Worker struct {
+	db        *sql.DB
+	providers *ai.Store
+	opts      Options
+	cron      *cron.Cron
+	ctx       context.Context // Cancelled by Stop to abort scheduled runs
+	cancel    context.CancelFunc
+	mu        sync.Mutex // serializes runs
+	now       func() time.Time
+}
+
+// NewWorker creates a new enrichment worker
+func NewWorker(db *sql.DB, providers *ai.Store, opts Options) *Worker {
+	if opts.MinScore <= 0 {
+		opts.MinScore = 60
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 2
+	}
+	if opts.DailyBudget <= 0 {
+		opts.DailyBudget = 50
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 15
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Worker{
+		db:        db,
+		providers: providers,
+		opts:      opts,
+		cron:      cron.New(),
+		ctx:       ctx,
+		cancel:    cancel,
+		now:       time.Now,
+	}
+}
+
+// Start releases work interrupted by a previous shutdown and schedules enrichment runs
+func (w *Worker) Start() error {
+	if err := w.release(); err != nil {
+		return err
+	}
+
+	_, err := w.cron.AddFunc(fmt.Sprintf("@every %dm", w.opts.Interval), func() {
+		if _, err := w.RunOnce(w.ctx); err != nil {
+			log.Printf("Enrichment run failed: %v", err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to schedule enrichment: %w", err)
+	}
+
+	w.cron.Start()
+	log.Printf("AI enrichment scheduled every %d minutes (min score %d, budget %d/day)", w.opts.Interval, w.opts.MinScore, w.opts.DailyBudget)
+
+	return nil
+}
+
+// Stop stops scheduling runs, aborts a running one and waits for it to put its
+// unfinished work back in the queue
+func (w *Worker) Stop() {
+	w.cancel()
+	ctx := w.cron.Stop()
+	<-ctx.Done()
+}
+
+// release puts opportunities claimed by an interrupted run back in the queue
+// without counting the interrupted attempt
+func (w *Worker) release() error {
+	_, err := w.db.Exec(`
+		UPDATE opportunities
+		SET ai_status = NULL, ai_attempts = MAX(ai_attempts - 1, 0), ai_attempted_at = NULL
+		WHERE ai_status = ?
+	`, StatusRunning)
+	if err != nil {
+		return fmt.Errorf("failed to release interrupted enrichment: %w", err)
+	}
+	return nil
+}
+
+// RunOnce enriches queued opportunities until the queue is empty or today's budget is spent
+func (w *Worker) RunOnce(ctx context.Context) (*Result, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	provider, err := w.providers.Provider(w.opts.Provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AI provider: %w", err)
+	}
+
+	// Nothing is claimed while the monthly spend cap is reached
+	if err := w.providers.Meter().Check(); err != nil {
+		return nil, err
+	}
+
+	used, err := w.usedToday()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Remaining: w.opts.DailyBudget - used}
+	if result.Remaining <= 0 {
+		return result, nil
+	}
+
+	queue, err := w.claim(result.Remaining)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, w.opts.Concurrency)
+
+	for _, opp := range queue {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(opp Opportunity) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := w.enrich(ctx, provider, opp)
+
+			// Released calls neither fail nor count against today's budget
+			if errors.Is(err, errReleased) {
+				log.Printf("Opportunity %d %v", opp.ID, err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			result.Remaining--
+			if err != nil {
+				result.Failed++
+				log.Printf("Failed to enrich opportunity %d: %v", opp.ID, err)
+			} else {
+				result.Enriched++
+			}
+		}(opp)
+	}
+	wg.Wait()
+
+	if len(queue) > 0 {
+		log.Printf("Enriched %d opportunities (%d failed, %d calls left today)", result.Enriched, result.Failed, result.Remaining)
+	}
+
+	return result, nil
+}
+
+// usedToday counts provider calls made since the start of the current UTC day
+func (w *Worker) usedToday() (int, error) {
+	now := w.now().UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var used int
+	err := w.db.QueryRow(`
+		SELECT COUNT(*) FROM opportunities
+		WHERE ai_attempted_at IS NOT NULL AND datetime(ai_attempted_at) >= datetime(?)
+	`, dayStart.Format(time.RFC3339)).Scan(&used)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count today's enrichment calls: %w", err)
+	}
+
+	return used, nil
+}
+
+// claim marks up to limit queued opportunities as running and returns them, best first
+func (w *Worker) claim(limit int) ([]Opportunity, error) {
+	now := w.now().UTC()
+
+	rows, err := w.db.Query(`
+		SELECT id, title, description, source, source_url, score, signals, metadata
+		FROM opportunities
+		WHERE score >= ?
+			AND (ai_status IS NULL OR ai_status = ?)
+			AND ai_attempts < ?
+			AND (ai_attempted_at IS NULL OR datetime(ai_attempted_at) < datetime(?))
+		ORDER BY score DESC, detected_at DESC
+		LIMIT ?
+	`, w.opts.MinScore, StatusFailed, maxAttempts, now.Add(-retryAfter).Format(time.RFC3339), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query enrichment queue: %w", err)
+	}
+
+	var queue []Opportunity
+	for rows.Next() {
+		var opp Opportunity
+		var description, sourceURL, signals, metadata sql.NullString
+		if err := rows.Scan(&opp.ID, &opp.Title, &description, &opp.SourceType, &sourceURL, &opp.Score, &signals, &metadata); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan opportunity: %w", err)
+		}
+		opp.Description = description.String
+		opp.SourceURL = sourceURL.String
+		json.Unmarshal([]byte(signals.String), &opp.Signals)
+		json.Unmarshal([]byte(metadata.String), &opp.Metadata)
+		queue = append(queue, opp)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, opp := range queue {
+		_, err := w.db.Exec(`
+			UPDATE opportunities
+			SET ai_status = ?, ai_attempts = ai_attempts + 1, ai_attempted_at = ?
+			WHERE id = ?
+		`, StatusRunning, now.Format(time.RFC3339), opp.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim opportunity: %w", err)
+		}
+	}
+
+	return queue, nil
+}
+
+// enrich asks the provider for a verdict on one opportunity and stores the outcome
+func (w *Worker) enrich(ctx context.Context, provider ai.Provider, opp Opportunity) error {
+	ctx, cancel := context.WithTimeout(ai.WithPurpose(ctx, "enrich"), callTimeout)
+	defer cancel()
+
+	response, err := provider.Chat(ctx, ai.Prompt(BuildPrompt(opp)))
+
+	var verdict *Verdict
+	if err == nil {
+		verdict, err = ParseVerdict(response.Content)
+	}
+
+	if err != nil {
+		// Cancelled runs and calls refused by the spend cap go back to the queue;
+		// they neither count as an attempt nor against the daily budget
+		if errors.Is(ctx.Err(), context.Canceled) || errors.Is(err, ai.ErrBudgetExceeded) {
+			_, dbErr := w.db.Exec(`
+				UPDATE opportunities
+				SET ai_status = NULL, ai_attempts = MAX(ai_attempts - 1, 0), ai_attempted_at = NULL
+				WHERE id = ?
+			`, opp.ID)
+			if dbErr != nil {
+				return errors.Join(err, dbErr)
+			}
+			return fmt.Errorf("%w: %w", errReleased, err)
+		}
+
+		_, dbErr := w.db.Exec(`
+			UPDATE opportunities SET ai_status = ?, ai_error = ? WHERE id = ?
+		`, StatusFailed, err.Error(), opp.ID)
+		return errors.Join(err, dbErr)
+	}
+
+	analysis, _ := json.Marshal(verdict)
+	_, err = w.db.Exec(`
+		UPDATE opportunities
+		SET ai_status = ?, ai_analysis = ?, ai_error = NULL, ai_provider = ?
+		WHERE id = ?
+	`, StatusDone, string(analysis), response.Usage.Provider, opp.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save verdict: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package report
+
+import "sort"
+
+// Diff is how the opportunities of one report changed in a later one
+type Diff struct {
+	// New are in the later report only, best first
+	New []Opportunity `json:"new"`
+	// Dropped are in the earlier report only, best first
+	Dropped []Opportunity `json:"dropped"`
+	// Movers are in both reports with a different score, largest change first
+	Movers []Mover `json:"movers"`
+	// Unchanged counts the opportunities in both reports with the same score
+	Unchanged int `json:"unchanged"`
+}
+
+// Mover is an opportunity whose score changed between two reports
+type Mover struct {
+	Opportunity   Opportunity `json:"opportunity"`
+	PreviousScore int         `json:"previous_score"`
+	Score         int         `json:"score"`
+	Change        int         `json:"change"`
+}
+
+// Compare returns how the opportunities of before changed in after. Score changes
+// smaller than minChange are counted as unchanged.
+func Compare(before, after []Opportunity, minChange int) *Diff {
+	if minChange < 1 {
+		minChange = 1
+	}
+
+	diff := &Diff{New: []Opportunity{}, Dropped: []Opportunity{}, Movers: []Mover{}}
+
+	previous := make(map[int64]Opportunity, len(before))
+	for _, opp := range before {
+		previous[opp.ID] = opp
+	}
+
+	seen := make(map[int64]bool, len(after))
+	for _, opp := range sortByScore(after) {
+		seen[opp.ID] = true
+
+		prev, ok := previous[opp.ID]
+		if !ok {
+			diff.New = append(diff.New, opp)
+			continue
+		}
+
+		change := opp.Score - prev.Score
+		if abs(change) < minChange {
+			diff.Unchanged++
+			continue
+		}
+		diff.Movers = append(diff.Movers, Mover{
+			Opportunity:   opp,
+			PreviousScore: prev.Score,
+			Score:         opp.Score,
+			Change:        change,
+		})
+	}
+
+	for _, opp := range sortByScore(before) {
+		if !seen[opp.ID] {
+			diff.Dropped = append(diff.Dropped, opp)
+		}
+	}
+
+	sort.SliceStable(diff.Movers, func(i, j int) bool {
+		return abs(diff.Movers[i].Change) > abs(diff.Movers[j].Change)
+	})
+
+	return diff
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,49 @@
+package report
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	before := []Opportunity{
+		{ID: 1, Title: "Steady", Score: 50},
+		{ID: 2, Title: "Rising", Score: 40},
+		{ID: 3, Title: "Gone", Score: 70},
+		{ID: 4, Title: "Falling", Score: 80},
+		{ID: 5, Title: "Wobble", Score: 60},
+	}
+	after := []Opportunity{
+		{ID: 1, Title: "Steady", Score: 50},
+		{ID: 2, Title: "Rising", Score: 75},
+		{ID: 4, Title: "Falling", Score: 70},
+		{ID: 5, Title: "Wobble", Score: 62},
+		{ID: 6, Title: "Fresh", Score: 30},
+		{ID: 7, Title: "Fresher", Score: 90},
+	}
+
+	diff := Compare(before, after, 5)
+
+	if len(diff.New) != 2 || diff.New[0].ID != 7 || diff.New[1].ID != 6 {
+		t.Errorf("expected new opportunities best first, got %+v", diff.New)
+	}
+	if len(diff.Dropped) != 1 || diff.Dropped[0].ID != 3 {
+		t.Errorf("expected dropped opportunity, got %+v", diff.Dropped)
+	}
+	if len(diff.Movers) != 2 {
+		t.Fatalf("expected two movers, got %+v", diff.Movers)
+	}
+	if m := diff.Movers[0]; m.Opportunity.ID != 2 || m.PreviousScore != 40 || m.Score != 75 || m.Change != 35 {
+		t.Errorf("expected largest mover first, got %+v", m)
+	}
+	if m := diff.Movers[1]; m.Opportunity.ID != 4 || m.Change != -10 {
+		t.Errorf("expected falling mover, got %+v", m)
+	}
+	if diff.Unchanged != 2 {
+		t.Errorf("expected changes below the threshold to count as unchanged, got %d", diff.Unchanged)
+	}
+}
+
+func TestCompareEmpty(t *testing.T) {
+	diff := Compare(nil, nil, 0)
+	if diff.New == nil || diff.Dropped == nil || diff.Movers == nil {
+		t.Errorf("expected empty lists, got %+v", diff)
+	}
+}
@@ -0,0 +1,265 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is a file format a report can be exported in
+type Format string
+
+const (
+	FormatMarkdown Format = "md"
+	FormatHTML     Format = "html"
+	FormatJSON     Format = "json"
+	FormatCSV      Format = "csv"
+)
+
+// ParseFormat returns the export format with the given name; empty is Markdown
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "", "md", "markdown":
+		return FormatMarkdown, nil
+	case "html":
+		return FormatHTML, nil
+	case "json":
+		return FormatJSON, nil
+	case "csv":
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q (use md, html, json or csv)", name)
+	}
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	case FormatJSON:
+		return "application/json"
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	default:
+		return "text/markdown; charset=utf-8"
+	}
+}
+
+// Export is a stored report with the opportunities it captured
+type Export struct {
+	ID           int64      `json:"id"`
+	PeriodStart  time.Time  `json:"period_start"`
+	PeriodEnd    time.Time  `json:"period_end"`
+	Template     string     `json:"template,omitempty"`
+	AIAnalysis   string     `json:"ai_analysis,omitempty"`
+	AIProvider   string     `json:"ai_provider,omitempty"`
+	AIModel      string     `json:"ai_model,omitempty"`
+	AIAnalyzedAt *time.Time `json:"ai_analyzed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	// Snapshot is false for reports saved before their opportunities were captured;
+	// their opportunities are those of the period as it is stored now
+	Snapshot bool  `json:"snapshot"`
+	Stats    Stats `json:"stats"`
+	// Opportunities are sorted by score, best first
+	Opportunities []Opportunity `json:"opportunities"`
+
+	// contentHuman is the Markdown summary stored with the report
+	contentHuman string
+}
+
+// Filename returns the name an export of the report is saved under
+func (e *Export) Filename(f Format) string {
+	return fmt.Sprintf("seer-report-%d.%s", e.ID, f)
+}
+
+// Write writes the report in the given format
+func (e *Export) Write(w io.Writer, f Format) error {
+	switch f {
+	case FormatHTML:
+		return e.writeHTML(w)
+	case FormatJSON:
+		return e.writeJSON(w)
+	case FormatCSV:
+		return e.writeCSV(w)
+	default:
+		return e.writeMarkdown(w)
+	}
+}
+
+func (e *Export) writeMarkdown(w io.Writer) error {
+	content := e.contentHuman
+	if content == "" {
+		content = New().generateHumanReadable(e.Opportunities, e.PeriodStart, e.PeriodEnd)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strings.TrimRight(content, "\n"))
+	sb.WriteString("\n")
+
+	if e.AIAnalysis != "" {
+		sb.WriteString("\n---\n\n## AI Analysis\n\n")
+		if e.AIProvider != "" {
+			sb.WriteString(fmt.Sprintf("*%s*\n\n", analyzedBy(e)))
+		}
+		sb.WriteString(strings.TrimRight(e.AIAnalysis, "\n"))
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (e *Export) writeJSON(w io.Writer) error {
+	out := *e
+	out.Opportunities = make([]Opportunity, len(e.Opportunities))
+	for i, opp := range e.Opportunities {
+		// Lists are always arrays, so consumers need no null checks
+		if opp.Signals == nil {
+			opp.Signals = []string{}
+		}
+		if opp.Watchlists == nil {
+			opp.Watchlists = []string{}
+		}
+		out.Opportunities[i] = opp
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// csvHeader is the header row of CSV exports
+var csvHeader = []string{"rank", "id", "title", "source", "score", "url", "signals", "watchlists", "detected_at", "description"}
+
+func (e *Export) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for i, opp := range e.Opportunities {
+		if err := cw.Write([]string{
+			strconv.Itoa(i + 1),
+			strconv.FormatInt(opp.ID, 10),
+			csvText(opp.Title),
+			opp.SourceType,
+			strconv.Itoa(opp.Score),
+			csvText(opp.SourceURL),
+			strings.Join(opp.Signals, "; "),
+			csvText(strings.Join(opp.Watchlists, "; ")),
+			opp.DetectedAt.UTC().Format(time.RFC3339),
+			csvText(opp.Description),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvText keeps scraped text from running as a formula when the CSV is opened in a
+// spreadsheet, by quoting cells that start like one
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func (e *Export) writeHTML(w io.Writer) error {
+	return htmlTemplate.Execute(w, struct {
+		*Export
+		AnalyzedBy string
+	}{e, analyzedBy(e)})
+}
+
+// analyzedBy describes the provider and model that analyzed a report
+func analyzedBy(e *Export) string {
+	by := "Analyzed by " + e.AIProvider
+	if e.AIModel != "" {
+		by += " (" + e.AIModel + ")"
+	}
+	if e.AIAnalyzedAt != nil {
+		by += " on " + e.AIAnalyzedAt.Format("Jan 2, 2006")
+	}
+	return by
+}
+
+// htmlTemplate renders a self-contained HTML report laid out for printing to PDF
+var htmlTemplate = template.Must(template.New("export").Funcs(template.FuncMap{
+	"date":     func(layout string, t time.Time) string { return t.Format(layout) },
+	"truncate": truncate,
+	"join":     func(sep string, items []string) string { return strings.Join(items, sep) },
+	"inc":      func(i int) int { return i + 1 },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Seer Opportunity Report {{date "2006-01-02" .PeriodStart}} to {{date "2006-01-02" .PeriodEnd}}</title>
+<style>
+	@page { size: A4; margin: 18mm 16mm; }
+	* { box-sizing: border-box; }
+	body { font: 11pt/1.5 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; max-width: 960px; margin: 2rem auto; padding: 0 1.5rem; }
+	h1 { font-size: 20pt; margin: 0 0 .25rem; }
+	h2 { font-size: 14pt; border-bottom: 1px solid #d0d7de; padding-bottom: .25rem; margin-top: 2rem; }
+	.meta { color: #59636e; margin: 0 0 1.5rem; }
+	.stats { display: flex; gap: 1rem; flex-wrap: wrap; margin: 1rem 0; }
+	.stat { border: 1px solid #d0d7de; border-radius: 6px; padding: .5rem 1rem; }
+	.stat strong { display: block; font-size: 16pt; }
+	table { width: 100%; border-collapse: collapse; font-size: 10pt; }
+	th, td { text-align: left; vertical-align: top; padding: .4rem .5rem; border-bottom: 1px solid #d0d7de; }
+	th { background: #f6f8fa; }
+	td.num { text-align: right; white-space: nowrap; }
+	tr { break-inside: avoid; }
+	a { color: #0969da; text-decoration: none; }
+	.desc { color: #59636e; margin-top: .2rem; }
+	.signals { font-size: 9pt; color: #59636e; }
+	.analysis { white-space: pre-wrap; border-left: 3px solid #d0d7de; padding-left: 1rem; }
+	@media print {
+		body { margin: 0; max-width: none; padding: 0; }
+		a { color: inherit; }
+		td a::after { content: " (" attr(href) ")"; font-size: 8pt; color: #59636e; word-break: break-all; }
+		h2 { break-after: avoid; }
+	}
+</style>
+</head>
+<body>
+<h1>Seer Opportunity Report</h1>
+<p class="meta">{{date "Jan 2, 2006" .PeriodStart}} to {{date "Jan 2, 2006" .PeriodEnd}}{{if .Template}} · template {{.Template}}{{end}} · generated {{date "Jan 2, 2006 15:04" .CreatedAt}}</p>
+
+<div class="stats">
+	<div class="stat"><strong>{{.Stats.Total}}</strong>opportunities</div>
+	<div class="stat"><strong>{{.Stats.AverageScore}}</strong>average score</div>
+	{{range .Stats.BySource}}<div class="stat"><strong>{{.Count}}</strong>{{.Source}} (avg {{.AverageScore}})</div>
+	{{end}}
+</div>
+{{if .AIAnalysis}}
+<h2>AI Analysis</h2>
+{{if .AIProvider}}<p class="meta">{{.AnalyzedBy}}</p>{{end}}
+<div class="analysis">{{.AIAnalysis}}</div>
+{{end}}
+<h2>Opportunities</h2>
+{{if .Opportunities}}<table>
+<thead><tr><th>#</th><th>Opportunity</th><th>Source</th><th>Score</th></tr></thead>
+<tbody>
+{{range $i, $o := .Opportunities}}<tr>
+	<td class="num">{{inc $i}}</td>
+	<td>{{if $o.SourceURL}}<a href="{{$o.SourceURL}}">{{$o.Title}}</a>{{else}}{{$o.Title}}{{end}}
+		{{if $o.Description}}<div class="desc">{{truncate 300 $o.Description}}</div>{{end}}
+		{{if $o.Signals}}<div class="signals">{{join ", " $o.Signals}}</div>{{end}}</td>
+	<td>{{$o.SourceType}}</td>
+	<td class="num">{{$o.Score}}</td>
+</tr>
+{{end}}</tbody>
+</table>{{else}}<p>No opportunities found in this period.</p>{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,157 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleExport() *Export {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	opportunities := []Opportunity{
+		{ID: 7, Title: "Need a <better> CSV tool", Description: "Spreadsheets, \"quoted\", commas", SourceType: "hackernews", SourceURL: "https://news.ycombinator.com/item?id=7", Score: 80, Signals: []string{"pain", "wish"}, Watchlists: []string{"tools"}, DetectedAt: start.Add(time.Hour)},
+		{ID: 9, Title: "Invoice app", SourceType: "github", SourceURL: "https://github.com/acme/invoice", Score: 40, DetectedAt: start.Add(2 * time.Hour)},
+	}
+
+	return &Export{
+		ID:            3,
+		PeriodStart:   start,
+		PeriodEnd:     start.Add(7 * 24 * time.Hour),
+		AIAnalysis:    "Focus on <b>CSV</b> tooling.",
+		AIProvider:    "ollama",
+		AIModel:       "llama3",
+		CreatedAt:     start.Add(7 * 24 * time.Hour),
+		Stats:         NewTemplateData(opportunities, nil, start, start.Add(7*24*time.Hour)).Stats,
+		Opportunities: opportunities,
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"": FormatMarkdown, "markdown": FormatMarkdown, "HTML": FormatHTML, "json": FormatJSON, "csv": FormatCSV} {
+		if got, err := ParseFormat(name); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestExportMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleExport().Write(&buf, FormatMarkdown); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "# Seer Opportunity Report") {
+		t.Errorf("expected generated summary without a stored one, got %q", out)
+	}
+	if !strings.Contains(out, "## AI Analysis\n\n*Analyzed by ollama (llama3)*\n\nFocus on <b>CSV</b> tooling.\n") {
+		t.Errorf("expected analysis section, got %q", out)
+	}
+}
+
+func TestExportHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleExport().Write(&buf, FormatHTML); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"@media print",
+		"Need a &lt;better&gt; CSV tool",
+		"Focus on &lt;b&gt;CSV&lt;/b&gt; tooling.",
+		`<a href="https://github.com/acme/invoice">`,
+		"pain, wish",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected HTML to contain %q", want)
+		}
+	}
+	if strings.Contains(out, "<link") || strings.Contains(out, "<script") {
+		t.Error("expected self-contained HTML")
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleExport().Write(&buf, FormatJSON); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var out struct {
+		Stats struct {
+			Total int `json:"total"`
+		} `json:"stats"`
+		Opportunities []struct {
+			Title   string    `json:"title"`
+			Signals *[]string `json:"signals"`
+		} `json:"opportunities"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if out.Stats.Total != 2 || len(out.Opportunities) != 2 {
+		t.Fatalf("expected two opportunities, got %+v", out)
+	}
+	if got := *out.Opportunities[0].Signals; len(got) != 2 || got[0] != "pain" {
+		t.Errorf("expected signals, got %v", got)
+	}
+	if out.Opportunities[1].Signals == nil {
+		t.Error("expected empty signals as an array")
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleExport().Write(&buf, FormatCSV); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("expected header and one row per opportunity, got %d rows", len(records))
+	}
+	want := []string{"1", "7", "Need a <better> CSV tool", "hackernews", "80", "https://news.ycombinator.com/item?id=7", "pain; wish", "tools", "2026-03-02T01:00:00Z", "Spreadsheets, \"quoted\", commas"}
+	for i := range want {
+		if records[1][i] != want[i] {
+			t.Errorf("column %s: expected %q, got %q", records[0][i], want[i], records[1][i])
+		}
+	}
+}
+
+func TestExportCSVEscapesFormulas(t *testing.T) {
+	export := sampleExport()
+	export.Opportunities[0].Title = `=HYPERLINK("https://evil.example","click")`
+	export.Opportunities[0].Description = "@SUM(A1:A9)"
+	export.Opportunities[1].Title = "-2+3"
+	export.Opportunities[1].Description = "\tindented"
+
+	var buf bytes.Buffer
+	if err := export.Write(&buf, FormatCSV); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+
+	for _, cell := range []string{records[1][2], records[1][9], records[2][2], records[2][9]} {
+		if !strings.HasPrefix(cell, "'") {
+			t.Errorf("expected formula-like cell to be quoted, got %q", cell)
+		}
+	}
+	if records[1][5] != "https://news.ycombinator.com/item?id=7" {
+		t.Errorf("expected ordinary cells to be unchanged, got %q", records[1][5])
+	}
+}
@@ -9,14 +9,16 @@ import (
 
 // Opportunity represents an opportunity for the report
 type Opportunity struct {
-	ID          int64
-	Title       string
-	Description string
-	SourceType  string
-	SourceURL   string
-	Score       int
-	Signals     []string
-	DetectedAt  time.Time
+	ID          int64     `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	SourceType  string    `json:"source"`
+	SourceURL   string    `json:"source_url"`
+	Score       int       `json:"score"`
+	Signals     []string  `json:"signals"`
+	DetectedAt  time.Time `json:"detected_at"`
+	// Watchlists are the names of the watchlists that tagged the opportunity
+	Watchlists []string `json:"watchlists"`
 }
 
 // Report contains the generated report
@@ -26,7 +28,9 @@ type Report struct {
 	OpportunityCount int
 	ContentHuman     string
 	ContentPrompt    string
-	Opportunities    []Opportunity
+	// Template is the name of the prompt template ContentPrompt was rendered from
+	Template      string
+	Opportunities []Opportunity
 }
 
 // Generator creates reports from opportunities
@@ -37,26 +41,45 @@ func New() *Generator {
 	return &Generator{}
 }
 
-// Generate creates a report from opportunities
+// Generate creates a report from opportunities using the default prompt template
 func (g *Generator) Generate(opportunities []Opportunity, periodStart, periodEnd time.Time) *Report {
-	// Sort by score descending
-	sorted := make([]Opportunity, len(opportunities))
-	copy(sorted, opportunities)
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].Score > sorted[j].Score
-	})
+	// The built-in template renders for any data
+	report, _ := g.GenerateWith(Default(), opportunities, nil, periodStart, periodEnd)
+	return report
+}
 
-	report := &Report{
+// GenerateWith creates a report from opportunities and the watchlists that tagged them,
+// rendering the prompt from tmpl
+func (g *Generator) GenerateWith(tmpl *Template, opportunities []Opportunity, watchlists []Watchlist, periodStart, periodEnd time.Time) (*Report, error) {
+	sorted := sortByScore(opportunities)
+	for i := range watchlists {
+		watchlists[i].Opportunities = sortByScore(watchlists[i].Opportunities)
+	}
+
+	prompt, err := tmpl.Execute(NewTemplateData(sorted, watchlists, periodStart, periodEnd))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Report{
 		PeriodStart:      periodStart,
 		PeriodEnd:        periodEnd,
 		OpportunityCount: len(opportunities),
+		ContentHuman:     g.generateHumanReadable(sorted, periodStart, periodEnd),
+		ContentPrompt:    prompt,
+		Template:         tmpl.Name(),
 		Opportunities:    sorted,
-	}
-
-	report.ContentHuman = g.generateHumanReadable(sorted, periodStart, periodEnd)
-	report.ContentPrompt = g.generatePrompt(sorted, periodStart, periodEnd)
+	}, nil
+}
 
-	return report
+// sortByScore returns a copy of opportunities sorted by score descending
+func sortByScore(opportunities []Opportunity) []Opportunity {
+	sorted := make([]Opportunity, len(opportunities))
+	copy(sorted, opportunities)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Score > sorted[j].Score
+	})
+	return sorted
 }
 
 // generateHumanReadable creates a human-readable report
@@ -123,53 +146,6 @@ func (g *Generator) generateHumanReadable(opportunities []Opportunity, start, en
 	return sb.String()
 }
 
-// generatePrompt creates an AI-optimized prompt
-func (g *Generator) generatePrompt(opportunities []Opportunity, start, end time.Time) string {
-	var sb strings.Builder
-
-	sb.WriteString("You are an expert market analyst specializing in opportunities for indie developers and bootstrapped startups.\n\n")
-	sb.WriteString("Analyze the following market opportunities detected from various sources and provide:\n")
-	sb.WriteString("1. A summary of the most promising opportunities\n")
-	sb.WriteString("2. Common themes and patterns you notice\n")
-	sb.WriteString("3. Specific actionable ideas for indie developers\n")
-	sb.WriteString("4. Any emerging trends worth watching\n\n")
-
-	sb.WriteString(fmt.Sprintf("Report Period: %s to %s\n", start.Format("2006-01-02"), end.Format("2006-01-02")))
-	sb.WriteString(fmt.Sprintf("Total Opportunities: %d\n\n", len(opportunities)))
-
-	sb.WriteString("=== OPPORTUNITIES ===\n\n")
-
-	limit := 30 // More items for AI analysis
-	if len(opportunities) < limit {
-		limit = len(opportunities)
-	}
-
-	for i, opp := range opportunities[:limit] {
-		sb.WriteString(fmt.Sprintf("[%d] %s\n", i+1, opp.Title))
-		sb.WriteString(fmt.Sprintf("Source: %s | Score: %d/100\n", opp.SourceType, opp.Score))
-
-		if opp.Description != "" {
-			desc := opp.Description
-			if len(desc) > 500 {
-				desc = desc[:500] + "..."
-			}
-			sb.WriteString(fmt.Sprintf("Description: %s\n", desc))
-		}
-
-		if len(opp.Signals) > 0 {
-			sb.WriteString(fmt.Sprintf("Signals: %s\n", strings.Join(opp.Signals, ", ")))
-		}
-
-		sb.WriteString(fmt.Sprintf("URL: %s\n", opp.SourceURL))
-		sb.WriteString("\n")
-	}
-
-	sb.WriteString("=== END OPPORTUNITIES ===\n\n")
-	sb.WriteString("Please provide your analysis in a structured, actionable format.")
-
-	return sb.String()
-}
-
 // GetTopOpportunities returns the top N opportunities by score
 func (r *Report) GetTopOpportunities(n int) []Opportunity {
 	if n > len(r.Opportunities) {
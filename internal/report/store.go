@@ -0,0 +1,400 @@
+package report
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrTemplateNotFound is returned when a prompt template does not exist
+var ErrTemplateNotFound = errors.New("prompt template not found")
+
+// PromptTemplate is a named prompt template stored in the database
+type PromptTemplate struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Body        string    `json:"body"`
+	Builtin     bool      `json:"builtin"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Normalize validates the template name and checks that the body renders
+func (t *PromptTemplate) Normalize() error {
+	t.Name = strings.TrimSpace(t.Name)
+	t.Description = strings.TrimSpace(t.Description)
+
+	if t.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.EqualFold(t.Name, DefaultTemplateName) {
+		return fmt.Errorf("%q is the name of the built-in template", DefaultTemplateName)
+	}
+	if strings.TrimSpace(t.Body) == "" {
+		return fmt.Errorf("body is required")
+	}
+
+	if _, err := ParseTemplate(t.Name, t.Body); err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+
+	return nil
+}
+
+// Store loads report data and persists prompt templates
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates a new report store
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Templates returns the built-in template followed by the stored ones by name
+func (s *Store) Templates() ([]PromptTemplate, error) {
+	rows, err := s.db.Query(`
+		SELECT id, name, description, body, created_at, updated_at
+		FROM prompt_templates
+		ORDER BY name ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prompt templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []PromptTemplate{{Name: DefaultTemplateName, Description: "Built-in market analysis prompt", Body: DefaultTemplate, Builtin: true}}
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *t)
+	}
+
+	return templates, rows.Err()
+}
+
+// GetTemplate returns a stored template by ID, or nil if it does not exist
+func (s *Store) GetTemplate(id int64) (*PromptTemplate, error) {
+	t, err := scanTemplate(s.db.QueryRow(`
+		SELECT id, name, description, body, created_at, updated_at
+		FROM prompt_templates
+		WHERE id = ?
+	`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return t, err
+}
+
+// Template parses the template with the given name; an empty name or "default"
+// is the built-in template
+func (s *Store) Template(name string) (*Template, error) {
+	if name == "" || strings.EqualFold(name, DefaultTemplateName) {
+		return Default(), nil
+	}
+
+	var body string
+	err := s.db.QueryRow(`SELECT body FROM prompt_templates WHERE name = ?`, name).Scan(&body)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load prompt template: %w", err)
+	}
+
+	return ParseTemplate(name, body)
+}
+
+// CreateTemplate stores a new template
+func (s *Store) CreateTemplate(t *PromptTemplate) error {
+	t.CreatedAt = time.Now().UTC()
+	t.UpdatedAt = t.CreatedAt
+
+	result, err := s.db.Exec(`
+		INSERT INTO prompt_templates (name, description, body, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, t.Name, t.Description, t.Body, t.CreatedAt, t.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create prompt template: %w", err)
+	}
+
+	t.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateTemplate replaces the name, description and body of a stored template
+func (s *Store) UpdateTemplate(t *PromptTemplate) error {
+	t.UpdatedAt = time.Now().UTC()
+
+	result, err := s.db.Exec(`
+		UPDATE prompt_templates
+		SET name = ?, description = ?, body = ?, updated_at = ?
+		WHERE id = ?
+	`, t.Name, t.Description, t.Body, t.UpdatedAt, t.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update prompt template: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrTemplateNotFound
+	}
+
+	return nil
+}
+
+// DeleteTemplate deletes a stored template
+func (s *Store) DeleteTemplate(id int64) error {
+	result, err := s.db.Exec(`DELETE FROM prompt_templates WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete prompt template: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrTemplateNotFound
+	}
+
+	return nil
+}
+
+// Load returns the opportunities detected in a period, with the watchlists that tagged them
+func (s *Store) Load(start, end time.Time) ([]Opportunity, []Watchlist, error) {
+	rows, err := s.db.Query(`
+		SELECT o.id, o.title, o.description, o.source, o.source_url, o.score, o.signals, o.detected_at,
+			(SELECT json_group_array(w.name)
+				FROM opportunity_watchlists ow JOIN watchlists w ON w.id = ow.watchlist_id
+				WHERE ow.opportunity_id = o.id)
+		FROM opportunities o
+		WHERE datetime(o.detected_at) BETWEEN datetime(?) AND datetime(?)
+		ORDER BY o.score DESC
+	`, start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query opportunities: %w", err)
+	}
+	defer rows.Close()
+
+	var opportunities []Opportunity
+	byWatchlist := make(map[string][]Opportunity)
+	var names []string
+
+	for rows.Next() {
+		var opp Opportunity
+		var description, sourceURL, signals, watchlists sql.NullString
+
+		if err := rows.Scan(
+			&opp.ID, &opp.Title, &description, &opp.SourceType,
+			&sourceURL, &opp.Score, &signals, &opp.DetectedAt, &watchlists,
+		); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan opportunity: %w", err)
+		}
+
+		opp.Description = description.String
+		opp.SourceURL = sourceURL.String
+		json.Unmarshal([]byte(signals.String), &opp.Signals)
+		json.Unmarshal([]byte(watchlists.String), &opp.Watchlists)
+
+		for _, name := range opp.Watchlists {
+			if _, ok := byWatchlist[name]; !ok {
+				names = append(names, name)
+			}
+			byWatchlist[name] = append(byWatchlist[name], opp)
+		}
+		opportunities = append(opportunities, opp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	sort.Strings(names)
+	watchlists := make([]Watchlist, 0, len(names))
+	for _, name := range names {
+		watchlists = append(watchlists, Watchlist{Name: name, Opportunities: byWatchlist[name]})
+	}
+
+	return opportunities, watchlists, nil
+}
+
+// Export loads a stored report with the opportunities it captured, or returns nil if
+// the report does not exist
+func (s *Store) Export(id int64) (*Export, error) {
+	var e Export
+	var contentHuman, template, analysis, provider, model sql.NullString
+	var analyzedAt sql.NullTime
+	var snapshot sql.NullBool
+
+	err := s.db.QueryRow(`
+		SELECT id, period_start, period_end, content_human, template,
+			ai_analysis, ai_provider, ai_model, ai_analyzed_at, snapshot, created_at
+		FROM reports
+		WHERE id = ?
+	`, id).Scan(
+		&e.ID, &e.PeriodStart, &e.PeriodEnd, &contentHuman, &template,
+		&analysis, &provider, &model, &analyzedAt, &snapshot, &e.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report: %w", err)
+	}
+
+	e.contentHuman = contentHuman.String
+	e.Template = template.String
+	e.AIAnalysis = analysis.String
+	e.AIProvider = provider.String
+	e.AIModel = model.String
+	if analyzedAt.Valid {
+		e.AIAnalyzedAt = &analyzedAt.Time
+	}
+
+	// Reports saved before snapshots were kept fall back to today's view of their period
+	e.Snapshot = snapshot.Bool
+	var opportunities []Opportunity
+	if e.Snapshot {
+		opportunities, err = s.snapshot(id)
+	} else {
+		opportunities, _, err = s.Load(e.PeriodStart, e.PeriodEnd)
+	}
+	if err != nil {
+		return nil, err
+	}
+	e.Opportunities = sortByScore(opportunities)
+	e.Stats = NewTemplateData(e.Opportunities, nil, e.PeriodStart, e.PeriodEnd).Stats
+
+	return &e, nil
+}
+
+// Save stores a generated report with a snapshot of its opportunities and returns its ID
+func (s *Store) Save(rep *Report) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT INTO reports (period_start, period_end, opportunity_count, content_human, content_prompt, template, snapshot)
+		VALUES (?, ?, ?, ?, ?, ?, true)
+	`, rep.PeriodStart, rep.PeriodEnd, rep.OpportunityCount, rep.ContentHuman, rep.ContentPrompt, rep.Template)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save report: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO report_opportunities
+			(report_id, opportunity_id, position, title, description, source, source_url, score, signals, watchlists, detected_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare report snapshot: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, opp := range rep.Opportunities {
+		signals, _ := json.Marshal(nonNil(opp.Signals))
+		watchlists, _ := json.Marshal(nonNil(opp.Watchlists))
+		if _, err := stmt.Exec(
+			id, opp.ID, i+1, opp.Title, opp.Description, opp.SourceType, opp.SourceURL,
+			opp.Score, string(signals), string(watchlists), opp.DetectedAt,
+		); err != nil {
+			return 0, fmt.Errorf("failed to save report snapshot: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit report: %w", err)
+	}
+
+	return id, nil
+}
+
+// snapshot returns the opportunities captured when a report was generated, best first
+func (s *Store) snapshot(reportID int64) ([]Opportunity, error) {
+	rows, err := s.db.Query(`
+		SELECT opportunity_id, title, description, source, source_url, score, signals, watchlists, detected_at
+		FROM report_opportunities
+		WHERE report_id = ?
+		ORDER BY position ASC
+	`, reportID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report snapshot: %w", err)
+	}
+	defer rows.Close()
+
+	opportunities := []Opportunity{}
+	for rows.Next() {
+		var opp Opportunity
+		var description, sourceURL, signals, watchlists sql.NullString
+		var detectedAt sql.NullTime
+
+		if err := rows.Scan(
+			&opp.ID, &opp.Title, &description, &opp.SourceType, &sourceURL,
+			&opp.Score, &signals, &watchlists, &detectedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan report snapshot: %w", err)
+		}
+
+		opp.Description = description.String
+		opp.SourceURL = sourceURL.String
+		opp.DetectedAt = detectedAt.Time
+		json.Unmarshal([]byte(signals.String), &opp.Signals)
+		json.Unmarshal([]byte(watchlists.String), &opp.Watchlists)
+		opportunities = append(opportunities, opp)
+	}
+
+	return opportunities, rows.Err()
+}
+
+// SaveAnalysis stores the AI analysis of a report, replacing any earlier analysis
+// and the follow-up conversation about it
+func (s *Store) SaveAnalysis(id int64, analysis, provider, model string, analyzedAt time.Time) error {
+	_, err := s.db.Exec(`
+		UPDATE reports
+		SET ai_analysis = ?, ai_provider = ?, ai_model = ?, ai_analyzed_at = ?
+		WHERE id = ?
+	`, analysis, provider, model, analyzedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to save analysis: %w", err)
+	}
+
+	if _, err := s.db.Exec(`DELETE FROM report_messages WHERE report_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to clear report messages: %w", err)
+	}
+
+	return nil
+}
+
+// nonNil returns items, or an empty list if it is nil
+func nonNil(items []string) []string {
+	if items == nil {
+		return []string{}
+	}
+	return items
+}
+
+func scanTemplate(row interface{ Scan(...any) error }) (*PromptTemplate, error) {
+	var t PromptTemplate
+	var description sql.NullString
+	if err := row.Scan(&t.ID, &t.Name, &description, &t.Body, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	t.Description = description.String
+	return &t, nil
+}
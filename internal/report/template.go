@@ -0,0 +1,200 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultTemplateName is the name of the built-in prompt template
+const DefaultTemplateName = "default"
+
+// DefaultTemplate is the built-in prompt template used when no other is chosen
+const DefaultTemplate = `You are an expert market analyst specializing in opportunities for indie developers and bootstrapped startups.
+
+Analyze the following market opportunities detected from various sources and provide:
+1. A summary of the most promising opportunities
+2. Common themes and patterns you notice
+3. Specific actionable ideas for indie developers
+4. Any emerging trends worth watching
+
+Report Period: {{date "2006-01-02" .Period.Start}} to {{date "2006-01-02" .Period.End}}
+Total Opportunities: {{.Stats.Total}}
+
+=== OPPORTUNITIES ===
+
+{{range $i, $o := top 30 .Opportunities}}[{{inc $i}}] {{$o.Title}}
+Source: {{$o.SourceType}} | Score: {{$o.Score}}/100
+{{if $o.Description}}Description: {{truncate 500 $o.Description}}
+{{end}}{{if $o.Signals}}Signals: {{join ", " $o.Signals}}
+{{end}}URL: {{$o.SourceURL}}
+
+{{end}}=== END OPPORTUNITIES ===
+
+Please provide your analysis in a structured, actionable format.`
+
+// TemplateData is the data a prompt template is rendered with
+type TemplateData struct {
+	Period Period
+	// Opportunities are sorted by score, best first
+	Opportunities []Opportunity
+	Stats         Stats
+	// Watchlists hold the opportunities of the period tagged by each watchlist
+	Watchlists []Watchlist
+}
+
+// Period is the time span a report covers
+type Period struct {
+	Start time.Time
+	End   time.Time
+	Days  int
+}
+
+// Stats summarizes the opportunities of a report
+type Stats struct {
+	Total        int `json:"total"`
+	AverageScore int `json:"average_score"`
+	// BySource is sorted by count, largest first
+	BySource []SourceStats `json:"by_source"`
+}
+
+// SourceStats summarizes the opportunities of one source type
+type SourceStats struct {
+	Source       string `json:"source"`
+	Count        int    `json:"count"`
+	AverageScore int    `json:"average_score"`
+}
+
+// Watchlist is a watchlist with the opportunities it tagged
+type Watchlist struct {
+	Name          string
+	Opportunities []Opportunity
+}
+
+// NewTemplateData builds the template data for opportunities sorted by score
+func NewTemplateData(opportunities []Opportunity, watchlists []Watchlist, start, end time.Time) TemplateData {
+	data := TemplateData{
+		Period: Period{
+			Start: start,
+			End:   end,
+			Days:  int(end.Sub(start).Round(24*time.Hour) / (24 * time.Hour)),
+		},
+		Opportunities: opportunities,
+		Watchlists:    watchlists,
+		Stats:         Stats{Total: len(opportunities), BySource: []SourceStats{}},
+	}
+	if data.Watchlists == nil {
+		data.Watchlists = []Watchlist{}
+	}
+
+	totals := make(map[string]int)
+	counts := make(map[string]int)
+	sum := 0
+	for _, opp := range opportunities {
+		sum += opp.Score
+		totals[opp.SourceType] += opp.Score
+		counts[opp.SourceType]++
+	}
+	if len(opportunities) > 0 {
+		data.Stats.AverageScore = sum / len(opportunities)
+	}
+
+	for source, count := range counts {
+		data.Stats.BySource = append(data.Stats.BySource, SourceStats{
+			Source:       source,
+			Count:        count,
+			AverageScore: totals[source] / count,
+		})
+	}
+	sort.Slice(data.Stats.BySource, func(i, j int) bool {
+		a, b := data.Stats.BySource[i], data.Stats.BySource[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Source < b.Source
+	})
+
+	return data
+}
+
+// templateFuncs are the functions available to prompt templates
+var templateFuncs = template.FuncMap{
+	// date formats a time with a Go layout
+	"date": func(layout string, t time.Time) string { return t.Format(layout) },
+	// truncate shortens text to n bytes, adding an ellipsis when it was cut
+	"truncate": truncate,
+	// join joins a list of strings
+	"join": func(sep string, items []string) string { return strings.Join(items, sep) },
+	// top returns the first n opportunities
+	"top": func(n int, opps []Opportunity) []Opportunity {
+		if n < len(opps) {
+			return opps[:n]
+		}
+		return opps
+	},
+	// inc adds one, for numbering from 1
+	"inc":   func(i int) int { return i + 1 },
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// truncate shortens s to at most n bytes without splitting a character
+func truncate(n int, s string) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "..."
+}
+
+// Template is a parsed prompt template
+type Template struct {
+	name string
+	tmpl *template.Template
+}
+
+// ParseTemplate parses a prompt template and checks that it renders
+func ParseTemplate(name, body string) (*Template, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Template{name: name, tmpl: tmpl}
+
+	// Field and function mistakes only show up when the template is executed
+	sample := []Opportunity{{Title: "Sample", Description: "Sample", SourceType: "sample", Score: 50, Signals: []string{"sample"}, Watchlists: []string{"sample"}}}
+	now := time.Now()
+	if _, err := t.Execute(NewTemplateData(sample, []Watchlist{{Name: "sample", Opportunities: sample}}, now.Add(-24*time.Hour), now)); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Name returns the name of the template
+func (t *Template) Name() string {
+	return t.name
+}
+
+// Execute renders the template
+func (t *Template) Execute(data TemplateData) (string, error) {
+	var sb strings.Builder
+	if err := t.tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// defaultTemplate is the parsed built-in template
+var defaultTemplate = template.Must(template.New(DefaultTemplateName).Funcs(templateFuncs).Parse(DefaultTemplate))
+
+// Default returns the built-in prompt template
+func Default() *Template {
+	return &Template{name: DefaultTemplateName, tmpl: defaultTemplate}
+}
@@ -0,0 +1,66 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTemplate(t *testing.T) {
+	if _, err := ParseTemplate("ok", "{{range top 5 .Opportunities}}{{truncate 10 .Title}}{{end}}"); err != nil {
+		t.Errorf("ParseTemplate() error = %v", err)
+	}
+
+	invalid := []string{
+		"{{.Unknown}}",
+		"{{range .Opportunities}}",
+		"{{nosuchfunc .Stats}}",
+	}
+	for _, body := range invalid {
+		if _, err := ParseTemplate("bad", body); err == nil {
+			t.Errorf("expected error for %q", body)
+		}
+	}
+}
+
+func TestGenerateWith(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(7 * 24 * time.Hour)
+
+	opportunities := []Opportunity{
+		{Title: "Low", SourceType: "npm", Score: 20},
+		{Title: "High", SourceType: "hackernews", Score: 90},
+		{Title: "Mid", SourceType: "hackernews", Score: 50},
+	}
+	watchlists := []Watchlist{{Name: "tools", Opportunities: []Opportunity{opportunities[0], opportunities[1]}}}
+
+	tmpl, err := ParseTemplate("scan", `{{.Period.Days}}d avg {{.Stats.AverageScore}}{{range .Stats.BySource}} {{.Source}}={{.Count}}/{{.AverageScore}}{{end}}{{range .Watchlists}} {{.Name}}:{{range .Opportunities}}{{.Title}},{{end}}{{end}}`)
+	if err != nil {
+		t.Fatalf("ParseTemplate() error = %v", err)
+	}
+
+	rep, err := New().GenerateWith(tmpl, opportunities, watchlists, start, end)
+	if err != nil {
+		t.Fatalf("GenerateWith() error = %v", err)
+	}
+
+	want := "7d avg 53 hackernews=2/70 npm=1/20 tools:High,Low,"
+	if rep.ContentPrompt != want {
+		t.Errorf("expected %q, got %q", want, rep.ContentPrompt)
+	}
+	if rep.Template != "scan" {
+		t.Errorf("expected template name scan, got %q", rep.Template)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate(2, "héllo"); got != "h..." {
+		t.Errorf("expected truncation before a split character, got %q", got)
+	}
+	if got := truncate(10, "short"); got != "short" {
+		t.Errorf("expected short text unchanged, got %q", got)
+	}
+	if !strings.HasSuffix(truncate(4, strings.Repeat("x", 10)), "...") {
+		t.Error("expected ellipsis")
+	}
+}
@@ -0,0 +1,79 @@
+package schedule
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mx-seer/seer/internal/db"
+	"github.com/robfig/cron/v3"
+)
+
+func TestNormalize(t *testing.T) {
+	sc := Schedule{Name: "  Weekly ", Cron: "0 8 * * 1", AIProvider: "default"}
+	if err := sc.Normalize(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sc.Name != "Weekly" || sc.PeriodHours != DefaultPeriodHours || sc.Alerts == nil {
+		t.Errorf("expected trimmed name and defaults, got %+v", sc)
+	}
+
+	for _, bad := range []Schedule{
+		{Cron: "@daily"},
+		{Name: "No cron"},
+		{Name: "Bad cron", Cron: "every monday"},
+		{Name: "Seconds", Cron: "0 0 8 * * 1"},
+		{Name: "Negative", Cron: "@daily", PeriodHours: -1},
+		{Name: "Too long", Cron: "@daily", PeriodHours: maxPeriodHours + 1},
+		{Name: "Unknown", Cron: "@daily", AIProvider: "skynet"},
+	} {
+		if err := bad.Normalize(); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	if got := excerpt("short", 10); got != "short" {
+		t.Errorf("expected text unchanged, got %q", got)
+	}
+
+	if got := excerpt("one two three four", 12); got != "one two..." {
+		t.Errorf("expected cut at a word boundary, got %q", got)
+	}
+
+	got := excerpt(strings.Repeat("é", 10), 7)
+	if got != "ééé..." {
+		t.Errorf("expected cut between characters, got %q", got)
+	}
+}
+
+func TestSchedulerSharesCron(t *testing.T) {
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	defer database.Close()
+
+	jobs := cron.New()
+	other, _ := jobs.AddFunc("@every 1h", func() {})
+
+	scheduler := NewScheduler(database.DB, nil, nil, jobs, "http://seer.test")
+	if err := scheduler.Store().Create(&Schedule{Name: "Weekly", Cron: "0 8 * * 1", Enabled: true}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer jobs.Stop()
+
+	if n := len(jobs.Entries()); n != 2 {
+		t.Fatalf("expected the schedule to join the shared cron, got %d entries", n)
+	}
+
+	// Stopping the scheduler leaves the other jobs scheduled
+	scheduler.Stop()
+	if entries := jobs.Entries(); len(entries) != 1 || entries[0].ID != other {
+		t.Errorf("expected only the other job to remain, got %+v", entries)
+	}
+}
@@ -0,0 +1,352 @@
+package schedule
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mx-seer/seer/internal/ai"
+	"github.com/mx-seer/seer/internal/alerts"
+	"github.com/mx-seer/seer/internal/report"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// analyzeTimeout bounds the AI analysis of a scheduled report, retries included
+	analyzeTimeout = 5 * time.Minute
+
+	// summaryLength is the longest excerpt of the analysis included in a delivered digest
+	summaryLength = 1500
+
+	// topOpportunities is the number of opportunities listed in a delivered digest
+	topOpportunities = 5
+)
+
+// Run is the outcome of running a schedule
+type Run struct {
+	ReportID         int64 `json:"report_id"`
+	OpportunityCount int   `json:"opportunity_count"`
+	Analyzed         bool  `json:"analyzed"`
+	// Delivered is the number of alerts the report was sent to
+	Delivered int `json:"delivered"`
+	// Errors are the analysis and delivery failures, which do not stop the run
+	Errors []string `json:"errors"`
+}
+
+// Scheduler runs report schedules with cron and delivers the reports through alerts
+type Scheduler struct {
+	store     *Store
+	reports   *report.Store
+	providers *ai.Store
+	alerts    *alerts.AlertService
+	baseURL   string
+	cron      *cron.Cron
+	mu        sync.Mutex // guards entries and stopped
+	entries   map[int64]cron.EntryID
+	stopped   bool
+	running   sync.WaitGroup
+	now       func() time.Time
+}
+
+// NewScheduler creates a scheduler that adds its jobs to jobs, the cron shared by
+// Seer's background work. Delivered reports link to baseURL, the address of the
+// web interface.
+func NewScheduler(db *sql.DB, providers *ai.Store, alertService *alerts.AlertService, jobs *cron.Cron, baseURL string) *Scheduler {
+	return &Scheduler{
+		store:     NewStore(db),
+		reports:   report.NewStore(db),
+		providers: providers,
+		alerts:    alertService,
+		baseURL:   baseURL,
+		cron:      jobs,
+		entries:   make(map[int64]cron.EntryID),
+		now:       time.Now,
+	}
+}
+
+// Store returns the store the scheduler reads schedules from
+func (s *Scheduler) Store() *Store {
+	return s.store
+}
+
+// Start schedules the enabled report schedules
+func (s *Scheduler) Start() error {
+	if err := s.Reload(); err != nil {
+		return err
+	}
+
+	// Starting a cron that is already running does nothing
+	s.cron.Start()
+	log.Printf("Report scheduler started with %d schedules", len(s.entries))
+
+	return nil
+}
+
+// Stop removes the scheduler's jobs and waits for running ones to finish. The
+// shared cron keeps running the other jobs.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	s.stopped = true
+	for id, entry := range s.entries {
+		s.cron.Remove(entry)
+		delete(s.entries, id)
+	}
+	s.mu.Unlock()
+
+	s.running.Wait()
+}
+
+// Reload replaces the scheduled jobs with the enabled schedules in the store; it is
+// called whenever a schedule changes
+func (s *Scheduler) Reload() error {
+	schedules, err := s.store.List()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, entry := range s.entries {
+		s.cron.Remove(entry)
+		delete(s.entries, id)
+	}
+
+	for _, sc := range schedules {
+		if !sc.Enabled {
+			continue
+		}
+
+		id := sc.ID
+		entry, err := s.cron.AddFunc(sc.Cron, func() {
+			if !s.begin() {
+				return
+			}
+			defer s.running.Done()
+
+			if _, err := s.RunID(context.Background(), id); err != nil {
+				log.Printf("Report schedule %d failed: %v", id, err)
+			}
+		})
+		if err != nil {
+			// Schedules are validated when saved, so this only skips rows edited by hand
+			log.Printf("Skipping report schedule %q: %v", sc.Name, err)
+			continue
+		}
+		s.entries[id] = entry
+	}
+
+	return nil
+}
+
+// begin counts a scheduled run, unless the scheduler has been stopped
+func (s *Scheduler) begin() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return false
+	}
+	s.running.Add(1)
+	return true
+}
+
+// Next returns when a schedule runs next, or nil if it is not scheduled
+func (s *Scheduler) Next(id int64) *time.Time {
+	s.mu.Lock()
+	entry, ok := s.entries[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	// Entries only have a next time once the cron is running
+	e := s.cron.Entry(entry)
+	next := e.Next
+	if next.IsZero() {
+		next = e.Schedule.Next(s.now())
+	}
+	return &next
+}
+
+// RunID runs the schedule with the given ID
+func (s *Scheduler) RunID(ctx context.Context, id int64) (*Run, error) {
+	sc, err := s.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if sc == nil {
+		return nil, ErrNotFound
+	}
+	return s.Run(ctx, sc)
+}
+
+// Run generates the report of a schedule, analyzes it if the schedule names an AI
+// provider and delivers it to the schedule's alerts. Only a report that cannot be
+// generated fails the run; analysis and delivery failures are listed in the result.
+func (s *Scheduler) Run(ctx context.Context, sc *Schedule) (*Run, error) {
+	now := s.now()
+	run, err := s.run(ctx, sc, now)
+
+	lastError := ""
+	var reportID int64
+	if err != nil {
+		lastError = err.Error()
+	} else {
+		reportID = run.ReportID
+		if len(run.Errors) > 0 {
+			lastError = strings.Join(run.Errors, "; ")
+		}
+	}
+	if recordErr := s.store.recordRun(sc.ID, now.UTC(), reportID, lastError); recordErr != nil {
+		log.Printf("Report schedule %q: %v", sc.Name, recordErr)
+	}
+
+	return run, err
+}
+
+func (s *Scheduler) run(ctx context.Context, sc *Schedule, now time.Time) (*Run, error) {
+	tmpl, err := s.reports.Template(sc.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	end := now
+	start := end.Add(-time.Duration(sc.PeriodHours) * time.Hour)
+	opportunities, watchlists, err := s.reports.Load(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	rep, err := report.New().GenerateWith(tmpl, opportunities, watchlists, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	reportID, err := s.reports.Save(rep)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &Run{ReportID: reportID, OpportunityCount: rep.OpportunityCount, Errors: []string{}}
+
+	var analysis string
+	if sc.AIProvider != "" {
+		analysis, err = s.analyze(ctx, sc, reportID, rep.ContentPrompt)
+		if err != nil {
+			run.Errors = append(run.Errors, fmt.Sprintf("analysis: %v", err))
+		} else {
+			run.Analyzed = true
+		}
+	}
+
+	payload := alerts.ReportPayload{
+		Title:            fmt.Sprintf("Seer report: %s", sc.Name),
+		ReportID:         reportID,
+		PeriodStart:      rep.PeriodStart,
+		PeriodEnd:        rep.PeriodEnd,
+		OpportunityCount: rep.OpportunityCount,
+		Summary:          excerpt(analysis, summaryLength),
+		Top:              []alerts.AlertPayload{},
+		URL:              fmt.Sprintf("%s/api/prompts/%d/export?format=html", s.baseURL, reportID),
+	}
+	for i, opp := range rep.Opportunities {
+		if i == topOpportunities {
+			break
+		}
+		payload.Top = append(payload.Top, alerts.AlertPayload{
+			Title:       opp.Title,
+			Description: excerpt(opp.Description, 200),
+			Score:       opp.Score,
+			Source:      opp.SourceType,
+			URL:         opp.SourceURL,
+			DetectedAt:  opp.DetectedAt,
+		})
+	}
+
+	targets, err := s.targets(sc)
+	if err != nil {
+		run.Errors = append(run.Errors, fmt.Sprintf("delivery: %v", err))
+		return run, nil
+	}
+	for _, alert := range targets {
+		if err := s.alerts.SendReport(ctx, alert, payload); err != nil {
+			run.Errors = append(run.Errors, fmt.Sprintf("alert %q: %v", alert.Name, err))
+			continue
+		}
+		run.Delivered++
+	}
+
+	return run, nil
+}
+
+// analyze asks the schedule's AI provider for an analysis and stores it on the report
+func (s *Scheduler) analyze(ctx context.Context, sc *Schedule, reportID int64, prompt string) (string, error) {
+	providerType := sc.AIProvider
+	if providerType == "default" {
+		providerType = ""
+	}
+
+	provider, err := s.providers.Provider(providerType)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ai.WithPurpose(ctx, "schedule"), analyzeTimeout)
+	defer cancel()
+
+	resp, err := provider.Chat(ctx, ai.Prompt(prompt))
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.reports.SaveAnalysis(reportID, resp.Content, resp.Usage.Provider, resp.Usage.Model, s.now().UTC()); err != nil {
+		return "", err
+	}
+
+	return resp.Content, nil
+}
+
+// targets returns the enabled alerts a schedule delivers to
+func (s *Scheduler) targets(sc *Schedule) ([]alerts.Alert, error) {
+	all, err := s.alerts.GetAlerts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load alerts: %w", err)
+	}
+
+	wanted := make(map[int64]bool, len(sc.Alerts))
+	for _, id := range sc.Alerts {
+		wanted[id] = true
+	}
+
+	var targets []alerts.Alert
+	for _, alert := range all {
+		if alert.Enabled && (len(wanted) == 0 || wanted[alert.ID]) {
+			targets = append(targets, alert)
+		}
+	}
+	return targets, nil
+}
+
+// excerpt shortens text to about n bytes at a word boundary
+func excerpt(text string, n int) string {
+	if len(text) <= n {
+		return text
+	}
+
+	cut := n
+	for cut > n/2 && text[cut] != ' ' && text[cut] != '\n' {
+		cut--
+	}
+	if cut <= n/2 {
+		cut = n
+		for cut > 0 && text[cut]&0xC0 == 0x80 {
+			cut--
+		}
+	}
+
+	return text[:cut] + "..."
+}
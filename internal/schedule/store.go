@@ -0,0 +1,244 @@
+package schedule
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mx-seer/seer/internal/ai"
+	"github.com/robfig/cron/v3"
+)
+
+// ErrNotFound is returned when a report schedule does not exist
+var ErrNotFound = errors.New("report schedule not found")
+
+const (
+	// DefaultPeriodHours is the report period of a schedule that does not set one
+	DefaultPeriodHours = 7 * 24
+
+	// maxPeriodHours is the longest report period a schedule may cover
+	maxPeriodHours = 366 * 24
+)
+
+// Schedule generates a report on a cron schedule and delivers it through alerts
+type Schedule struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// Cron is a standard five-field cron expression or descriptor such as @weekly,
+	// evaluated in the server's local time zone
+	Cron string `json:"cron"`
+	// PeriodHours is how far back from the run time the report reaches
+	PeriodHours int `json:"period_hours"`
+	// Template is the prompt template name; empty uses the built-in template
+	Template string `json:"template,omitempty"`
+	// AIProvider analyzes the report when set; "default" uses the default provider
+	AIProvider string `json:"ai_provider,omitempty"`
+	// Alerts are the IDs of the alerts the report is delivered to; empty delivers
+	// to every enabled alert
+	Alerts       []int64    `json:"alerts"`
+	Enabled      bool       `json:"enabled"`
+	LastRunAt    *time.Time `json:"last_run_at,omitempty"`
+	LastReportID *int64     `json:"last_report_id,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	NextRunAt    *time.Time `json:"next_run_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Normalize validates the schedule and fills in defaults
+func (s *Schedule) Normalize() error {
+	s.Name = strings.TrimSpace(s.Name)
+	s.Cron = strings.TrimSpace(s.Cron)
+	s.Template = strings.TrimSpace(s.Template)
+	s.AIProvider = strings.TrimSpace(s.AIProvider)
+
+	if s.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if s.Cron == "" {
+		return fmt.Errorf("cron is required")
+	}
+	if _, err := cron.ParseStandard(s.Cron); err != nil {
+		return fmt.Errorf("invalid cron expression: %w", err)
+	}
+
+	if s.PeriodHours == 0 {
+		s.PeriodHours = DefaultPeriodHours
+	}
+	if s.PeriodHours < 0 || s.PeriodHours > maxPeriodHours {
+		return fmt.Errorf("period_hours must be between 1 and %d", maxPeriodHours)
+	}
+
+	if s.AIProvider != "" && s.AIProvider != "default" {
+		known := false
+		for _, name := range ai.AvailableProviders() {
+			if name == s.AIProvider {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown AI provider: %s", s.AIProvider)
+		}
+	}
+
+	if s.Alerts == nil {
+		s.Alerts = []int64{}
+	}
+
+	return nil
+}
+
+// Store persists report schedules
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates a new report schedule store
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+const selectSchedule = `
+	SELECT id, name, cron, period_hours, template, ai_provider, alert_ids, enabled,
+		last_run_at, last_report_id, last_error, created_at, updated_at
+	FROM report_schedules
+`
+
+// List returns all schedules by name
+func (s *Store) List() ([]Schedule, error) {
+	rows, err := s.db.Query(selectSchedule + ` ORDER BY name ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := []Schedule{}
+	for rows.Next() {
+		sc, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, *sc)
+	}
+
+	return schedules, rows.Err()
+}
+
+// Get returns a schedule by ID, or nil if it does not exist
+func (s *Store) Get(id int64) (*Schedule, error) {
+	sc, err := scanSchedule(s.db.QueryRow(selectSchedule+` WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return sc, err
+}
+
+// Create stores a new schedule
+func (s *Store) Create(sc *Schedule) error {
+	sc.CreatedAt = time.Now().UTC()
+	sc.UpdatedAt = sc.CreatedAt
+
+	alertIDs, _ := json.Marshal(sc.Alerts)
+	result, err := s.db.Exec(`
+		INSERT INTO report_schedules (name, cron, period_hours, template, ai_provider, alert_ids, enabled, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, sc.Name, sc.Cron, sc.PeriodHours, sc.Template, sc.AIProvider, string(alertIDs), sc.Enabled, sc.CreatedAt, sc.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create report schedule: %w", err)
+	}
+
+	sc.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces the settings of a schedule, keeping its run history
+func (s *Store) Update(sc *Schedule) error {
+	sc.UpdatedAt = time.Now().UTC()
+
+	alertIDs, _ := json.Marshal(sc.Alerts)
+	result, err := s.db.Exec(`
+		UPDATE report_schedules
+		SET name = ?, cron = ?, period_hours = ?, template = ?, ai_provider = ?, alert_ids = ?, enabled = ?, updated_at = ?
+		WHERE id = ?
+	`, sc.Name, sc.Cron, sc.PeriodHours, sc.Template, sc.AIProvider, string(alertIDs), sc.Enabled, sc.UpdatedAt, sc.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update report schedule: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes a schedule
+func (s *Store) Delete(id int64) error {
+	result, err := s.db.Exec(`DELETE FROM report_schedules WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete report schedule: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// recordRun stores the outcome of a run; reportID is zero when no report was generated
+func (s *Store) recordRun(id int64, at time.Time, reportID int64, runErr string) error {
+	var lastReport any
+	if reportID != 0 {
+		lastReport = reportID
+	}
+
+	_, err := s.db.Exec(`
+		UPDATE report_schedules
+		SET last_run_at = ?, last_report_id = COALESCE(?, last_report_id), last_error = ?
+		WHERE id = ?
+	`, at, lastReport, runErr, id)
+	if err != nil {
+		return fmt.Errorf("failed to record report schedule run: %w", err)
+	}
+	return nil
+}
+
+func scanSchedule(row interface{ Scan(...any) error }) (*Schedule, error) {
+	var sc Schedule
+	var template, provider, alertIDs, lastError sql.NullString
+	var lastRunAt sql.NullTime
+	var lastReportID sql.NullInt64
+
+	if err := row.Scan(
+		&sc.ID, &sc.Name, &sc.Cron, &sc.PeriodHours, &template, &provider, &alertIDs, &sc.Enabled,
+		&lastRunAt, &lastReportID, &lastError, &sc.CreatedAt, &sc.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	sc.Template = template.String
+	sc.AIProvider = provider.String
+	sc.LastError = lastError.String
+	if lastRunAt.Valid {
+		sc.LastRunAt = &lastRunAt.Time
+	}
+	if lastReportID.Valid {
+		sc.LastReportID = &lastReportID.Int64
+	}
+
+	sc.Alerts = []int64{}
+	json.Unmarshal([]byte(alertIDs.String), &sc.Alerts)
+
+	return &sc, nil
+}
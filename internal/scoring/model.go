@@ -0,0 +1,95 @@
+package scoring
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+)
+
+// Metadata-derived feature names
+const (
+	FeatureEngagement = "meta.engagement"
+	FeatureDiscussion = "meta.discussion"
+)
+
+// Model is a logistic regression over signal matches and normalized metadata features
+type Model struct {
+	Bias       float64            `json:"bias"`
+	Weights    map[string]float64 `json:"weights"`
+	TrainedAt  time.Time          `json:"trained_at"`
+	Examples   int                `json:"examples"`
+	Evaluation *Evaluation        `json:"evaluation,omitempty"`
+}
+
+// Probability returns the predicted likelihood that an opportunity will be saved
+func (m *Model) Probability(features map[string]float64) float64 {
+	z := m.Bias
+	for name, value := range features {
+		z += m.Weights[name] * value
+	}
+	return sigmoid(z)
+}
+
+// Score returns the model probability on the 0-100 scale used by the scorer
+func (m *Model) Score(features map[string]float64) int {
+	return int(math.Round(m.Probability(features) * 100))
+}
+
+// Features builds the model input for a set of matched signals and source metadata
+func Features(signals []string, metadata map[string]any) map[string]float64 {
+	features := make(map[string]float64, len(signals)+2)
+	for _, name := range signals {
+		features["signal."+name] = 1
+	}
+
+	// Engagement metrics differ per source, so take the strongest one available
+	features[FeatureEngagement] = logNormalize(maxMetric(metadata, "points", "stars", "reactions", "like_count"), 1000)
+	features[FeatureDiscussion] = logNormalize(maxMetric(metadata, "num_comments", "comments", "reply_count"), 200)
+
+	return features
+}
+
+// maxMetric returns the largest numeric value among the given metadata keys
+func maxMetric(metadata map[string]any, keys ...string) float64 {
+	best := 0.0
+	for _, key := range keys {
+		if v, ok := metaNumber(metadata, key); ok && v > best {
+			best = v
+		}
+	}
+	return best
+}
+
+// logNormalize maps a count onto 0-1 using a log scale saturating at ceiling
+func logNormalize(v, ceiling float64) float64 {
+	if v <= 0 {
+		return 0
+	}
+	n := math.Log1p(v) / math.Log1p(ceiling)
+	if n > 1 {
+		return 1
+	}
+	return n
+}
+
+// metaNumber reads a numeric metadata value regardless of whether it came
+// straight from a source (int) or was round-tripped through JSON (float64)
+func metaNumber(metadata map[string]any, key string) (float64, bool) {
+	switch v := metadata[key].(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func sigmoid(z float64) float64 {
+	return 1 / (1 + math.Exp(-z))
+}
@@ -2,6 +2,7 @@ package scoring
 
 import (
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -31,6 +32,8 @@ type Result struct {
 // Scorer calculates opportunity scores based on signals
 type Scorer struct {
 	signals []signalCheck
+	mu      sync.RWMutex
+	model   *Model
 }
 
 type signalCheck struct {
@@ -103,16 +106,16 @@ func (s *Scorer) registerDefaultSignals() {
 		Weight:      10,
 	}, func(o Opportunity) bool {
 		// Check various engagement metrics from metadata
-		if points, ok := o.Metadata["points"].(int); ok && points > 50 {
+		if points, ok := metaNumber(o.Metadata, "points"); ok && points > 50 {
 			return true
 		}
-		if comments, ok := o.Metadata["num_comments"].(int); ok && comments > 20 {
+		if comments, ok := metaNumber(o.Metadata, "num_comments"); ok && comments > 20 {
 			return true
 		}
-		if stars, ok := o.Metadata["stars"].(int); ok && stars > 100 {
+		if stars, ok := metaNumber(o.Metadata, "stars"); ok && stars > 100 {
 			return true
 		}
-		if reactions, ok := o.Metadata["reactions"].(int); ok && reactions > 20 {
+		if reactions, ok := metaNumber(o.Metadata, "reactions"); ok && reactions > 20 {
 			return true
 		}
 		return false
@@ -142,37 +145,74 @@ func (s *Scorer) addSignal(signal Signal, check func(o Opportunity) bool) {
 	s.signals = append(s.signals, signalCheck{signal: signal, check: check})
 }
 
+// SetModel makes a learned model the active scoring function.
+// Passing nil restores the default signal weights.
+func (s *Scorer) SetModel(m *Model) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.model = m
+}
+
+// Model returns the active learned model, or nil when default weights are used
+func (s *Scorer) Model() *Model {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.model
+}
+
 // Score calculates the score for an opportunity
 func (s *Scorer) Score(o Opportunity) Result {
-	var totalScore float64
 	var matchedSignals []Signal
+	var matchedNames []string
 
 	for _, sc := range s.signals {
 		signal := sc.signal
 		signal.Matched = sc.check(o)
 
 		if signal.Matched {
-			totalScore += signal.Weight
+			matchedNames = append(matchedNames, signal.Name)
 		}
 
 		matchedSignals = append(matchedSignals, signal)
 	}
 
-	// Normalize to 0-100 scale
+	// A promoted model replaces the hand-tuned weights entirely
+	if model := s.Model(); model != nil {
+		return Result{
+			Score:   model.Score(Features(matchedNames, o.Metadata)),
+			Signals: matchedSignals,
+		}
+	}
+
+	return Result{
+		Score:   s.ScoreSignals(matchedNames),
+		Signals: matchedSignals,
+	}
+}
+
+// ScoreSignals calculates the default weighted score for a set of matched signal names
+func (s *Scorer) ScoreSignals(names []string) int {
+	matched := make(map[string]bool, len(names))
+	for _, name := range names {
+		matched[name] = true
+	}
+
+	var totalScore float64
 	maxPossible := 0.0
 	for _, sc := range s.signals {
 		maxPossible += sc.signal.Weight
+		if matched[sc.signal.Name] {
+			totalScore += sc.signal.Weight
+		}
 	}
 
+	// Normalize to 0-100 scale
 	normalizedScore := int((totalScore / maxPossible) * 100)
 	if normalizedScore > 100 {
 		normalizedScore = 100
 	}
 
-	return Result{
-		Score:   normalizedScore,
-		Signals: matchedSignals,
-	}
+	return normalizedScore
 }
 
 // GetMatchedSignals returns only the matched signals from a result
@@ -148,6 +148,7 @@ func TestScore_HighEngagement(t *testing.T) {
 		{"high_comments", map[string]any{"num_comments": 50}},
 		{"high_stars", map[string]any{"stars": 200}},
 		{"high_reactions", map[string]any{"reactions": 30}},
+		{"high_points_from_json", map[string]any{"points": float64(100)}},
 	}
 
 	for _, tc := range testCases {
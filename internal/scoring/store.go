@@ -0,0 +1,128 @@
+package scoring
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	activeModelKey    = "scoring_model"
+	candidateModelKey = "scoring_model_candidate"
+)
+
+// ErrNoCandidate is returned when promoting without a trained candidate model
+var ErrNoCandidate = errors.New("no candidate model has been trained")
+
+// ModelStore persists learned models and loads training data from triage feedback
+type ModelStore struct {
+	db *sql.DB
+}
+
+// NewModelStore creates a new model store
+func NewModelStore(db *sql.DB) *ModelStore {
+	return &ModelStore{db: db}
+}
+
+// Active returns the promoted model, or nil when default weights are in use
+func (s *ModelStore) Active() (*Model, error) {
+	return s.load(activeModelKey)
+}
+
+// Candidate returns the most recently trained model awaiting promotion
+func (s *ModelStore) Candidate() (*Model, error) {
+	return s.load(candidateModelKey)
+}
+
+// SaveCandidate stores a trained model without activating it
+func (s *ModelStore) SaveCandidate(m *Model) error {
+	return s.save(candidateModelKey, m)
+}
+
+// Promote makes the candidate model the active scorer
+func (s *ModelStore) Promote() (*Model, error) {
+	candidate, err := s.Candidate()
+	if err != nil {
+		return nil, err
+	}
+	if candidate == nil {
+		return nil, ErrNoCandidate
+	}
+
+	if err := s.save(activeModelKey, candidate); err != nil {
+		return nil, err
+	}
+
+	return candidate, nil
+}
+
+// Reset deactivates the learned model and restores the default weights
+func (s *ModelStore) Reset() error {
+	_, err := s.db.Exec(`DELETE FROM settings WHERE key = ?`, activeModelKey)
+	return err
+}
+
+// Examples returns every opportunity that has a feedback label
+func (s *ModelStore) Examples() ([]Example, error) {
+	rows, err := s.db.Query(`
+		SELECT o.id, o.signals, o.metadata, f.label
+		FROM opportunity_feedback f
+		JOIN opportunities o ON o.id = f.opportunity_id
+		ORDER BY o.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query feedback: %w", err)
+	}
+	defer rows.Close()
+
+	var examples []Example
+	for rows.Next() {
+		var ex Example
+		var signalsJSON, metadataJSON sql.NullString
+		var label string
+		if err := rows.Scan(&ex.ID, &signalsJSON, &metadataJSON, &label); err != nil {
+			return nil, fmt.Errorf("failed to scan feedback: %w", err)
+		}
+
+		json.Unmarshal([]byte(signalsJSON.String), &ex.Signals)
+		json.Unmarshal([]byte(metadataJSON.String), &ex.Metadata)
+		ex.Saved = label == LabelSaved
+
+		examples = append(examples, ex)
+	}
+
+	return examples, rows.Err()
+}
+
+func (s *ModelStore) load(key string) (*Model, error) {
+	var value string
+	err := s.db.QueryRow(`SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var m Model
+	if err := json.Unmarshal([]byte(value), &m); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+func (s *ModelStore) save(key string, m *Model) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO settings (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value
+	`, key, string(data))
+
+	return err
+}
@@ -0,0 +1,199 @@
+package scoring
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// Feedback labels recorded when triaging opportunities
+const (
+	LabelSaved     = "saved"
+	LabelDismissed = "dismissed"
+)
+
+// ErrNotEnoughFeedback is returned when the labels cannot support training and evaluation
+var ErrNotEnoughFeedback = errors.New("not enough feedback: need saved and dismissed labels in both the training and held-out sets")
+
+// Example is a labelled opportunity used for training
+type Example struct {
+	ID       int64
+	Signals  []string
+	Metadata map[string]any
+	Saved    bool
+}
+
+// TrainOptions controls the training routine
+type TrainOptions struct {
+	Epochs       int     // Full passes of gradient descent
+	LearningRate float64 // Gradient descent step size
+	L2           float64 // Weight decay to keep sparse signals from dominating
+	HoldoutEvery int     // Every Nth example (by ID) is held out for evaluation
+	Threshold    int     // Score at or above which an opportunity counts as "would save"
+}
+
+// DefaultTrainOptions returns sensible defaults for small feedback sets
+func DefaultTrainOptions() TrainOptions {
+	return TrainOptions{
+		Epochs:       500,
+		LearningRate: 0.5,
+		L2:           0.01,
+		HoldoutEvery: 5,
+		Threshold:    50,
+	}
+}
+
+// Metrics summarizes how well a scorer separates saved from dismissed opportunities
+type Metrics struct {
+	Precision     float64 `json:"precision"`
+	Recall        float64 `json:"recall"`
+	Predicted     int     `json:"predicted"`
+	TruePositives int     `json:"true_positives"`
+	Positives     int     `json:"positives"`
+}
+
+// Evaluation compares the default weights with a learned model on held-out labels
+type Evaluation struct {
+	TrainSize   int     `json:"train_size"`
+	HoldoutSize int     `json:"holdout_size"`
+	Threshold   int     `json:"threshold"`
+	Baseline    Metrics `json:"baseline"`
+	Learned     Metrics `json:"learned"`
+}
+
+type vector struct {
+	features map[string]float64
+	label    float64
+}
+
+// Train fits a logistic regression model to the examples and evaluates it
+// against the default weights on a deterministic held-out split
+func Train(examples []Example, opts TrainOptions) (*Model, error) {
+	defaults := DefaultTrainOptions()
+	if opts.Epochs <= 0 {
+		opts.Epochs = defaults.Epochs
+	}
+	if opts.LearningRate <= 0 {
+		opts.LearningRate = defaults.LearningRate
+	}
+	if opts.HoldoutEvery <= 1 {
+		opts.HoldoutEvery = defaults.HoldoutEvery
+	}
+	if opts.Threshold <= 0 {
+		opts.Threshold = defaults.Threshold
+	}
+
+	// Sort so the split does not depend on query order
+	sorted := make([]Example, len(examples))
+	copy(sorted, examples)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	var train, holdout []Example
+	for i, ex := range sorted {
+		if (i+1)%opts.HoldoutEvery == 0 {
+			holdout = append(holdout, ex)
+		} else {
+			train = append(train, ex)
+		}
+	}
+
+	if !hasBothLabels(train) || !hasBothLabels(holdout) {
+		return nil, ErrNotEnoughFeedback
+	}
+
+	vectors := make([]vector, len(train))
+	names := make(map[string]bool)
+	for i, ex := range train {
+		vectors[i] = vector{features: Features(ex.Signals, ex.Metadata)}
+		if ex.Saved {
+			vectors[i].label = 1
+		}
+		for name := range vectors[i].features {
+			names[name] = true
+		}
+	}
+
+	model := &Model{
+		Weights:   make(map[string]float64, len(names)),
+		TrainedAt: time.Now().UTC(),
+		Examples:  len(train),
+	}
+	for name := range names {
+		model.Weights[name] = 0
+	}
+
+	// Batch gradient descent on the log loss
+	n := float64(len(vectors))
+	for epoch := 0; epoch < opts.Epochs; epoch++ {
+		gradBias := 0.0
+		grads := make(map[string]float64, len(names))
+
+		for _, v := range vectors {
+			diff := model.Probability(v.features) - v.label
+			gradBias += diff
+			for name, value := range v.features {
+				grads[name] += diff * value
+			}
+		}
+
+		model.Bias -= opts.LearningRate * gradBias / n
+		for name, w := range model.Weights {
+			model.Weights[name] = w - opts.LearningRate*(grads[name]/n+opts.L2*w)
+		}
+	}
+
+	baseline := New()
+	model.Evaluation = &Evaluation{
+		TrainSize:   len(train),
+		HoldoutSize: len(holdout),
+		Threshold:   opts.Threshold,
+		Baseline: evaluate(holdout, opts.Threshold, func(ex Example) int {
+			return baseline.ScoreSignals(ex.Signals)
+		}),
+		Learned: evaluate(holdout, opts.Threshold, func(ex Example) int {
+			return model.Score(Features(ex.Signals, ex.Metadata))
+		}),
+	}
+
+	return model, nil
+}
+
+// evaluate computes precision and recall of a scoring function at a threshold
+func evaluate(examples []Example, threshold int, score func(Example) int) Metrics {
+	var m Metrics
+	for _, ex := range examples {
+		predicted := score(ex) >= threshold
+		if ex.Saved {
+			m.Positives++
+		}
+		if predicted {
+			m.Predicted++
+			if ex.Saved {
+				m.TruePositives++
+			}
+		}
+	}
+
+	if m.Predicted > 0 {
+		m.Precision = float64(m.TruePositives) / float64(m.Predicted)
+	}
+	if m.Positives > 0 {
+		m.Recall = float64(m.TruePositives) / float64(m.Positives)
+	}
+
+	return m
+}
+
+func hasBothLabels(examples []Example) bool {
+	var saved, dismissed bool
+	for _, ex := range examples {
+		if ex.Saved {
+			saved = true
+		} else {
+			dismissed = true
+		}
+	}
+	return saved && dismissed
+}
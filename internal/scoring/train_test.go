@@ -0,0 +1,98 @@
+package scoring
+
+import (
+	"errors"
+	"testing"
+)
+
+// syntheticExamples builds feedback where indie projects are always saved
+// and generic business posts are always dismissed
+func syntheticExamples(n int) []Example {
+	var examples []Example
+	for i := 1; i <= n; i++ {
+		ex := Example{ID: int64(i), Metadata: map[string]any{}}
+		if i%2 == 0 {
+			ex.Signals = []string{"indie_focus", "show_project"}
+			ex.Metadata["points"] = float64(10)
+			ex.Saved = true
+		} else {
+			ex.Signals = []string{"business_opportunity", "technical", "solution_seeking", "problem_mention", "recent"}
+			ex.Metadata["points"] = float64(500)
+		}
+		examples = append(examples, ex)
+	}
+	return examples
+}
+
+func TestTrain_LearnsFromFeedback(t *testing.T) {
+	model, err := Train(syntheticExamples(60), DefaultTrainOptions())
+	if err != nil {
+		t.Fatalf("failed to train: %v", err)
+	}
+
+	if model.Weights["signal.indie_focus"] <= 0 {
+		t.Errorf("expected positive weight for indie_focus, got %f", model.Weights["signal.indie_focus"])
+	}
+	if model.Weights["signal.business_opportunity"] >= 0 {
+		t.Errorf("expected negative weight for business_opportunity, got %f", model.Weights["signal.business_opportunity"])
+	}
+
+	eval := model.Evaluation
+	if eval == nil {
+		t.Fatal("expected evaluation to be attached to the model")
+	}
+	if eval.HoldoutSize != 12 || eval.TrainSize != 48 {
+		t.Errorf("expected 48/12 split, got %d/%d", eval.TrainSize, eval.HoldoutSize)
+	}
+	if eval.Learned.Precision != 1 {
+		t.Errorf("expected perfect learned precision, got %f", eval.Learned.Precision)
+	}
+	if eval.Learned.Precision <= eval.Baseline.Precision {
+		t.Errorf("expected learned precision %f to beat baseline %f", eval.Learned.Precision, eval.Baseline.Precision)
+	}
+}
+
+func TestTrain_NotEnoughFeedback(t *testing.T) {
+	examples := []Example{
+		{ID: 1, Signals: []string{"technical"}, Saved: true},
+		{ID: 2, Signals: []string{"technical"}, Saved: true},
+	}
+
+	if _, err := Train(examples, DefaultTrainOptions()); !errors.Is(err, ErrNotEnoughFeedback) {
+		t.Errorf("expected ErrNotEnoughFeedback, got %v", err)
+	}
+}
+
+func TestScorer_SetModel(t *testing.T) {
+	scorer := New()
+	opp := Opportunity{Title: "My indie side project", Metadata: map[string]any{}}
+
+	defaultScore := scorer.Score(opp).Score
+
+	scorer.SetModel(&Model{Bias: 5})
+	if got := scorer.Score(opp).Score; got != 99 {
+		t.Errorf("expected model score 99, got %d", got)
+	}
+
+	scorer.SetModel(nil)
+	if got := scorer.Score(opp).Score; got != defaultScore {
+		t.Errorf("expected default score %d after reset, got %d", defaultScore, got)
+	}
+}
+
+func TestFeatures(t *testing.T) {
+	features := Features([]string{"recent"}, map[string]any{
+		"stars":    float64(5000),
+		"comments": 0,
+	})
+
+	if features["signal.recent"] != 1 {
+		t.Error("expected matched signal feature to be set")
+	}
+	if features[FeatureEngagement] != 1 {
+		t.Errorf("expected engagement to saturate at 1, got %f", features[FeatureEngagement])
+	}
+	if features[FeatureDiscussion] != 0 {
+		t.Errorf("expected zero discussion, got %f", features[FeatureDiscussion])
+	}
+}
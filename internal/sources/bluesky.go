@@ -0,0 +1,189 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	blueskyDefaultAppView = "https://public.api.bsky.app"
+	blueskyPostURL        = "https://bsky.app/profile/%s/post/%s"
+)
+
+// Bluesky fetches posts from Bluesky through an AppView's public search
+type Bluesky struct {
+	name     string
+	appView  string
+	keywords []string
+	lang     string
+	client   *http.Client
+}
+
+// blueskySearchResponse represents the app.bsky.feed.searchPosts response
+type blueskySearchResponse struct {
+	Posts []blueskyPost `json:"posts"`
+}
+
+type blueskyPost struct {
+	URI    string `json:"uri"`
+	Author struct {
+		DID         string `json:"did"`
+		Handle      string `json:"handle"`
+		DisplayName string `json:"displayName"`
+	} `json:"author"`
+	Record struct {
+		Text      string `json:"text"`
+		CreatedAt string `json:"createdAt"`
+	} `json:"record"`
+	ReplyCount  int    `json:"replyCount"`
+	RepostCount int    `json:"repostCount"`
+	LikeCount   int    `json:"likeCount"`
+	QuoteCount  int    `json:"quoteCount"`
+	IndexedAt   string `json:"indexedAt"`
+}
+
+// NewBluesky creates a new Bluesky source. The source URL is the AppView to search
+// (default the public Bluesky AppView); "keywords" defaults to the Twitter source's
+// list and "lang" to en (empty searches all languages).
+func NewBluesky(cfg SourceConfig) (Source, error) {
+	appView := blueskyDefaultAppView
+	if cfg.URL != "" {
+		appView = strings.TrimRight(cfg.URL, "/")
+	}
+
+	keywords := socialKeywords
+	if kw, ok := cfg.Config["keywords"]; ok && kw != "" {
+		keywords = parseCSV(kw)
+	}
+
+	lang, ok := cfg.Config["lang"]
+	if !ok {
+		lang = "en"
+	}
+
+	return &Bluesky{
+		name:     cfg.Name,
+		appView:  appView,
+		keywords: keywords,
+		lang:     strings.TrimSpace(lang),
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (b *Bluesky) Type() string {
+	return "bluesky"
+}
+
+func (b *Bluesky) Name() string {
+	return b.name
+}
+
+// Fetch searches the latest posts for each keyword
+func (b *Bluesky) Fetch(ctx context.Context) ([]Opportunity, error) {
+	seen := make(map[string]bool)
+	var opportunities []Opportunity
+	var lastErr error
+
+	for _, keyword := range b.keywords {
+		posts, err := b.searchPosts(ctx, keyword)
+		if err != nil {
+			lastErr = err
+			continue // Skip failed searches
+		}
+
+		for _, post := range posts {
+			if seen[post.URI] {
+				continue
+			}
+			seen[post.URI] = true
+			opportunities = append(opportunities, b.postToOpportunity(post, keyword))
+		}
+	}
+
+	// Only fail when no search could be run at all
+	if len(opportunities) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	return opportunities, nil
+}
+
+func (b *Bluesky) searchPosts(ctx context.Context, query string) ([]blueskyPost, error) {
+	params := url.Values{}
+	// Quote phrases so the search matches them rather than any of their words
+	if strings.Contains(query, " ") {
+		query = `"` + query + `"`
+	}
+	params.Set("q", query)
+	params.Set("sort", "latest")
+	params.Set("limit", "50")
+	if b.lang != "" {
+		params.Set("lang", b.lang)
+	}
+
+	reqURL := b.appView + "/xrpc/app.bsky.feed.searchPosts?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "Seer/1.0")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bluesky API returned status %d", resp.StatusCode)
+	}
+
+	var result blueskySearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Posts, nil
+}
+
+func (b *Bluesky) postToOpportunity(post blueskyPost, query string) Opportunity {
+	createdAt, _ := time.Parse(time.RFC3339, post.Record.CreatedAt)
+	if createdAt.IsZero() {
+		createdAt, _ = time.Parse(time.RFC3339, post.IndexedAt)
+	}
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	// Post URIs are at://{did}/app.bsky.feed.post/{rkey}
+	rkey := post.URI[strings.LastIndex(post.URI, "/")+1:]
+	profile := post.Author.Handle
+	if profile == "" {
+		profile = post.Author.DID
+	}
+
+	return Opportunity{
+		Title:            truncate(post.Record.Text, 100),
+		Description:      post.Record.Text,
+		SourceType:       "bluesky",
+		SourceURL:        fmt.Sprintf(blueskyPostURL, profile, rkey),
+		SourceIDExternal: post.URI,
+		DetectedAt:       createdAt,
+		Metadata: map[string]any{
+			"author":       post.Author.Handle,
+			"repost_count": post.RepostCount,
+			"quote_count":  post.QuoteCount,
+			"like_count":   post.LikeCount,
+			"reply_count":  post.ReplyCount,
+			"keyword":      query,
+		},
+	}
+}
@@ -0,0 +1,68 @@
+package sources
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBluesky_Fetch(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/xrpc/app.bsky.feed.searchPosts" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("sort") != "latest" || r.URL.Query().Get("lang") != "en" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		queries = append(queries, r.URL.Query().Get("q"))
+		w.Write([]byte(`{"posts": [
+			{"uri": "at://did:plc:abc/app.bsky.feed.post/3kxyz", "author": {"did": "did:plc:abc", "handle": "jane.bsky.social"}, "record": {"text": "Does anyone know a good invoicing tool for freelancers?", "createdAt": "2026-03-02T10:00:00.000Z"}, "replyCount": 14, "repostCount": 3, "likeCount": 25, "quoteCount": 1}
+		]}`))
+	}))
+	defer server.Close()
+
+	src, err := NewBluesky(SourceConfig{Name: "Test", URL: server.URL, Config: map[string]string{"keywords": "anyone know, invoicing"}})
+	if err != nil {
+		t.Fatalf("NewBluesky() error = %v", err)
+	}
+	if src.Type() != "bluesky" {
+		t.Errorf("expected type bluesky, got %s", src.Type())
+	}
+
+	opps, err := src.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(queries) != 2 || queries[0] != `"anyone know"` || queries[1] != "invoicing" {
+		t.Errorf("expected phrases to be quoted, got %v", queries)
+	}
+	if len(opps) != 1 {
+		t.Fatalf("expected posts deduplicated across keywords, got %d", len(opps))
+	}
+
+	opp := opps[0]
+	if opp.SourceURL != "https://bsky.app/profile/jane.bsky.social/post/3kxyz" || opp.SourceIDExternal != "at://did:plc:abc/app.bsky.feed.post/3kxyz" {
+		t.Errorf("unexpected source fields %+v", opp)
+	}
+	if !opp.DetectedAt.Equal(time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected date %v", opp.DetectedAt)
+	}
+	if opp.Metadata["like_count"] != 25 || opp.Metadata["repost_count"] != 3 || opp.Metadata["reply_count"] != 14 || opp.Metadata["keyword"] != "anyone know" {
+		t.Errorf("unexpected metadata %v", opp.Metadata)
+	}
+}
+
+func TestBluesky_FetchError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	src, _ := NewBluesky(SourceConfig{Name: "Test", URL: server.URL})
+	if _, err := src.Fetch(context.Background()); err == nil {
+		t.Error("expected error when every search fails")
+	}
+}
@@ -0,0 +1,168 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	cratesAPI      = "https://crates.io/api/v1/crates"
+	cratesCrateURL = "https://crates.io/crates/"
+)
+
+// Crates fetches new Rust crates from crates.io
+type Crates struct {
+	name    string
+	apiURL  string
+	queries []string
+	window  time.Duration
+	client  *http.Client
+}
+
+// cratesResponse represents the crates.io crate listing response
+type cratesResponse struct {
+	Crates []cratesCrate `json:"crates"`
+}
+
+type cratesCrate struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	Description     string    `json:"description"`
+	MaxVersion      string    `json:"max_version"`
+	NewestVersion   string    `json:"newest_version"`
+	Downloads       int       `json:"downloads"`
+	RecentDownloads int       `json:"recent_downloads"`
+	Homepage        string    `json:"homepage"`
+	Repository      string    `json:"repository"`
+	Documentation   string    `json:"documentation"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// NewCrates creates a new crates.io source. The optional "queries" config is a
+// comma-separated list of search terms, and "days" how recently a crate must have
+// been published (default 14).
+func NewCrates(cfg SourceConfig) (Source, error) {
+	queries, window, err := registryConfig(cfg, 14)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Crates{
+		name:    cfg.Name,
+		apiURL:  cratesAPI,
+		queries: queries,
+		window:  window,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Type returns the source type
+func (c *Crates) Type() string {
+	return "crates"
+}
+
+// Name returns the source name
+func (c *Crates) Name() string {
+	return c.name
+}
+
+// Fetch searches the newest crates for each query
+func (c *Crates) Fetch(ctx context.Context) ([]Opportunity, error) {
+	seen := make(map[string]bool)
+	var opportunities []Opportunity
+	var lastErr error
+	cutoff := time.Now().Add(-c.window)
+
+	for _, query := range c.queries {
+		crates, err := c.search(ctx, query)
+		if err != nil {
+			lastErr = err
+			continue // Skip failed queries
+		}
+
+		for _, crate := range crates {
+			if seen[crate.ID] || crate.CreatedAt.Before(cutoff) {
+				continue
+			}
+			seen[crate.ID] = true
+
+			opportunities = append(opportunities, c.crateToOpportunity(crate))
+		}
+	}
+
+	// Only fail when no query could be run at all
+	if len(opportunities) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	return opportunities, nil
+}
+
+func (c *Crates) search(ctx context.Context, query string) ([]cratesCrate, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("sort", "new")
+	params.Set("per_page", "25")
+
+	reqURL := c.apiURL + "?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// crates.io rejects requests without a descriptive User-Agent
+	req.Header.Set("User-Agent", "Seer/1.0 (https://github.com/mx-seer/seer)")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	var result cratesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Crates, nil
+}
+
+func (c *Crates) crateToOpportunity(crate cratesCrate) Opportunity {
+	version := crate.NewestVersion
+	if version == "" {
+		version = crate.MaxVersion
+	}
+
+	description := crate.Description
+	if description == "" {
+		description = fmt.Sprintf("Rust crate: %s v%s", crate.Name, version)
+	}
+
+	return Opportunity{
+		Title:            crate.Name,
+		Description:      truncate(description, 1000),
+		SourceType:       "crates",
+		SourceURL:        cratesCrateURL + crate.ID,
+		SourceIDExternal: crate.ID + "@" + version,
+		DetectedAt:       crate.CreatedAt,
+		Metadata: map[string]any{
+			"version":          version,
+			"downloads":        crate.Downloads,
+			"recent_downloads": crate.RecentDownloads,
+			"homepage":         crate.Homepage,
+			"repository":       crate.Repository,
+			"documentation":    crate.Documentation,
+			"updated_at":       crate.UpdatedAt,
+		},
+	}
+}
@@ -0,0 +1,254 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// discourseMaxPages bounds how many pages of each topic list are read per fetch
+const discourseMaxPages = 3
+
+// Discourse fetches topics from a Discourse forum's category and latest lists
+type Discourse struct {
+	name        string
+	baseURL     string
+	categories  []string
+	apiKey      string
+	apiUsername string
+	client      *http.Client
+}
+
+// discourseTopicList represents a Discourse topic list response
+type discourseTopicList struct {
+	Users []struct {
+		ID       int    `json:"id"`
+		Username string `json:"username"`
+	} `json:"users"`
+	TopicList struct {
+		MoreTopicsURL string           `json:"more_topics_url"`
+		Topics        []discourseTopic `json:"topics"`
+	} `json:"topic_list"`
+}
+
+type discourseTopic struct {
+	ID         int            `json:"id"`
+	Title      string         `json:"title"`
+	Slug       string         `json:"slug"`
+	Excerpt    string         `json:"excerpt"`
+	CreatedAt  time.Time      `json:"created_at"`
+	PostsCount int            `json:"posts_count"`
+	ReplyCount int            `json:"reply_count"`
+	LikeCount  int            `json:"like_count"`
+	Views      int            `json:"views"`
+	VoteCount  *int           `json:"vote_count"` // Only with the topic voting plugin
+	Pinned     bool           `json:"pinned"`
+	Closed     bool           `json:"closed"`
+	CategoryID int            `json:"category_id"`
+	Tags       []discourseTag `json:"tags"`
+	Posters    []struct {
+		Description string `json:"description"`
+		UserID      int    `json:"user_id"`
+	} `json:"posters"`
+}
+
+// discourseTag is a topic tag, which newer Discourse versions send as an object
+type discourseTag string
+
+func (t *discourseTag) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*t = discourseTag(name)
+		return nil
+	}
+	var tag struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &tag); err != nil {
+		return err
+	}
+	*t = discourseTag(tag.Name)
+	return nil
+}
+
+// NewDiscourse creates a new Discourse source. The source URL is the forum; the
+// optional "categories" config is a comma-separated list of category slugs to
+// read, otherwise the latest topics are read. Forums that require signing in
+// take an "api_key", used as "api_username" (default system).
+func NewDiscourse(cfg SourceConfig) (Source, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("discourse source requires a URL")
+	}
+
+	apiUsername := cfg.Config["api_username"]
+	if apiUsername == "" {
+		apiUsername = "system"
+	}
+
+	return &Discourse{
+		name:        cfg.Name,
+		baseURL:     strings.TrimRight(cfg.URL, "/"),
+		categories:  parseCSV(cfg.Config["categories"]),
+		apiKey:      cfg.Config["api_key"],
+		apiUsername: apiUsername,
+		client:      &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Type returns the source type
+func (d *Discourse) Type() string {
+	return "discourse"
+}
+
+// Name returns the source name
+func (d *Discourse) Name() string {
+	return d.name
+}
+
+// Fetch reads the first pages of each category, or of the latest topics
+func (d *Discourse) Fetch(ctx context.Context) ([]Opportunity, error) {
+	paths := []string{"/latest.json"}
+	if len(d.categories) > 0 {
+		paths = paths[:0]
+		for _, slug := range d.categories {
+			paths = append(paths, "/c/"+strings.Trim(slug, "/")+".json")
+		}
+	}
+
+	seen := make(map[int]bool)
+	var opportunities []Opportunity
+	var lastErr error
+
+	for _, path := range paths {
+		for page := 0; page < discourseMaxPages; page++ {
+			list, err := d.fetchTopics(ctx, path, page)
+			if err != nil {
+				lastErr = err
+				break // Keep the pages already read
+			}
+
+			usernames := make(map[int]string, len(list.Users))
+			for _, user := range list.Users {
+				usernames[user.ID] = user.Username
+			}
+
+			for _, topic := range list.TopicList.Topics {
+				// Pinned topics are category descriptions and announcements
+				if topic.Pinned || seen[topic.ID] {
+					continue
+				}
+				seen[topic.ID] = true
+				opportunities = append(opportunities, d.topicToOpportunity(topic, usernames))
+			}
+
+			if list.TopicList.MoreTopicsURL == "" {
+				break
+			}
+		}
+	}
+
+	// Only fail when no list could be read at all
+	if len(opportunities) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	return opportunities, nil
+}
+
+func (d *Discourse) fetchTopics(ctx context.Context, path string, page int) (*discourseTopicList, error) {
+	reqURL := d.baseURL + path
+	if page > 0 {
+		reqURL += "?page=" + strconv.Itoa(page)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "Seer/1.0")
+	req.Header.Set("Accept", "application/json")
+	if d.apiKey != "" {
+		req.Header.Set("Api-Key", d.apiKey)
+		req.Header.Set("Api-Username", d.apiUsername)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status for %s: %d", path, resp.StatusCode)
+	}
+
+	var list discourseTopicList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &list, nil
+}
+
+func (d *Discourse) topicToOpportunity(topic discourseTopic, usernames map[int]string) Opportunity {
+	description := cleanText(topic.Excerpt)
+	if description == "" {
+		description = topic.Title
+	}
+
+	createdAt := topic.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	author := ""
+	for _, poster := range topic.Posters {
+		if strings.Contains(poster.Description, "Original Poster") {
+			author = usernames[poster.UserID]
+			break
+		}
+	}
+
+	tags := make([]string, 0, len(topic.Tags))
+	for _, tag := range topic.Tags {
+		tags = append(tags, string(tag))
+	}
+
+	metadata := map[string]any{
+		"author":      author,
+		"like_count":  topic.LikeCount,
+		"reply_count": topic.ReplyCount,
+		"posts_count": topic.PostsCount,
+		"views":       topic.Views,
+		"category_id": topic.CategoryID,
+		"closed":      topic.Closed,
+		"tags":        tags,
+	}
+	if topic.VoteCount != nil {
+		metadata["vote_count"] = *topic.VoteCount
+	}
+
+	return Opportunity{
+		Title:            topic.Title,
+		Description:      truncate(description, 1000),
+		SourceType:       "discourse",
+		SourceURL:        fmt.Sprintf("%s/t/%s/%d", d.baseURL, topic.Slug, topic.ID),
+		SourceIDExternal: d.host() + ":" + strconv.Itoa(topic.ID),
+		DetectedAt:       createdAt,
+		Metadata:         metadata,
+	}
+}
+
+// host returns the forum host, which scopes topic IDs across forums
+func (d *Discourse) host() string {
+	if u, err := url.Parse(d.baseURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return d.baseURL
+}
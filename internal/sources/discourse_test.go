@@ -0,0 +1,96 @@
+package sources
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDiscourse_Config(t *testing.T) {
+	if _, err := NewDiscourse(SourceConfig{Name: "No URL"}); err == nil {
+		t.Error("expected error without a forum URL")
+	}
+}
+
+func TestDiscourse_Fetch(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path+"?"+r.URL.RawQuery)
+		if r.Header.Get("Api-Key") != "secret" || r.Header.Get("Api-Username") != "system" {
+			t.Errorf("expected API key headers, got %v", r.Header)
+		}
+
+		switch {
+		case r.URL.Path == "/c/feature-requests.json" && r.URL.Query().Get("page") == "":
+			w.Write([]byte(`{
+				"users": [{"id": 7, "username": "jane"}],
+				"topic_list": {"more_topics_url": "/c/feature-requests/5/l/latest?page=1", "topics": [
+					{"id": 1, "title": "About the Feature Requests category", "slug": "about", "pinned": true},
+					{"id": 42, "title": "Offline sync for mobile", "slug": "offline-sync-for-mobile", "excerpt": "It would be great to &hellip; sync <b>offline</b>", "created_at": "2026-03-02T10:00:00.000Z", "posts_count": 31, "reply_count": 28, "like_count": 95, "views": 4200, "vote_count": 180, "category_id": 5, "tags": ["mobile", {"id": 3, "name": "sync", "slug": "sync"}], "posters": [{"description": "Original Poster, Most Recent Poster", "user_id": 7}]}
+				]}
+			}`))
+		case r.URL.Path == "/c/feature-requests.json":
+			w.Write([]byte(`{"topic_list": {"topics": [
+				{"id": 42, "title": "Offline sync for mobile", "slug": "offline-sync-for-mobile"},
+				{"id": 43, "title": "Dark mode", "slug": "dark-mode", "like_count": 3}
+			]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	src, _ := NewDiscourse(SourceConfig{Name: "Test", URL: server.URL + "/", Config: map[string]string{
+		"categories": "feature-requests",
+		"api_key":    "secret",
+	}})
+
+	opps, err := src.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if strings.Join(requests, ",") != "/c/feature-requests.json?,/c/feature-requests.json?page=1" {
+		t.Errorf("expected to follow pages until the last, got %v", requests)
+	}
+	if len(opps) != 2 {
+		t.Fatalf("expected pinned and duplicate topics to be skipped, got %d", len(opps))
+	}
+
+	opp := opps[0]
+	if opp.SourceType != "discourse" || opp.SourceURL != server.URL+"/t/offline-sync-for-mobile/42" {
+		t.Errorf("unexpected source fields %+v", opp)
+	}
+	if !strings.HasSuffix(opp.SourceIDExternal, ":42") {
+		t.Errorf("expected topic ID scoped to the forum, got %q", opp.SourceIDExternal)
+	}
+	if opp.Description != "It would be great to … sync offline" {
+		t.Errorf("expected excerpt without HTML, got %q", opp.Description)
+	}
+	if opp.Metadata["like_count"] != 95 || opp.Metadata["reply_count"] != 28 || opp.Metadata["views"] != 4200 || opp.Metadata["vote_count"] != 180 || opp.Metadata["author"] != "jane" {
+		t.Errorf("unexpected metadata %v", opp.Metadata)
+	}
+	if tags := opp.Metadata["tags"].([]string); len(tags) != 2 || tags[1] != "sync" {
+		t.Errorf("expected string and object tags, got %v", tags)
+	}
+
+	if _, ok := opps[1].Metadata["vote_count"]; ok {
+		t.Error("expected no vote count without the voting plugin")
+	}
+}
+
+func TestDiscourse_Latest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/latest.json" {
+			t.Errorf("expected latest topics without categories, got %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	src, _ := NewDiscourse(SourceConfig{Name: "Test", URL: server.URL})
+	if _, err := src.Fetch(context.Background()); err == nil {
+		t.Error("expected error when no list can be read")
+	}
+}
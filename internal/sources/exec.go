@@ -0,0 +1,198 @@
+package sources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// execDefaultTimeout bounds a command run when no "timeout" is configured
+	execDefaultTimeout = time.Minute
+
+	// execMaxOutput bounds how much of stdout and of stderr is read
+	execMaxOutput = 10 << 20
+
+	// execMaxWarnings bounds how many warnings a run reports
+	execMaxWarnings = 50
+
+	// execEnvPrefix marks config keys that set environment variables
+	execEnvPrefix = "env_"
+)
+
+// Exec runs a local command that writes opportunities to stdout as
+// newline-delimited JSON
+type Exec struct {
+	id       int64
+	name     string
+	args     []string
+	dir      string
+	env      []string
+	timeout  time.Duration
+	warnings []string
+}
+
+// NewExec creates a new exec source. The "command" config is the program and its
+// arguments, separated by spaces (there is no shell quoting; wrap anything more
+// involved in a script). "dir" sets the working directory, "timeout" how long the
+// command may run (a duration such as 30s, default 1m), and each "env_NAME" key
+// sets the environment variable NAME on top of Seer's own environment.
+func NewExec(cfg SourceConfig) (Source, error) {
+	args := strings.Fields(cfg.Config["command"])
+	if len(args) == 0 {
+		return nil, fmt.Errorf("exec source requires a command in config")
+	}
+
+	timeout := execDefaultTimeout
+	if t, ok := cfg.Config["timeout"]; ok && t != "" {
+		d, err := time.ParseDuration(t)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid timeout: %s", t)
+		}
+		timeout = d
+	}
+
+	var env []string
+	for key, value := range cfg.Config {
+		if name, ok := strings.CutPrefix(key, execEnvPrefix); ok && name != "" {
+			env = append(env, name+"="+value)
+		}
+	}
+	sort.Strings(env)
+
+	return &Exec{
+		id:      cfg.ID,
+		name:    cfg.Name,
+		args:    args,
+		dir:     cfg.Config["dir"],
+		env:     env,
+		timeout: timeout,
+	}, nil
+}
+
+// newDisabledExec is the exec factory while exec sources are not allowed
+func newDisabledExec(cfg SourceConfig) (Source, error) {
+	return nil, fmt.Errorf("exec sources are disabled; set sources.allow_exec in the config to run them")
+}
+
+// Type returns the source type
+func (e *Exec) Type() string {
+	return "exec"
+}
+
+// Name returns the source name
+func (e *Exec) Name() string {
+	return e.name
+}
+
+// Warnings returns the stderr output and the rejected lines of the last run
+func (e *Exec) Warnings() []string {
+	return e.warnings
+}
+
+// Fetch runs the command and reads an opportunity from each line of its output.
+// Lines that are not valid opportunities are reported as warnings and skipped.
+func (e *Exec) Fetch(ctx context.Context) ([]Opportunity, error) {
+	e.warnings = nil
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	var stdout, stderr execOutput
+	cmd := exec.CommandContext(ctx, e.args[0], e.args[1:]...)
+	cmd.Dir = e.dir
+	cmd.Env = append(os.Environ(), e.env...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Don't wait on children that keep the output open after the command is killed
+	cmd.WaitDelay = 5 * time.Second
+
+	runErr := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		runErr = fmt.Errorf("command timed out after %s", e.timeout)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(stderr.buf.String()), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			e.warn("stderr: " + line)
+		}
+	}
+	if stderr.truncated {
+		e.warn("stderr: output truncated")
+	}
+
+	opportunities := e.parseOutput(stdout.buf.Bytes())
+	if stdout.truncated {
+		e.warn(fmt.Sprintf("stdout truncated after %d bytes", execMaxOutput))
+	}
+
+	// A failed command only fails the fetch when it produced nothing usable
+	if runErr != nil {
+		if len(opportunities) == 0 {
+			return nil, fmt.Errorf("command failed: %w", runErr)
+		}
+		e.warn(fmt.Sprintf("command failed: %v", runErr))
+	}
+
+	return opportunities, nil
+}
+
+// parseOutput reads an opportunity from each non-empty line of the output
+func (e *Exec) parseOutput(output []byte) []Opportunity {
+	var opportunities []Opportunity
+
+	for i, line := range bytes.Split(output, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var opp Opportunity
+		if err := json.Unmarshal(line, &opp); err != nil {
+			e.warn(fmt.Sprintf("line %d: invalid JSON: %v", i+1, err))
+			continue
+		}
+		if err := preparePushed(&opp, "exec"); err != nil {
+			e.warn(fmt.Sprintf("line %d: %v", i+1, err))
+			continue
+		}
+		opp.SourceIDExternal = scopeExternalID(e.id, opp.SourceIDExternal)
+
+		opportunities = append(opportunities, opp)
+	}
+
+	return opportunities
+}
+
+// warn adds a warning, up to execMaxWarnings
+func (e *Exec) warn(warning string) {
+	switch {
+	case len(e.warnings) < execMaxWarnings:
+		e.warnings = append(e.warnings, truncate(warning, 500))
+	case len(e.warnings) == execMaxWarnings:
+		e.warnings = append(e.warnings, "further warnings omitted")
+	}
+}
+
+// execOutput collects command output up to execMaxOutput, discarding the rest so
+// the command is never blocked on a full pipe
+type execOutput struct {
+	buf       bytes.Buffer
+	truncated bool
+}
+
+func (o *execOutput) Write(p []byte) (int, error) {
+	if room := execMaxOutput - o.buf.Len(); len(p) > room {
+		o.buf.Write(p[:max(room, 0)])
+		o.truncated = true
+		return len(p), nil
+	}
+	return o.buf.Write(p)
+}
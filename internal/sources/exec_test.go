@@ -0,0 +1,109 @@
+package sources
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeScript writes a shell script into a temporary directory, returning the directory
+func writeScript(t *testing.T, script string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "scrape.sh"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestExec_Config(t *testing.T) {
+	if _, err := NewExec(SourceConfig{Name: "No command"}); err == nil {
+		t.Error("expected error without a command")
+	}
+	if _, err := NewExec(SourceConfig{Name: "Bad timeout", Config: map[string]string{"command": "true", "timeout": "soon"}}); err == nil {
+		t.Error("expected error for an invalid timeout")
+	}
+	if _, err := newDisabledExec(SourceConfig{Name: "Disabled"}); err == nil || !strings.Contains(err.Error(), "allow_exec") {
+		t.Errorf("expected disabled exec sources to point at allow_exec, got %v", err)
+	}
+}
+
+func TestExec_Fetch(t *testing.T) {
+	dir := writeScript(t, `
+echo "scraping $SCRAPE_TARGET" >&2
+echo '{"title": "Invoicing for freelancers", "description": "'"$(basename "$PWD")"'", "source_url": "https://example.com/1", "metadata": {"points": 12}}'
+echo 'not json'
+echo ''
+echo '{"description": "no title"}'
+echo '{"title": "Second", "source_type": "reddit", "source_id_external": "abc", "detected_at": "2026-03-02T10:00:00Z"}'
+`)
+
+	src, err := NewExec(SourceConfig{ID: 7, Name: "Test", Config: map[string]string{
+		"command":           "sh scrape.sh",
+		"dir":               dir,
+		"env_SCRAPE_TARGET": "forum",
+	}})
+	if err != nil {
+		t.Fatalf("NewExec() error = %v", err)
+	}
+
+	opps, err := src.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(opps) != 2 {
+		t.Fatalf("expected bad lines to be skipped, got %d", len(opps))
+	}
+
+	opp := opps[0]
+	if opp.SourceType != "exec" || opp.SourceIDExternal != "7:https://example.com/1" || opp.Description != filepath.Base(dir) {
+		t.Errorf("unexpected opportunity %+v", opp)
+	}
+	if opp.Metadata["points"] != float64(12) || opp.DetectedAt.IsZero() {
+		t.Errorf("unexpected metadata or date %+v", opp)
+	}
+	if opps[1].SourceType != "exec" || opps[1].SourceIDExternal != "7:abc" || !opps[1].DetectedAt.Equal(time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected opportunity %+v", opps[1])
+	}
+
+	// Another exec source printing the same lines keeps its own rows
+	other, _ := NewExec(SourceConfig{ID: 8, Name: "Other", Config: map[string]string{"command": "sh scrape.sh", "dir": dir}})
+	if otherOpps, err := other.Fetch(context.Background()); err != nil || len(otherOpps) != 2 || otherOpps[0].SourceIDExternal != "8:https://example.com/1" {
+		t.Errorf("expected IDs scoped to the other source, got %+v, %v", otherOpps, err)
+	}
+
+	warnings := strings.Join(src.(WarningSource).Warnings(), "\n")
+	for _, want := range []string{"stderr: scraping forum", "line 2: invalid JSON", "line 4: missing title"} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("expected warning %q, got %q", want, warnings)
+		}
+	}
+}
+
+func TestExec_Failure(t *testing.T) {
+	dir := writeScript(t, `
+echo '{"title": "Partial", "source_url": "https://example.com/1"}'
+exit 3
+`)
+
+	src, _ := NewExec(SourceConfig{Name: "Test", Config: map[string]string{"command": "sh scrape.sh", "dir": dir}})
+	opps, err := src.Fetch(context.Background())
+	if err != nil || len(opps) != 1 {
+		t.Fatalf("expected the output of a failed command to be kept, got %d, %v", len(opps), err)
+	}
+	if warnings := src.(WarningSource).Warnings(); len(warnings) != 1 || !strings.Contains(warnings[0], "exit status 3") {
+		t.Errorf("expected the failure as a warning, got %v", warnings)
+	}
+
+	src, _ = NewExec(SourceConfig{Name: "Test", Config: map[string]string{"command": "sleep 5", "timeout": "100ms"}})
+	start := time.Now()
+	if _, err := src.Fetch(context.Background()); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Error("expected the command to be killed at the timeout")
+	}
+}
@@ -0,0 +1,499 @@
+package sources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// feedMaxBytes bounds the size of a feed document
+	feedMaxBytes = 10 << 20
+
+	// feedDescriptionLength is the longest entry description kept, in bytes
+	feedDescriptionLength = 1000
+
+	// State keys holding the HTTP cache validators of the last response
+	feedStateETag         = "etag"
+	feedStateLastModified = "last_modified"
+)
+
+// Feed fetches opportunities from an RSS 2.0, Atom 1.0 or JSON Feed 1.1 feed
+type Feed struct {
+	name     string
+	url      string
+	keywords []string
+	client   *http.Client
+
+	etag         string
+	lastModified string
+}
+
+// feedEntry is a feed item in any of the supported formats
+type feedEntry struct {
+	ID          string
+	Title       string
+	Link        string
+	Description string
+	Author      string
+	Categories  []string
+	Published   time.Time
+}
+
+// rssDocument is an RSS 2.0 feed
+type rssDocument struct {
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	GUID        string   `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Author      string   `xml:"author"`
+	Creator     string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories  []string `xml:"category"`
+}
+
+// atomDocument is an Atom 1.0 feed
+type atomDocument struct {
+	Title   string      `xml:"title"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID    string `xml:"id"`
+	Title string `xml:"title"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Summary   string `xml:"summary"`
+	Content   string `xml:"content"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+	Authors   []struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+	Categories []struct {
+		Term string `xml:"term,attr"`
+	} `xml:"category"`
+}
+
+// jsonFeedDocument is a JSON Feed 1.0 or 1.1 feed
+type jsonFeedDocument struct {
+	Version string         `json:"version"`
+	Title   string         `json:"title"`
+	Items   []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            any    `json:"id"`
+	URL           string `json:"url"`
+	ExternalURL   string `json:"external_url"`
+	Title         string `json:"title"`
+	ContentText   string `json:"content_text"`
+	ContentHTML   string `json:"content_html"`
+	Summary       string `json:"summary"`
+	DatePublished string `json:"date_published"`
+	DateModified  string `json:"date_modified"`
+	Authors       []struct {
+		Name string `json:"name"`
+	} `json:"authors"`
+	// Author is the JSON Feed 1.0 field replaced by Authors in 1.1
+	Author *struct {
+		Name string `json:"name"`
+	} `json:"author"`
+	Tags []string `json:"tags"`
+}
+
+// NewFeed creates a new feed source. The feed URL is the source URL; the optional
+// "keywords" config is a comma-separated list of which an entry must contain one.
+func NewFeed(cfg SourceConfig) (Source, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("feed source requires a URL")
+	}
+
+	var keywords []string
+	if kw, ok := cfg.Config["keywords"]; ok && kw != "" {
+		keywords = parseCSV(kw)
+	}
+
+	return &Feed{
+		name:         cfg.Name,
+		url:          cfg.URL,
+		keywords:     keywords,
+		client:       &http.Client{Timeout: 30 * time.Second},
+		etag:         cfg.State[feedStateETag],
+		lastModified: cfg.State[feedStateLastModified],
+	}, nil
+}
+
+func (f *Feed) Type() string {
+	return "feed"
+}
+
+func (f *Feed) Name() string {
+	return f.name
+}
+
+// State returns the cache validators of the last response, sent with the next fetch
+func (f *Feed) State() map[string]string {
+	state := make(map[string]string)
+	if f.etag != "" {
+		state[feedStateETag] = f.etag
+	}
+	if f.lastModified != "" {
+		state[feedStateLastModified] = f.lastModified
+	}
+	return state
+}
+
+// Fetch retrieves the feed, returning no opportunities if it has not changed since
+// the previous fetch
+func (f *Feed) Fetch(ctx context.Context) ([]Opportunity, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", "Seer/1.0")
+	req.Header.Set("Accept", "application/feed+json, application/atom+xml, application/rss+xml, application/xml;q=0.9, */*;q=0.8")
+	if f.etag != "" {
+		req.Header.Set("If-None-Match", f.etag)
+	}
+	if f.lastModified != "" {
+		req.Header.Set("If-Modified-Since", f.lastModified)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feed returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, feedMaxBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := parseFeed(body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only remember the validators once the response has been read successfully
+	f.etag = resp.Header.Get("ETag")
+	f.lastModified = resp.Header.Get("Last-Modified")
+
+	var opportunities []Opportunity
+	for _, entry := range entries {
+		opp, ok := f.entryToOpportunity(entry)
+		if !ok {
+			continue
+		}
+		if len(f.keywords) > 0 && !containsAnyKeyword(opp.Title+" "+opp.Description, f.keywords) {
+			continue
+		}
+		opportunities = append(opportunities, opp)
+	}
+
+	return opportunities, nil
+}
+
+func (f *Feed) entryToOpportunity(entry feedEntry) (Opportunity, bool) {
+	id := entry.ID
+	if id == "" {
+		id = entry.Link
+	}
+	if id == "" || entry.Title == "" {
+		return Opportunity{}, false
+	}
+
+	detectedAt := entry.Published
+	if detectedAt.IsZero() {
+		detectedAt = time.Now()
+	}
+
+	metadata := map[string]any{
+		"feed": f.name,
+	}
+	if entry.Author != "" {
+		metadata["author"] = entry.Author
+	}
+	if len(entry.Categories) > 0 {
+		metadata["categories"] = entry.Categories
+	}
+
+	return Opportunity{
+		Title:            entry.Title,
+		Description:      truncate(entry.Description, feedDescriptionLength),
+		SourceType:       "feed",
+		SourceURL:        entry.Link,
+		SourceIDExternal: id,
+		DetectedAt:       detectedAt,
+		Metadata:         metadata,
+	}, true
+}
+
+// parseFeed detects the format of a feed document and returns its entries
+func parseFeed(data []byte) ([]feedEntry, error) {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("empty feed")
+	}
+
+	if trimmed[0] == '{' {
+		return parseJSONFeed(trimmed)
+	}
+
+	root, err := xmlRoot(trimmed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+
+	switch root.Local {
+	case "rss":
+		return parseRSS(trimmed)
+	case "feed":
+		return parseAtom(trimmed)
+	default:
+		return nil, fmt.Errorf("unsupported feed format: <%s>", root.Local)
+	}
+}
+
+// xmlRoot returns the name of the root element of an XML document
+func xmlRoot(data []byte) (xml.Name, error) {
+	decoder := newXMLDecoder(data)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return xml.Name{}, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name, nil
+		}
+	}
+}
+
+// newXMLDecoder returns a lenient decoder, since feeds in the wild are often not
+// strictly valid XML
+func newXMLDecoder(data []byte) *xml.Decoder {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	// Non-UTF-8 feeds are read as is rather than rejected
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	return decoder
+}
+
+func parseRSS(data []byte) ([]feedEntry, error) {
+	var doc rssDocument
+	if err := newXMLDecoder(data).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse RSS feed: %w", err)
+	}
+
+	entries := make([]feedEntry, 0, len(doc.Channel.Items))
+	for _, item := range doc.Channel.Items {
+		description := item.Description
+		if description == "" {
+			description = item.Content
+		}
+		author := item.Creator
+		if author == "" {
+			author = item.Author
+		}
+
+		entries = append(entries, feedEntry{
+			ID:          strings.TrimSpace(item.GUID),
+			Title:       cleanText(item.Title),
+			Link:        strings.TrimSpace(item.Link),
+			Description: cleanText(description),
+			Author:      strings.TrimSpace(author),
+			Categories:  trimAll(item.Categories),
+			Published:   parseFeedTime(item.PubDate),
+		})
+	}
+
+	return entries, nil
+}
+
+func parseAtom(data []byte) ([]feedEntry, error) {
+	var doc atomDocument
+	if err := newXMLDecoder(data).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse Atom feed: %w", err)
+	}
+
+	entries := make([]feedEntry, 0, len(doc.Entries))
+	for _, e := range doc.Entries {
+		// The alternate link is the entry's page; a link without rel is alternate too
+		var link string
+		for _, l := range e.Links {
+			if l.Rel == "" || l.Rel == "alternate" {
+				link = l.Href
+				break
+			}
+		}
+
+		description := e.Summary
+		if description == "" {
+			description = e.Content
+		}
+		published := e.Published
+		if published == "" {
+			published = e.Updated
+		}
+
+		var authors, categories []string
+		for _, a := range e.Authors {
+			authors = append(authors, a.Name)
+		}
+		for _, c := range e.Categories {
+			categories = append(categories, c.Term)
+		}
+
+		entries = append(entries, feedEntry{
+			ID:          strings.TrimSpace(e.ID),
+			Title:       cleanText(e.Title),
+			Link:        strings.TrimSpace(link),
+			Description: cleanText(description),
+			Author:      strings.Join(trimAll(authors), ", "),
+			Categories:  trimAll(categories),
+			Published:   parseFeedTime(published),
+		})
+	}
+
+	return entries, nil
+}
+
+func parseJSONFeed(data []byte) ([]feedEntry, error) {
+	var doc jsonFeedDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON feed: %w", err)
+	}
+	if !strings.HasPrefix(doc.Version, "https://jsonfeed.org/version/") {
+		return nil, fmt.Errorf("unsupported JSON feed version %q", doc.Version)
+	}
+
+	entries := make([]feedEntry, 0, len(doc.Items))
+	for _, item := range doc.Items {
+		link := item.URL
+		if link == "" {
+			link = item.ExternalURL
+		}
+
+		description := item.Summary
+		if description == "" {
+			description = item.ContentText
+		}
+		if description == "" {
+			description = item.ContentHTML
+		}
+
+		published := item.DatePublished
+		if published == "" {
+			published = item.DateModified
+		}
+
+		var authors []string
+		for _, a := range item.Authors {
+			authors = append(authors, a.Name)
+		}
+		if len(authors) == 0 && item.Author != nil {
+			authors = append(authors, item.Author.Name)
+		}
+
+		// The spec requires a string ID, but some feeds use numbers
+		var id string
+		switch v := item.ID.(type) {
+		case string:
+			id = v
+		case float64:
+			id = fmt.Sprintf("%.0f", v)
+		}
+
+		entries = append(entries, feedEntry{
+			ID:          strings.TrimSpace(id),
+			Title:       cleanText(item.Title),
+			Link:        strings.TrimSpace(link),
+			Description: cleanText(description),
+			Author:      strings.Join(trimAll(authors), ", "),
+			Categories:  trimAll(item.Tags),
+			Published:   parseFeedTime(published),
+		})
+	}
+
+	return entries, nil
+}
+
+// feedTimeLayouts are the date formats found in feeds, RFC 3339 for Atom and JSON Feed
+// and RFC 822 variants for RSS
+var feedTimeLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, _2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC822Z,
+	time.RFC822,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// parseFeedTime parses a feed date, returning the zero time if it is not recognized
+func parseFeedTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range feedTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+var (
+	htmlTagPattern    = regexp.MustCompile(`<[^>]*>`)
+	whitespacePattern = regexp.MustCompile(`\s+`)
+)
+
+// cleanText strips HTML tags and entities and collapses whitespace
+func cleanText(s string) string {
+	s = htmlTagPattern.ReplaceAllString(s, " ")
+	s = html.UnescapeString(s)
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(s, " "))
+}
+
+// trimAll trims each string and drops empty ones
+func trimAll(items []string) []string {
+	var result []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,179 @@
+package sources
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// serveFixture serves a testdata file with cache validators, answering conditional
+// requests with 304 Not Modified
+func serveFixture(t *testing.T, name, contentType string) (*httptest.Server, *int) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	const etag = `"v1"`
+	const lastModified = "Tue, 03 Mar 2026 12:00:00 GMT"
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag && r.Header.Get("If-Modified-Since") == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func fetchFeed(t *testing.T, url string, config map[string]string, state map[string]string) (*Feed, []Opportunity) {
+	t.Helper()
+
+	src, err := NewFeed(SourceConfig{Name: "Test feed", URL: url, Config: config, State: state})
+	if err != nil {
+		t.Fatalf("NewFeed() error = %v", err)
+	}
+
+	feed := src.(*Feed)
+	opps, err := feed.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	return feed, opps
+}
+
+func TestFeed_RequiresURL(t *testing.T) {
+	if _, err := NewFeed(SourceConfig{Name: "No URL"}); err == nil {
+		t.Error("expected error without a URL")
+	}
+}
+
+func TestFeed_RSS(t *testing.T) {
+	server, _ := serveFixture(t, "rss.xml", "application/rss+xml")
+
+	_, opps := fetchFeed(t, server.URL, nil, nil)
+	if len(opps) != 2 {
+		t.Fatalf("expected 2 opportunities, got %d", len(opps))
+	}
+
+	opp := opps[0]
+	if opp.Title != "Looking for an alternative to Zapier for small teams" {
+		t.Errorf("unexpected title %q", opp.Title)
+	}
+	if opp.SourceType != "feed" || opp.SourceIDExternal != "makers-1042" {
+		t.Errorf("expected GUID as external ID, got %s/%s", opp.SourceType, opp.SourceIDExternal)
+	}
+	if opp.SourceURL != "https://makers.example.com/posts/zapier-alternative" {
+		t.Errorf("unexpected URL %q", opp.SourceURL)
+	}
+	if opp.Description != "We pay $300/month and only use two workflows & a webhook." {
+		t.Errorf("expected description without HTML, got %q", opp.Description)
+	}
+	if !opp.DetectedAt.Equal(time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("unexpected date %v", opp.DetectedAt)
+	}
+	if opp.Metadata["author"] != "jane" || len(opp.Metadata["categories"].([]string)) != 2 {
+		t.Errorf("unexpected metadata %v", opp.Metadata)
+	}
+
+	// Without a GUID the link identifies the entry
+	if opps[1].SourceIDExternal != "https://makers.example.com/posts/mountains" || opps[1].Description != "No software here." {
+		t.Errorf("expected link as external ID and content as description, got %+v", opps[1])
+	}
+}
+
+func TestFeed_Atom(t *testing.T) {
+	server, _ := serveFixture(t, "atom.xml", "application/atom+xml")
+
+	_, opps := fetchFeed(t, server.URL, nil, nil)
+	if len(opps) != 2 {
+		t.Fatalf("expected 2 opportunities, got %d", len(opps))
+	}
+
+	opp := opps[0]
+	if opp.Title != "Why we built our own invoice tool" {
+		t.Errorf("unexpected title %q", opp.Title)
+	}
+	if opp.SourceIDExternal != "tag:blog.example.com,2026:7" || opp.SourceURL != "https://blog.example.com/invoice-tool" {
+		t.Errorf("expected entry ID and alternate link, got %s %s", opp.SourceIDExternal, opp.SourceURL)
+	}
+	if !opp.DetectedAt.Equal(time.Date(2026, 3, 3, 9, 15, 0, 0, time.UTC)) {
+		t.Errorf("expected published date, got %v", opp.DetectedAt)
+	}
+	if opp.Metadata["author"] != "Sam, Lee" {
+		t.Errorf("expected both authors, got %v", opp.Metadata["author"])
+	}
+
+	if opps[1].SourceIDExternal != "https://blog.example.com/release-notes" || opps[1].Description != "Bug fixes" {
+		t.Errorf("expected link as external ID and content as description, got %+v", opps[1])
+	}
+}
+
+func TestFeed_JSONFeed(t *testing.T) {
+	server, _ := serveFixture(t, "feed.json", "application/feed+json")
+
+	_, opps := fetchFeed(t, server.URL, nil, nil)
+	if len(opps) != 2 {
+		t.Fatalf("expected untitled item to be skipped, got %d opportunities", len(opps))
+	}
+
+	if opps[0].SourceIDExternal != "https://letter.example.com/p/spreadsheets" || opps[0].Description != "Small agencies hate their payroll tools." {
+		t.Errorf("unexpected first item %+v", opps[0])
+	}
+	if opps[1].SourceIDExternal != "99" || opps[1].SourceURL != "https://elsewhere.example.com/story" || opps[1].Metadata["author"] != "Old Style" {
+		t.Errorf("unexpected second item %+v", opps[1])
+	}
+}
+
+func TestFeed_Keywords(t *testing.T) {
+	server, _ := serveFixture(t, "rss.xml", "application/rss+xml")
+
+	_, opps := fetchFeed(t, server.URL, map[string]string{"keywords": "alternative, pricing"}, nil)
+	if len(opps) != 1 || opps[0].SourceIDExternal != "makers-1042" {
+		t.Errorf("expected only the entry matching a keyword, got %+v", opps)
+	}
+}
+
+func TestFeed_ConditionalGet(t *testing.T) {
+	server, requests := serveFixture(t, "atom.xml", "application/atom+xml")
+
+	feed, opps := fetchFeed(t, server.URL, nil, nil)
+	if len(opps) != 2 {
+		t.Fatalf("expected 2 opportunities, got %d", len(opps))
+	}
+
+	state := feed.State()
+	if state["etag"] != `"v1"` || state["last_modified"] != "Tue, 03 Mar 2026 12:00:00 GMT" {
+		t.Fatalf("expected cache validators in state, got %v", state)
+	}
+
+	feed, opps = fetchFeed(t, server.URL, nil, state)
+	if len(opps) != 0 || *requests != 2 {
+		t.Errorf("expected unchanged feed to yield nothing, got %d opportunities", len(opps))
+	}
+	if feed.State()["etag"] != `"v1"` {
+		t.Error("expected validators to be kept after 304")
+	}
+}
+
+func TestParseFeed_Unsupported(t *testing.T) {
+	for _, doc := range []string{"", "<html><body>Not a feed</body></html>", `{"version":"1","items":[]}`} {
+		if _, err := parseFeed([]byte(doc)); err == nil {
+			t.Errorf("expected error for %q", doc)
+		}
+	}
+}
@@ -3,9 +3,12 @@ package sources
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -14,10 +17,19 @@ const (
 	ghSearchAPI = ghAPIBase + "/search/repositories"
 )
 
+// ghMaxRateLimitWait is the longest a fetch waits for the rate limit to reset;
+// the search limit resets every minute, the core limit only every hour
+const ghMaxRateLimitWait = 2 * time.Minute
+
 // GitHub fetches trending repositories and issues from GitHub
 type GitHub struct {
-	config SourceConfig
-	client *http.Client
+	config   SourceConfig
+	client   *http.Client
+	apiURL   string
+	token    string
+	queries  []string
+	sleep    func(ctx context.Context, d time.Duration) error
+	warnings []string
 }
 
 // ghSearchResponse represents GitHub search API response
@@ -40,11 +52,42 @@ type ghRepo struct {
 	PushedAt        time.Time `json:"pushed_at"`
 }
 
-// NewGitHub creates a new GitHub source
+// ghRateLimit is the rate limit state GitHub reports with each response
+type ghRateLimit struct {
+	remaining int
+	reset     time.Time
+	known     bool
+}
+
+// ghRateLimitError is a request rejected because the rate limit was exhausted
+type ghRateLimitError struct {
+	reset time.Time
+}
+
+func (e *ghRateLimitError) Error() string {
+	return fmt.Sprintf("rate limit exceeded until %s", e.reset.Format("15:04:05"))
+}
+
+// NewGitHub creates a new GitHub source. The optional "token" config is a personal
+// access token, which raises the search rate limit from 10 to 30 requests per
+// minute. The optional "queries" config replaces the built-in search queries, one
+// per line; queries without a pushed: or created: qualifier only match repositories
+// pushed in the last 7 days.
 func NewGitHub(cfg SourceConfig) (Source, error) {
+	var queries []string
+	for _, line := range strings.Split(cfg.Config["queries"], "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			queries = append(queries, line)
+		}
+	}
+
 	return &GitHub{
-		config: cfg,
-		client: &http.Client{Timeout: 30 * time.Second},
+		config:  cfg,
+		client:  &http.Client{Timeout: 30 * time.Second},
+		apiURL:  ghSearchAPI,
+		token:   cfg.Config["token"],
+		queries: queries,
+		sleep:   sleepContext,
 	}, nil
 }
 
@@ -58,13 +101,86 @@ func (g *GitHub) Name() string {
 	return g.config.Name
 }
 
-// Fetch retrieves trending repositories from GitHub
+// Warnings returns the problems of the last fetch that left its results incomplete,
+// such as queries skipped because the rate limit ran out
+func (g *GitHub) Warnings() []string {
+	return g.warnings
+}
+
+// Fetch retrieves trending repositories from GitHub, pacing its queries to the
+// rate limit GitHub reports
 func (g *GitHub) Fetch(ctx context.Context) ([]Opportunity, error) {
+	g.warnings = nil
+
+	queries := g.searchQueries()
+	seen := make(map[int64]bool)
+	var opportunities []Opportunity
+	failed := 0
+
+	for i, query := range queries {
+		repos, limit, err := g.searchRepos(ctx, query)
+
+		var rateErr *ghRateLimitError
+		if errors.As(err, &rateErr) {
+			// Wait for the reset and retry the rejected query once
+			if !g.waitForReset(ctx, rateErr.reset, len(queries)-i) {
+				break
+			}
+			repos, limit, err = g.searchRepos(ctx, query)
+		}
+
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			failed++
+			g.warnings = append(g.warnings, fmt.Sprintf("query %q failed: %v", query, err))
+			continue
+		}
+
+		for _, repo := range repos {
+			if seen[repo.ID] {
+				continue
+			}
+			seen[repo.ID] = true
+
+			opp := g.repoToOpportunity(repo)
+			opportunities = append(opportunities, opp)
+		}
+
+		// Pace the remaining queries: once the limit is used up, wait for the reset
+		if remaining := len(queries) - i - 1; remaining > 0 && limit.known && limit.remaining == 0 {
+			if !g.waitForReset(ctx, limit.reset, remaining) {
+				break
+			}
+		}
+	}
+
+	if failed > 0 && failed == len(queries) {
+		return nil, fmt.Errorf("all %d GitHub queries failed: %s", failed, g.warnings[len(g.warnings)-1])
+	}
+
+	return opportunities, nil
+}
+
+// searchQueries returns the configured queries, or the built-in ones
+func (g *GitHub) searchQueries() []string {
 	// All queries filter by pushed date (last 7 days)
 	dateFilter := " pushed:>" + time.Now().AddDate(0, 0, -7).Format("2006-01-02")
 
+	if len(g.queries) > 0 {
+		queries := make([]string, len(g.queries))
+		for i, query := range g.queries {
+			if !strings.Contains(query, "pushed:") && !strings.Contains(query, "created:") {
+				query += dateFilter
+			}
+			queries[i] = query
+		}
+		return queries
+	}
+
 	// Optimized queries for opportunity detection
-	queries := []string{
+	return []string{
 		// Trending new repos with traction
 		"stars:>50 created:>" + time.Now().AddDate(0, 0, -7).Format("2006-01-02"),
 		"stars:>10" + dateFilter,
@@ -99,63 +215,84 @@ func (g *GitHub) Fetch(ctx context.Context) ([]Opportunity, error) {
 		"\"golang\" \"self-hosted\"" + dateFilter,
 		"\"nuxt\" stars:>5" + dateFilter,
 	}
+}
 
-	seen := make(map[int64]bool)
-	var opportunities []Opportunity
-
-	for _, query := range queries {
-		repos, err := g.searchRepos(ctx, query)
-		if err != nil {
-			continue
-		}
-
-		for _, repo := range repos {
-			if seen[repo.ID] {
-				continue
-			}
-			seen[repo.ID] = true
-
-			opp := g.repoToOpportunity(repo)
-			opportunities = append(opportunities, opp)
-		}
+// waitForReset waits until the rate limit resets, reporting false with a warning
+// when the reset is too far away or the fetch is cancelled first
+func (g *GitHub) waitForReset(ctx context.Context, reset time.Time, pending int) bool {
+	wait := time.Until(reset) + time.Second // Reset times have second precision
+	if wait > ghMaxRateLimitWait {
+		g.warnings = append(g.warnings, fmt.Sprintf("GitHub rate limit exhausted until %s; skipped %d queries (set a token to raise the limit)", reset.Format("15:04:05"), pending))
+		return false
 	}
 
-	return opportunities, nil
+	if err := g.sleep(ctx, wait); err != nil {
+		return false
+	}
+	return true
 }
 
-func (g *GitHub) searchRepos(ctx context.Context, query string) ([]ghRepo, error) {
+func (g *GitHub) searchRepos(ctx context.Context, query string) ([]ghRepo, ghRateLimit, error) {
 	params := url.Values{}
 	params.Set("q", query)
 	params.Set("sort", "stars")
 	params.Set("order", "desc")
 	params.Set("per_page", "20")
 
-	reqURL := ghSearchAPI + "?" + params.Encode()
+	reqURL := g.apiURL + "?" + params.Encode()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, ghRateLimit{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("User-Agent", "Seer/1.0")
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
 
 	resp, err := g.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch: %w", err)
+		return nil, ghRateLimit{}, fmt.Errorf("failed to fetch: %w", err)
 	}
 	defer resp.Body.Close()
 
+	limit := parseGitHubRateLimit(resp.Header)
+
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		// Secondary limits send Retry-After instead of an exhausted primary limit
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			return nil, limit, &ghRateLimitError{reset: time.Now().Add(time.Duration(seconds) * time.Second)}
+		}
+		if limit.known && limit.remaining == 0 {
+			return nil, limit, &ghRateLimitError{reset: limit.reset}
+		}
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+		return nil, limit, fmt.Errorf("unexpected status: %d", resp.StatusCode)
 	}
 
 	var result ghSearchResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, limit, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return result.Items, nil
+	return result.Items, limit, nil
+}
+
+// parseGitHubRateLimit reads the X-RateLimit headers of a GitHub API response
+func parseGitHubRateLimit(header http.Header) ghRateLimit {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return ghRateLimit{}
+	}
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return ghRateLimit{}
+	}
+	return ghRateLimit{remaining: remaining, reset: time.Unix(reset, 0), known: true}
 }
 
 func (g *GitHub) repoToOpportunity(repo ghRepo) Opportunity {
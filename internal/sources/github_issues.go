@@ -0,0 +1,217 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ghIssuesSearchAPI = ghAPIBase + "/search/issues"
+
+// GitHubIssues fetches highly upvoted feature requests from GitHub issues
+type GitHubIssues struct {
+	name         string
+	apiURL       string
+	query        string
+	minReactions int
+	targets      []string
+	token        string
+	client       *http.Client
+}
+
+// ghIssuesResponse represents the GitHub issue search API response
+type ghIssuesResponse struct {
+	Items []ghIssue `json:"items"`
+}
+
+type ghIssue struct {
+	ID            int64     `json:"id"`
+	Number        int       `json:"number"`
+	Title         string    `json:"title"`
+	Body          string    `json:"body"`
+	HTMLURL       string    `json:"html_url"`
+	State         string    `json:"state"`
+	Comments      int       `json:"comments"`
+	RepositoryURL string    `json:"repository_url"`
+	CreatedAt     time.Time `json:"created_at"`
+	User          struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Reactions struct {
+		TotalCount int `json:"total_count"`
+		PlusOne    int `json:"+1"`
+	} `json:"reactions"`
+}
+
+// NewGitHubIssues creates a new GitHub issues source. Config keys: "query" (GitHub
+// search syntax, default open issues labelled as feature requests),
+// "min_reactions", "repos" and "orgs" (comma-separated targets, searched one at a
+// time) and an optional "token", which raises the search rate limit.
+func NewGitHubIssues(cfg SourceConfig) (Source, error) {
+	minReactions, err := strconv.Atoi(getConfigOrDefault(cfg.Config, "min_reactions", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid min_reactions: %w", err)
+	}
+
+	var targets []string
+	for _, repo := range parseCSV(cfg.Config["repos"]) {
+		if !strings.Contains(repo, "/") {
+			return nil, fmt.Errorf("invalid repo %q (use owner/name)", repo)
+		}
+		targets = append(targets, "repo:"+repo)
+	}
+	for _, org := range parseCSV(cfg.Config["orgs"]) {
+		targets = append(targets, "org:"+org)
+	}
+
+	return &GitHubIssues{
+		name:         cfg.Name,
+		apiURL:       ghIssuesSearchAPI,
+		query:        getConfigOrDefault(cfg.Config, "query", `is:open label:enhancement,"feature request"`),
+		minReactions: minReactions,
+		targets:      targets,
+		token:        cfg.Config["token"],
+		client:       &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Type returns the source type
+func (g *GitHubIssues) Type() string {
+	return "github_issues"
+}
+
+// Name returns the source name
+func (g *GitHubIssues) Name() string {
+	return g.name
+}
+
+// Fetch searches issues matching the query in each target, most reactions first
+func (g *GitHubIssues) Fetch(ctx context.Context) ([]Opportunity, error) {
+	queries := []string{g.buildQuery("")}
+	if len(g.targets) > 0 {
+		queries = queries[:0]
+		for _, target := range g.targets {
+			queries = append(queries, g.buildQuery(target))
+		}
+	}
+
+	seen := make(map[int64]bool)
+	var opportunities []Opportunity
+	var lastErr error
+
+	for _, query := range queries {
+		issues, err := g.searchIssues(ctx, query)
+		if err != nil {
+			lastErr = err
+			continue // Skip failed queries
+		}
+
+		for _, issue := range issues {
+			if seen[issue.ID] {
+				continue
+			}
+			seen[issue.ID] = true
+
+			opportunities = append(opportunities, g.issueToOpportunity(issue))
+		}
+	}
+
+	// Only fail when no query could be run at all
+	if len(opportunities) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	return opportunities, nil
+}
+
+// buildQuery restricts the configured query to issues, an optional target and the
+// minimum reactions
+func (g *GitHubIssues) buildQuery(target string) string {
+	parts := []string{g.query, "is:issue"}
+	if target != "" {
+		parts = append(parts, target)
+	}
+	if g.minReactions > 0 {
+		parts = append(parts, fmt.Sprintf("reactions:>=%d", g.minReactions))
+	}
+	return strings.Join(parts, " ")
+}
+
+func (g *GitHubIssues) searchIssues(ctx context.Context, query string) ([]ghIssue, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("sort", "reactions-+1")
+	params.Set("order", "desc")
+	params.Set("per_page", "50")
+
+	reqURL := g.apiURL + "?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "Seer/1.0")
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	var result ghIssuesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Items, nil
+}
+
+func (g *GitHubIssues) issueToOpportunity(issue ghIssue) Opportunity {
+	repo := strings.TrimPrefix(issue.RepositoryURL, ghAPIBase+"/repos/")
+
+	description := issue.Body
+	if description == "" {
+		description = fmt.Sprintf("Issue #%d in %s", issue.Number, repo)
+	}
+
+	labels := make([]string, 0, len(issue.Labels))
+	for _, label := range issue.Labels {
+		labels = append(labels, label.Name)
+	}
+
+	return Opportunity{
+		Title:            issue.Title,
+		Description:      truncate(description, 1000),
+		SourceType:       "github_issues",
+		SourceURL:        issue.HTMLURL,
+		SourceIDExternal: fmt.Sprintf("%d", issue.ID),
+		DetectedAt:       issue.CreatedAt,
+		Metadata: map[string]any{
+			"repo":      repo,
+			"number":    issue.Number,
+			"state":     issue.State,
+			"author":    issue.User.Login,
+			"reactions": issue.Reactions.TotalCount,
+			"thumbs_up": issue.Reactions.PlusOne,
+			"comments":  issue.Comments,
+			"labels":    labels,
+		},
+	}
+}
@@ -0,0 +1,108 @@
+package sources
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGitHubIssues_Config(t *testing.T) {
+	src, err := NewGitHubIssues(SourceConfig{Name: "Test Issues"})
+	if err != nil {
+		t.Fatalf("NewGitHubIssues() error = %v", err)
+	}
+	if src.Type() != "github_issues" || src.Name() != "Test Issues" {
+		t.Errorf("unexpected type/name %s/%s", src.Type(), src.Name())
+	}
+
+	gi := src.(*GitHubIssues)
+	if got := gi.buildQuery(""); got != `is:open label:enhancement,"feature request" is:issue reactions:>=10` {
+		t.Errorf("unexpected default query %s", got)
+	}
+
+	if _, err := NewGitHubIssues(SourceConfig{Config: map[string]string{"min_reactions": "lots"}}); err == nil {
+		t.Error("expected error for invalid min_reactions")
+	}
+	if _, err := NewGitHubIssues(SourceConfig{Config: map[string]string{"repos": "kubernetes"}}); err == nil {
+		t.Error("expected error for repo without owner")
+	}
+}
+
+func TestGitHubIssues_Fetch(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("expected token to be sent, got %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.Query().Get("sort") != "reactions-+1" {
+			t.Errorf("expected sort by thumbs up, got %s", r.URL.Query().Get("sort"))
+		}
+
+		w.Write([]byte(`{"items": [
+			{"id": 9001, "number": 42, "title": "Support exporting dashboards as PDF", "body": "", "html_url": "https://github.com/acme/dash/issues/42", "state": "open", "comments": 18, "repository_url": "https://api.github.com/repos/acme/dash", "created_at": "2026-02-10T08:00:00Z", "user": {"login": "jane"}, "labels": [{"name": "enhancement"}, {"name": "export"}], "reactions": {"total_count": 57, "+1": 51}}
+		]}`))
+	}))
+	defer server.Close()
+
+	src, _ := NewGitHubIssues(SourceConfig{Name: "Test", Config: map[string]string{
+		"query":         "is:open label:enhancement",
+		"min_reactions": "25",
+		"repos":         "acme/dash",
+		"orgs":          "acme",
+		"token":         "secret",
+	}})
+	gi := src.(*GitHubIssues)
+	gi.apiURL = server.URL
+
+	opps, err := gi.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	want := []string{
+		"is:open label:enhancement is:issue repo:acme/dash reactions:>=25",
+		"is:open label:enhancement is:issue org:acme reactions:>=25",
+	}
+	if strings.Join(queries, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected one query per target, got %q", queries)
+	}
+
+	if len(opps) != 1 {
+		t.Fatalf("expected issues deduplicated across targets, got %d", len(opps))
+	}
+
+	opp := opps[0]
+	if opp.SourceType != "github_issues" || opp.SourceIDExternal != "9001" || opp.SourceURL != "https://github.com/acme/dash/issues/42" {
+		t.Errorf("unexpected source fields %+v", opp)
+	}
+	if opp.Description != "Issue #42 in acme/dash" {
+		t.Errorf("expected fallback description, got %q", opp.Description)
+	}
+	if !opp.DetectedAt.Equal(time.Date(2026, 2, 10, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected date %v", opp.DetectedAt)
+	}
+	if opp.Metadata["reactions"] != 57 || opp.Metadata["thumbs_up"] != 51 || opp.Metadata["repo"] != "acme/dash" {
+		t.Errorf("unexpected metadata %v", opp.Metadata)
+	}
+	if labels := opp.Metadata["labels"].([]string); len(labels) != 2 || labels[1] != "export" {
+		t.Errorf("unexpected labels %v", labels)
+	}
+}
+
+func TestGitHubIssues_FetchError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	src, _ := NewGitHubIssues(SourceConfig{Name: "Test"})
+	src.(*GitHubIssues).apiURL = server.URL
+
+	if _, err := src.Fetch(context.Background()); err == nil {
+		t.Error("expected error when the search fails")
+	}
+}
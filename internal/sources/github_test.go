@@ -2,6 +2,10 @@ package sources
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -101,3 +105,148 @@ func TestGitHub_FetchWithCancelledContext(t *testing.T) {
 		t.Errorf("expected no error with cancelled context, got %v", err)
 	}
 }
+
+// newTestGitHub creates a GitHub source against server that records its waits
+func newTestGitHub(t *testing.T, url string, config map[string]string) (*GitHub, *[]time.Duration) {
+	t.Helper()
+
+	src, err := NewGitHub(SourceConfig{Name: "Test", Config: config})
+	if err != nil {
+		t.Fatalf("NewGitHub() error = %v", err)
+	}
+
+	var waits []time.Duration
+	gh := src.(*GitHub)
+	gh.apiURL = url
+	gh.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return gh, &waits
+}
+
+func TestGitHub_ConfiguredQueries(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		if r.Header.Get("Authorization") != "Bearer ghp_test" {
+			t.Errorf("expected token to be sent, got %q", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`{"items": [{"id": 1, "full_name": "acme/tool", "html_url": "https://github.com/acme/tool"}]}`))
+	}))
+	defer server.Close()
+
+	gh, _ := newTestGitHub(t, server.URL, map[string]string{
+		"token":   "ghp_test",
+		"queries": "\"invoice\" stars:>5\n\n  topic:cli created:>2026-01-01  ",
+	})
+
+	opps, err := gh.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(opps) != 1 {
+		t.Errorf("expected repositories deduplicated across queries, got %d", len(opps))
+	}
+
+	if len(queries) != 2 {
+		t.Fatalf("expected only the configured queries, got %q", queries)
+	}
+	if !strings.HasPrefix(queries[0], `"invoice" stars:>5 pushed:>`) {
+		t.Errorf("expected pushed filter to be added, got %q", queries[0])
+	}
+	if queries[1] != "topic:cli created:>2026-01-01" {
+		t.Errorf("expected query with a date qualifier to be kept, got %q", queries[1])
+	}
+}
+
+func TestGitHub_PacesToRateLimit(t *testing.T) {
+	requests := 0
+	reset := time.Now().Add(30 * time.Second).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", reset))
+
+		switch requests {
+		case 1:
+			// The last request of the window succeeds
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Write([]byte(`{"items": [{"id": 1, "full_name": "acme/one"}]}`))
+		case 2:
+			// A request racing the reset is rejected and retried
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.Header().Set("X-RateLimit-Remaining", "9")
+			w.Write([]byte(fmt.Sprintf(`{"items": [{"id": %d, "full_name": "acme/repo"}]}`, requests)))
+		}
+	}))
+	defer server.Close()
+
+	gh, waits := newTestGitHub(t, server.URL, map[string]string{"queries": "one\ntwo"})
+
+	opps, err := gh.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(opps) != 2 || requests != 3 {
+		t.Errorf("expected both queries after retrying, got %d opportunities in %d requests", len(opps), requests)
+	}
+	if len(*waits) != 2 || (*waits)[0] < 25*time.Second || (*waits)[0] > ghMaxRateLimitWait {
+		t.Errorf("expected to wait for the reset twice, got %v", *waits)
+	}
+	if len(gh.Warnings()) != 0 {
+		t.Errorf("expected no warnings, got %v", gh.Warnings())
+	}
+}
+
+func TestGitHub_WarnsWhenRateLimitExhausted(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", time.Now().Add(time.Hour).Unix()))
+		w.Write([]byte(`{"items": [{"id": 1, "full_name": "acme/one"}]}`))
+	}))
+	defer server.Close()
+
+	gh, waits := newTestGitHub(t, server.URL, map[string]string{"queries": "one\ntwo\nthree"})
+
+	opps, err := gh.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(opps) != 1 || requests != 1 || len(*waits) != 0 {
+		t.Errorf("expected to stop after the limit ran out, got %d opportunities in %d requests", len(opps), requests)
+	}
+
+	warnings := gh.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "skipped 2 queries") {
+		t.Errorf("expected rate limit warning, got %v", warnings)
+	}
+}
+
+func TestGitHub_FailedQueries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") == "bad pushed:>2026-01-01" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		w.Write([]byte(`{"items": [{"id": 1, "full_name": "acme/one"}]}`))
+	}))
+	defer server.Close()
+
+	gh, _ := newTestGitHub(t, server.URL, map[string]string{"queries": "bad pushed:>2026-01-01\ngood pushed:>2026-01-01"})
+	opps, err := gh.Fetch(context.Background())
+	if err != nil || len(opps) != 1 {
+		t.Fatalf("expected partial results, got %d, %v", len(opps), err)
+	}
+	if warnings := gh.Warnings(); len(warnings) != 1 || !strings.Contains(warnings[0], "422") {
+		t.Errorf("expected failed query as warning, got %v", warnings)
+	}
+
+	gh, _ = newTestGitHub(t, server.URL, map[string]string{"queries": "bad pushed:>2026-01-01"})
+	if _, err := gh.Fetch(context.Background()); err == nil {
+		t.Error("expected error when every query fails")
+	}
+}
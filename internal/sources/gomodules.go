@@ -0,0 +1,188 @@
+package sources
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	goIndexURL  = "https://index.golang.org/index"
+	goModuleURL = "https://pkg.go.dev/"
+
+	// goIndexPageSize is the most entries the index returns per request
+	goIndexPageSize = 2000
+
+	// goIndexMaxPages bounds a fetch; the index grows by thousands of entries a day
+	goIndexMaxPages = 25
+
+	// goStateSince is the state key holding the index timestamp read up to
+	goStateSince = "since"
+)
+
+// goPseudoVersion matches pseudo-versions, which are commits rather than releases
+var goPseudoVersion = regexp.MustCompile(`\d{14}-[0-9a-f]{12}(\+incompatible)?$`)
+
+// GoModules fetches newly published Go module versions from the module index
+type GoModules struct {
+	name     string
+	indexURL string
+	queries  []string
+	since    time.Time
+	client   *http.Client
+}
+
+// goIndexEntry is a line of the module index
+type goIndexEntry struct {
+	Path      string    `json:"Path"`
+	Version   string    `json:"Version"`
+	Timestamp time.Time `json:"Timestamp"`
+}
+
+// NewGoModules creates a new Go module index source. The optional "queries" config
+// is a comma-separated list of terms a module path must contain. The first fetch
+// reads the last "days" of the index (default 1); later fetches continue where the
+// previous one stopped.
+func NewGoModules(cfg SourceConfig) (Source, error) {
+	queries, window, err := registryConfig(cfg, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().Add(-window)
+	if s, err := time.Parse(time.RFC3339Nano, cfg.State[goStateSince]); err == nil && s.After(since) {
+		since = s
+	}
+
+	return &GoModules{
+		name:     cfg.Name,
+		indexURL: goIndexURL,
+		queries:  queries,
+		since:    since,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Type returns the source type
+func (g *GoModules) Type() string {
+	return "gomodules"
+}
+
+// Name returns the source name
+func (g *GoModules) Name() string {
+	return g.name
+}
+
+// State returns the index timestamp the next fetch continues from
+func (g *GoModules) State() map[string]string {
+	return map[string]string{goStateSince: g.since.Format(time.RFC3339Nano)}
+}
+
+// Fetch reads the index since the previous fetch, keeping the latest release of
+// each module whose path matches a query
+func (g *GoModules) Fetch(ctx context.Context) ([]Opportunity, error) {
+	latest := make(map[string]goIndexEntry)
+	var order []string
+
+	for page := 0; page < goIndexMaxPages; page++ {
+		entries, err := g.readIndex(ctx, g.since)
+		if err != nil {
+			if page == 0 {
+				return nil, err
+			}
+			break // Keep what was read; the next fetch continues from there
+		}
+
+		for _, entry := range entries {
+			if entry.Timestamp.After(g.since) {
+				g.since = entry.Timestamp
+			}
+			if goPseudoVersion.MatchString(entry.Version) || !containsAnyTerm(entry.Path, g.queries) {
+				continue
+			}
+			if _, ok := latest[entry.Path]; !ok {
+				order = append(order, entry.Path)
+			}
+			latest[entry.Path] = entry
+		}
+
+		if len(entries) < goIndexPageSize {
+			break
+		}
+	}
+
+	opportunities := make([]Opportunity, 0, len(order))
+	for _, path := range order {
+		opportunities = append(opportunities, g.moduleToOpportunity(latest[path]))
+	}
+
+	return opportunities, nil
+}
+
+func (g *GoModules) readIndex(ctx context.Context, since time.Time) ([]goIndexEntry, error) {
+	params := url.Values{}
+	params.Set("since", since.UTC().Format(time.RFC3339Nano))
+	params.Set("limit", fmt.Sprintf("%d", goIndexPageSize))
+
+	reqURL := g.indexURL + "?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "Seer/1.0")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	// The index is newline-delimited JSON
+	var entries []goIndexEntry
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry goIndexEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("failed to decode index entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (g *GoModules) moduleToOpportunity(entry goIndexEntry) Opportunity {
+	host, _, _ := strings.Cut(entry.Path, "/")
+
+	return Opportunity{
+		Title:            entry.Path,
+		Description:      fmt.Sprintf("Go module: %s %s", entry.Path, entry.Version),
+		SourceType:       "gomodules",
+		SourceURL:        goModuleURL + entry.Path,
+		SourceIDExternal: entry.Path + "@" + entry.Version,
+		DetectedAt:       entry.Timestamp,
+		Metadata: map[string]any{
+			"version": entry.Version,
+			"host":    host,
+		},
+	}
+}
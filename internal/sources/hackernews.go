@@ -189,6 +189,7 @@ func (h *HackerNews) hitToOpportunity(hit hnHit) Opportunity {
 			"points":       hit.Points,
 			"num_comments": hit.NumComments,
 			"hn_url":       hnBaseURL + hit.ObjectID,
+			"url":          hit.URL,
 		},
 	}
 }
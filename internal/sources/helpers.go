@@ -1,6 +1,10 @@
 package sources
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 func containsAnyKeyword(text string, keywords []string) bool {
 	lowerText := strings.ToLower(text)
@@ -21,3 +25,31 @@ func truncate(s string, maxLen int) string {
 	}
 	return s[:maxLen-3] + "..."
 }
+
+// scopeExternalID prefixes an external ID chosen by a source's sender with the
+// source's ID, so two sources of the same type never overwrite each other's rows
+func scopeExternalID(sourceID int64, id string) string {
+	return fmt.Sprintf("%d:%s", sourceID, id)
+}
+
+// preparePushed checks an opportunity that was handed to Seer rather than fetched,
+// such as an exec source's output line or a webhook item, and fills in what its
+// sender may leave out
+func preparePushed(opp *Opportunity, sourceType string) error {
+	if strings.TrimSpace(opp.Title) == "" {
+		return fmt.Errorf("missing title")
+	}
+
+	// Opportunities are stored by source type and external ID
+	opp.SourceType = sourceType
+	if opp.SourceIDExternal == "" {
+		opp.SourceIDExternal = opp.SourceURL
+	}
+	if opp.SourceIDExternal == "" {
+		return fmt.Errorf("missing source_id_external and source_url")
+	}
+	if opp.DetectedAt.IsZero() {
+		opp.DetectedAt = time.Now()
+	}
+	return nil
+}
@@ -3,6 +3,15 @@ package sources
 import (
 	"database/sql"
 	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Limits applied when validating a keywords configuration
+const (
+	maxKeywordsPerList = 100
+	maxKeywordLength   = 100
+	maxKeywordBoost    = 20
 )
 
 // KeywordsConfig stores custom keywords configuration for Pro users
@@ -103,9 +112,81 @@ func CalculateKeywordBoost(opp Opportunity, config *KeywordsConfig) int {
 	}
 
 	// Cap boost at 20 points
-	if boost > 20 {
-		boost = 20
+	if boost > maxKeywordBoost {
+		boost = maxKeywordBoost
 	}
 
 	return boost
 }
+
+// Normalize trims and de-duplicates keywords and validates the configuration
+func (c *KeywordsConfig) Normalize() error {
+	lists := []struct {
+		name     string
+		keywords *[]string
+	}{
+		{"include_keywords", &c.IncludeKeywords},
+		{"exclude_keywords", &c.ExcludeKeywords},
+		{"boost_keywords", &c.BoostKeywords},
+	}
+
+	for _, l := range lists {
+		seen := make(map[string]bool)
+		cleaned := []string{}
+		for _, kw := range *l.keywords {
+			kw = strings.TrimSpace(kw)
+			if kw == "" || seen[strings.ToLower(kw)] {
+				continue
+			}
+			if len(kw) > maxKeywordLength {
+				return fmt.Errorf("%s: keyword %q exceeds %d characters", l.name, truncate(kw, 20), maxKeywordLength)
+			}
+			seen[strings.ToLower(kw)] = true
+			cleaned = append(cleaned, kw)
+		}
+		if len(cleaned) > maxKeywordsPerList {
+			return fmt.Errorf("%s: at most %d keywords allowed", l.name, maxKeywordsPerList)
+		}
+		*l.keywords = cleaned
+	}
+
+	// A keyword cannot both require and reject an opportunity
+	excluded := make(map[string]bool)
+	for _, kw := range c.ExcludeKeywords {
+		excluded[strings.ToLower(kw)] = true
+	}
+	for _, kw := range c.IncludeKeywords {
+		if excluded[strings.ToLower(kw)] {
+			return fmt.Errorf("keyword %q cannot be both included and excluded", kw)
+		}
+	}
+
+	return nil
+}
+
+// KeywordsPreview summarizes the effect of a keywords configuration on a set of opportunities
+type KeywordsPreview struct {
+	Total   int `json:"total"`
+	Kept    int `json:"kept"`
+	Dropped int `json:"dropped"`
+	Boosted int `json:"boosted"`
+}
+
+// PreviewKeywords counts how many opportunities a configuration would keep, drop or boost
+func PreviewKeywords(opps []Opportunity, config *KeywordsConfig) KeywordsPreview {
+	kept := FilterOpportunities(opps, config)
+
+	preview := KeywordsPreview{
+		Total:   len(opps),
+		Kept:    len(kept),
+		Dropped: len(opps) - len(kept),
+	}
+
+	for _, opp := range kept {
+		if CalculateKeywordBoost(opp, config) > 0 {
+			preview.Boosted++
+		}
+	}
+
+	return preview
+}
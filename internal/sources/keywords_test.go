@@ -0,0 +1,112 @@
+package sources
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mx-seer/seer/internal/scoring"
+)
+
+func TestKeywordsConfig_Normalize(t *testing.T) {
+	config := KeywordsConfig{
+		IncludeKeywords: []string{" saas ", "SaaS", "", "invoicing"},
+		BoostKeywords:   []string{"self-hosted"},
+	}
+
+	if err := config.Normalize(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(config.IncludeKeywords) != 2 || config.IncludeKeywords[0] != "saas" {
+		t.Errorf("expected trimmed, de-duplicated include keywords, got %v", config.IncludeKeywords)
+	}
+	if config.ExcludeKeywords == nil {
+		t.Error("expected empty exclude keywords to be normalized to an empty slice")
+	}
+}
+
+func TestKeywordsConfig_NormalizeConflict(t *testing.T) {
+	config := KeywordsConfig{
+		IncludeKeywords: []string{"crypto"},
+		ExcludeKeywords: []string{"Crypto"},
+	}
+
+	if err := config.Normalize(); err == nil {
+		t.Error("expected error for keyword both included and excluded")
+	}
+}
+
+func TestPreviewKeywords(t *testing.T) {
+	opps := []Opportunity{
+		{Title: "Self-hosted invoicing for freelancers"},
+		{Title: "Invoicing API for SaaS"},
+		{Title: "Crypto trading bot"},
+	}
+	config := &KeywordsConfig{
+		ExcludeKeywords: []string{"crypto"},
+		BoostKeywords:   []string{"self-hosted"},
+	}
+
+	preview := PreviewKeywords(opps, config)
+
+	if preview.Total != 3 || preview.Kept != 2 || preview.Dropped != 1 || preview.Boosted != 1 {
+		t.Errorf("unexpected preview: %+v", preview)
+	}
+}
+
+type stubSource struct {
+	opportunities []Opportunity
+}
+
+func (s *stubSource) Type() string { return "stub" }
+func (s *stubSource) Name() string { return "Stub" }
+func (s *stubSource) Fetch(ctx context.Context) ([]Opportunity, error) {
+	return s.opportunities, nil
+}
+
+func TestManager_FetchSourceAppliesKeywords(t *testing.T) {
+	db := setupTestDB(t)
+	m := NewManager(db, 60)
+
+	stub := &stubSource{opportunities: []Opportunity{
+		{Title: "Self-hosted analytics", SourceType: "stub", SourceURL: "https://example.com/1", SourceIDExternal: "1"},
+		{Title: "Crypto exchange launch", SourceType: "stub", SourceURL: "https://example.com/2", SourceIDExternal: "2"},
+	}}
+	m.RegisterFactory("stub", func(cfg SourceConfig) (Source, error) { return stub, nil })
+
+	record := &SourceRecord{Type: "stub", Name: "Stub", Enabled: true, Config: "{}"}
+	if err := m.repo.Create(record); err != nil {
+		t.Fatalf("failed to create source: %v", err)
+	}
+
+	if err := m.keywords.SaveKeywords(&KeywordsConfig{
+		ExcludeKeywords: []string{"crypto"},
+		BoostKeywords:   []string{"self-hosted", "analytics"},
+	}); err != nil {
+		t.Fatalf("failed to save keywords: %v", err)
+	}
+
+	if err := m.fetchSource(context.Background(), *record); err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+
+	var count int
+	db.QueryRow("SELECT COUNT(*) FROM opportunities").Scan(&count)
+	if count != 1 {
+		t.Fatalf("expected excluded opportunity to be dropped, got %d saved", count)
+	}
+
+	var score, boost int
+	var signals string
+	db.QueryRow("SELECT score, signals, keyword_boost FROM opportunities WHERE source_id_external = '1'").Scan(&score, &signals, &boost)
+
+	unboosted := m.scorer.Score(scoring.Opportunity{Title: "Self-hosted analytics"}).Score
+	if score != unboosted+10 || boost != 10 {
+		t.Errorf("expected score %d with a boost of 10, got %d with %d", unboosted+10, score, boost)
+	}
+	// Signals only list what the scorer matched
+	if strings.Contains(signals, "keyword_boost") {
+		t.Errorf("expected the boost kept out of signals, got %s", signals)
+	}
+}
@@ -0,0 +1,183 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const lobstersBaseURL = "https://lobste.rs"
+
+// Lobsters fetches stories from Lobste.rs, or another site running its software
+type Lobsters struct {
+	name    string
+	baseURL string
+	tags    []string
+	client  *http.Client
+}
+
+// lobstersStory represents a story in the Lobsters JSON API
+type lobstersStory struct {
+	ShortID          string   `json:"short_id"`
+	Title            string   `json:"title"`
+	URL              string   `json:"url"`
+	CreatedAt        string   `json:"created_at"`
+	Score            int      `json:"score"`
+	CommentCount     int      `json:"comment_count"`
+	DescriptionPlain string   `json:"description_plain"`
+	CommentsURL      string   `json:"comments_url"`
+	SubmitterUser    any      `json:"submitter_user"`
+	Tags             []string `json:"tags"`
+}
+
+// NewLobsters creates a new Lobsters source. The optional "tags" config is a
+// comma-separated list of tags to follow; "newest" follows all new stories. The
+// source URL overrides the site, for other sites running the Lobsters software.
+func NewLobsters(cfg SourceConfig) (Source, error) {
+	tags := []string{"ask", "show", "practices", "devops", "web"}
+	if t, ok := cfg.Config["tags"]; ok && t != "" {
+		tags = parseCSV(t)
+	}
+
+	baseURL := lobstersBaseURL
+	if cfg.URL != "" {
+		baseURL = strings.TrimRight(cfg.URL, "/")
+	}
+
+	return &Lobsters{
+		name:    cfg.Name,
+		baseURL: baseURL,
+		tags:    tags,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Type returns the source type
+func (l *Lobsters) Type() string {
+	return "lobsters"
+}
+
+// Name returns the source name
+func (l *Lobsters) Name() string {
+	return l.name
+}
+
+// Fetch retrieves the latest stories of the configured tags
+func (l *Lobsters) Fetch(ctx context.Context) ([]Opportunity, error) {
+	seen := make(map[string]bool)
+	var opportunities []Opportunity
+	var lastErr error
+
+	for _, tag := range l.tags {
+		stories, err := l.fetchStories(ctx, tag)
+		if err != nil {
+			lastErr = err
+			continue // Skip failed tags
+		}
+
+		for _, story := range stories {
+			if seen[story.ShortID] {
+				continue
+			}
+			seen[story.ShortID] = true
+
+			opportunities = append(opportunities, l.storyToOpportunity(story))
+		}
+	}
+
+	// Only fail when no tag could be fetched at all
+	if len(opportunities) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	return opportunities, nil
+}
+
+func (l *Lobsters) fetchStories(ctx context.Context, tag string) ([]lobstersStory, error) {
+	reqURL := l.baseURL + "/newest.json"
+	if tag != "newest" {
+		reqURL = l.baseURL + "/t/" + url.PathEscape(tag) + ".json"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "Seer/1.0")
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status for tag %s: %d", tag, resp.StatusCode)
+	}
+
+	var stories []lobstersStory
+	if err := json.NewDecoder(resp.Body).Decode(&stories); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return stories, nil
+}
+
+func (l *Lobsters) storyToOpportunity(story lobstersStory) Opportunity {
+	// Like Hacker News, link to the discussion rather than the submitted URL
+	sourceURL := story.CommentsURL
+	if sourceURL == "" {
+		sourceURL = l.baseURL + "/s/" + story.ShortID
+	}
+
+	description := story.DescriptionPlain
+	if description == "" && story.URL != "" {
+		description = story.URL
+	}
+
+	createdAt, _ := time.Parse(time.RFC3339, story.CreatedAt)
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	tags := story.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return Opportunity{
+		Title:            story.Title,
+		Description:      truncate(description, 1000),
+		SourceType:       "lobsters",
+		SourceURL:        sourceURL,
+		SourceIDExternal: story.ShortID,
+		DetectedAt:       createdAt,
+		Metadata: map[string]any{
+			"author": lobstersSubmitter(story.SubmitterUser),
+			// Score and comment count use the keys the scorer reads engagement from
+			"points":       story.Score,
+			"num_comments": story.CommentCount,
+			"tags":         tags,
+			"url":          story.URL,
+		},
+	}
+}
+
+// lobstersSubmitter returns the submitter's username, which older versions of the
+// API return as a user object instead of a string
+func lobstersSubmitter(user any) string {
+	switch u := user.(type) {
+	case string:
+		return u
+	case map[string]any:
+		if name, ok := u["username"].(string); ok {
+			return name
+		}
+	}
+	return ""
+}
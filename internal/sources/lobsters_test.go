@@ -0,0 +1,102 @@
+package sources
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLobsters_Type(t *testing.T) {
+	l, _ := NewLobsters(SourceConfig{Name: "Test Lobsters"})
+	if l.Type() != "lobsters" {
+		t.Errorf("expected type lobsters, got %s", l.Type())
+	}
+	if l.Name() != "Test Lobsters" {
+		t.Errorf("expected name 'Test Lobsters', got %s", l.Name())
+	}
+}
+
+func TestLobsters_Fetch(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/t/ask.json":
+			w.Write([]byte(`[
+				{"short_id": "abc123", "title": "Ask: What do you use for on-call scheduling?", "url": "", "created_at": "2026-03-02T09:30:00.000-06:00", "score": 64, "comment_count": 31, "description_plain": "PagerDuty is too expensive for us.", "comments_url": "https://lobste.rs/s/abc123/ask_on_call", "submitter_user": "jane", "tags": ["ask", "devops"]},
+				{"short_id": "def456", "title": "Show: a tiny feature flag server", "url": "https://flags.example.com", "created_at": "2026-03-02T08:00:00.000-06:00", "score": 12, "comment_count": 2, "submitter_user": {"username": "sam"}, "tags": ["show"]}
+			]`))
+		case "/t/devops.json":
+			w.Write([]byte(`[
+				{"short_id": "abc123", "title": "Ask: What do you use for on-call scheduling?", "created_at": "2026-03-02T09:30:00.000-06:00", "score": 64, "comment_count": 31, "tags": ["ask", "devops"]}
+			]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	src, _ := NewLobsters(SourceConfig{Name: "Test", URL: server.URL + "/", Config: map[string]string{"tags": "ask, devops, missing"}})
+	opps, err := src.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	if len(paths) != 3 {
+		t.Errorf("expected one request per tag, got %v", paths)
+	}
+	if len(opps) != 2 {
+		t.Fatalf("expected stories deduplicated across tags, got %d", len(opps))
+	}
+
+	opp := opps[0]
+	if opp.SourceType != "lobsters" || opp.SourceIDExternal != "abc123" {
+		t.Errorf("unexpected source %s/%s", opp.SourceType, opp.SourceIDExternal)
+	}
+	if opp.SourceURL != "https://lobste.rs/s/abc123/ask_on_call" || opp.Description != "PagerDuty is too expensive for us." {
+		t.Errorf("expected discussion URL and plain description, got %s %q", opp.SourceURL, opp.Description)
+	}
+	if !opp.DetectedAt.Equal(time.Date(2026, 3, 2, 15, 30, 0, 0, time.UTC)) {
+		t.Errorf("unexpected date %v", opp.DetectedAt)
+	}
+	if opp.Metadata["points"] != 64 || opp.Metadata["num_comments"] != 31 || opp.Metadata["author"] != "jane" {
+		t.Errorf("unexpected metadata %v", opp.Metadata)
+	}
+	if tags := opp.Metadata["tags"].([]string); len(tags) != 2 || tags[1] != "devops" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+
+	// Link stories fall back to the submitted URL and a built discussion URL
+	if opps[1].Description != "https://flags.example.com" || opps[1].SourceURL != server.URL+"/s/def456" || opps[1].Metadata["author"] != "sam" {
+		t.Errorf("unexpected link story %+v", opps[1])
+	}
+}
+
+func TestLobsters_FetchNewest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/newest.json" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	src, _ := NewLobsters(SourceConfig{Name: "Test", URL: server.URL, Config: map[string]string{"tags": "newest"}})
+	if _, err := src.Fetch(context.Background()); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+}
+
+func TestLobsters_FetchError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	src, _ := NewLobsters(SourceConfig{Name: "Test", URL: server.URL})
+	if _, err := src.Fetch(context.Background()); err == nil {
+		t.Error("expected error when no tag can be fetched")
+	}
+}
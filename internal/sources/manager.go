@@ -9,7 +9,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/mx-seer/seer/internal/cluster"
 	"github.com/mx-seer/seer/internal/scoring"
+	"github.com/mx-seer/seer/internal/topics"
 	"github.com/robfig/cron/v3"
 )
 
@@ -17,9 +19,15 @@ import (
 type Manager struct {
 	db            *sql.DB
 	repo          *Repository
+	keywords      *KeywordsRepository
+	watchlists    *WatchlistRepository
+	clusters      *cluster.Store
+	topics        *topics.Store
+	extractor     *topics.Extractor
 	cron          *cron.Cron
 	factories     map[string]SourceFactory
 	scorer        *scoring.Scorer
+	models        *scoring.ModelStore
 	mu            sync.RWMutex
 	isRunning     bool
 	fetchInterval int // Interval in minutes between fetches
@@ -34,9 +42,14 @@ func NewManager(db *sql.DB, fetchIntervalMinutes int) *Manager {
 	m := &Manager{
 		db:            db,
 		repo:          NewRepository(db),
+		keywords:      NewKeywordsRepository(db),
+		watchlists:    NewWatchlistRepository(db),
+		clusters:      cluster.NewStore(db),
+		topics:        topics.NewStore(db),
 		cron:          cron.New(),
 		factories:     make(map[string]SourceFactory),
 		scorer:        scoring.New(),
+		models:        scoring.NewModelStore(db),
 		fetchInterval: fetchIntervalMinutes,
 	}
 
@@ -45,6 +58,17 @@ func NewManager(db *sql.DB, fetchIntervalMinutes int) *Manager {
 	m.RegisterFactory("github", NewGitHub)
 	m.RegisterFactory("npm", NewNPM)
 	m.RegisterFactory("devto", NewDevTo)
+	m.RegisterFactory("feed", NewFeed)
+	m.RegisterFactory("lobsters", NewLobsters)
+	m.RegisterFactory("stackexchange", NewStackExchange)
+	m.RegisterFactory("github_issues", NewGitHubIssues)
+	m.RegisterFactory("pypi", NewPyPI)
+	m.RegisterFactory("crates", NewCrates)
+	m.RegisterFactory("gomodules", NewGoModules)
+	m.RegisterFactory("mastodon", NewMastodon)
+	m.RegisterFactory("bluesky", NewBluesky)
+	m.RegisterFactory("discourse", NewDiscourse)
+	m.RegisterFactory("exec", newDisabledExec) // Replaced with NewExec when allowed
 
 	return m
 }
@@ -82,6 +106,11 @@ func (m *Manager) Start() error {
 		return fmt.Errorf("failed to schedule fetch job: %w", err)
 	}
 
+	// Refresh topic document frequencies and re-tag recent opportunities with them
+	if _, err := m.cron.AddFunc(topicRefreshSchedule, m.refreshTopics); err != nil {
+		return fmt.Errorf("failed to schedule topic refresh: %w", err)
+	}
+
 	m.cron.Start()
 	m.isRunning = true
 
@@ -97,6 +126,11 @@ func (m *Manager) Start() error {
 	return nil
 }
 
+// Cron returns the cron that schedules fetches, which other background jobs share
+func (m *Manager) Cron() *cron.Cron {
+	return m.cron
+}
+
 // Stop stops the scheduler
 func (m *Manager) Stop() {
 	m.mu.Lock()
@@ -120,10 +154,30 @@ func (m *Manager) FetchAll(ctx context.Context) error {
 		return fmt.Errorf("failed to get enabled sources: %w", err)
 	}
 
+	// Pick up a newly promoted (or reset) scoring model before scoring this batch
+	model, err := m.models.Active()
+	if err != nil {
+		log.Printf("Failed to load scoring model, using default weights: %v", err)
+	}
+	m.scorer.SetModel(model)
+
+	// Topics are refreshed on their own schedule; the first fetch builds the extractor
+	m.mu.RLock()
+	ready := m.extractor != nil
+	m.mu.RUnlock()
+	if !ready {
+		m.refreshTopics()
+	}
+
 	var wg sync.WaitGroup
 	errChan := make(chan error, len(sources))
 
 	for _, src := range sources {
+		// Webhook sources receive their opportunities through Ingest
+		if src.Type == WebhookType {
+			continue
+		}
+
 		wg.Add(1)
 		go func(s SourceRecord) {
 			defer wg.Done()
@@ -152,8 +206,28 @@ func (m *Manager) FetchAll(ctx context.Context) error {
 	return nil
 }
 
-// fetchSource fetches opportunities from a single source
+// fetchSource fetches opportunities from a single source and records the run
 func (m *Manager) fetchSource(ctx context.Context, record SourceRecord) error {
+	run := &FetchRun{SourceID: record.ID, StartedAt: time.Now()}
+	err := m.runSource(ctx, record, run)
+	run.FinishedAt = time.Now()
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	for _, warning := range run.Warnings {
+		log.Printf("Warning from %s: %s", record.Name, warning)
+	}
+	if recordErr := m.repo.RecordRun(run); recordErr != nil {
+		log.Printf("Failed to record fetch run of %s: %v", record.Name, recordErr)
+	}
+
+	return err
+}
+
+// runSource fetches, filters and saves the opportunities of a source, filling in
+// the counts and warnings of its run
+func (m *Manager) runSource(ctx context.Context, record SourceRecord, run *FetchRun) error {
 	m.mu.RLock()
 	factory, ok := m.factories[record.Type]
 	m.mu.RUnlock()
@@ -163,29 +237,148 @@ func (m *Manager) fetchSource(ctx context.Context, record SourceRecord) error {
 	}
 
 	cfg := record.ToConfig()
+	state, err := m.repo.GetState(record.ID)
+	if err != nil {
+		return err
+	}
+	cfg.State = state
+
 	source, err := factory(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create source: %w", err)
 	}
 
 	opportunities, err := source.Fetch(ctx)
+	if reporter, ok := source.(WarningSource); ok {
+		run.Warnings = reporter.Warnings()
+	}
 	if err != nil {
 		return fmt.Errorf("failed to fetch: %w", err)
 	}
 
+	if stateful, ok := source.(StatefulSource); ok {
+		if err := m.repo.SetState(record.ID, stateful.State()); err != nil {
+			log.Printf("Failed to save state of %s: %v", record.Name, err)
+		}
+	}
+
+	m.store(record, opportunities, run)
+
+	log.Printf("Fetched %d opportunities from %s (%d dropped by keyword filters)", len(opportunities), record.Name, len(opportunities)-run.Kept)
+	return nil
+}
+
+// store filters, scores and saves the opportunities of a source, counting them in its run
+func (m *Manager) store(record SourceRecord, opportunities []Opportunity, run *FetchRun) {
+	// Apply custom include/exclude keywords before anything is stored
+	rules := m.loadRules()
+	kept := FilterOpportunities(opportunities, rules.keywords)
+	run.Fetched += len(opportunities)
+	run.Kept += len(kept)
+
 	// Save opportunities to database
-	for _, opp := range opportunities {
-		if err := m.saveOpportunity(record.ID, opp); err != nil {
+	for _, opp := range kept {
+		if err := m.saveOpportunity(record.ID, opp, rules); err != nil {
 			log.Printf("Failed to save opportunity %s: %v", opp.Title, err)
 		}
 	}
+}
+
+// Ingest stores opportunities delivered to a webhook source the way fetched ones
+// are stored, recording the delivery as a fetch run. Items that are not valid
+// opportunities are skipped and reported in the run's warnings.
+func (m *Manager) Ingest(record SourceRecord, opportunities []Opportunity) (*FetchRun, error) {
+	run := &FetchRun{SourceID: record.ID, StartedAt: time.Now(), Warnings: []string{}}
+
+	valid := make([]Opportunity, 0, len(opportunities))
+	for i, opp := range opportunities {
+		if err := preparePushed(&opp, WebhookType); err != nil {
+			run.Warnings = append(run.Warnings, fmt.Sprintf("item %d: %v", i+1, err))
+			continue
+		}
+		opp.SourceIDExternal = scopeExternalID(record.ID, opp.SourceIDExternal)
+		valid = append(valid, opp)
+	}
 
-	log.Printf("Fetched %d opportunities from %s", len(opportunities), record.Name)
-	return nil
+	m.store(record, valid, run)
+	run.FinishedAt = time.Now()
+
+	if err := m.repo.RecordRun(run); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Received %d opportunities for %s (%d dropped by keyword filters)", run.Fetched, record.Name, run.Fetched-run.Kept)
+	return run, nil
 }
 
-// saveOpportunity saves an opportunity to the database
-func (m *Manager) saveOpportunity(sourceID int64, opp Opportunity) error {
+const (
+	// topicRetagWindow is how far back topics are re-extracted when the corpus is
+	// refreshed, covering both periods compared by the default weekly trends
+	topicRetagWindow = 14 * 24 * time.Hour
+
+	// topicRefreshSchedule is how often the corpus is refreshed. Document frequencies
+	// drift slowly, so opportunities saved in between are tagged with the last ones.
+	topicRefreshSchedule = "@every 6h"
+)
+
+// refreshTopics rebuilds the topic extractor from stored opportunities and re-tags
+// recent and untagged ones
+func (m *Manager) refreshTopics() {
+	extractor, err := m.topics.Extractor()
+	if err != nil {
+		log.Printf("Failed to build topic extractor: %v", err)
+		return
+	}
+
+	tagged, err := m.topics.Retag(extractor, time.Now().Add(-topicRetagWindow))
+	if err != nil {
+		log.Printf("Failed to re-tag topics: %v", err)
+	} else if tagged > 0 {
+		log.Printf("Tagged topics for %d opportunities", tagged)
+	}
+
+	m.mu.Lock()
+	m.extractor = extractor
+	m.mu.Unlock()
+}
+
+// ingestRules holds the keyword configuration applied to a fetched batch
+type ingestRules struct {
+	keywords   *KeywordsConfig
+	watchlists []Watchlist
+	extractor  *topics.Extractor
+}
+
+// loadRules reads the global keywords and watchlists, falling back to no rules on error
+func (m *Manager) loadRules() *ingestRules {
+	m.mu.RLock()
+	rules := &ingestRules{extractor: m.extractor}
+	m.mu.RUnlock()
+
+	keywords, err := m.keywords.GetKeywords()
+	if err != nil {
+		log.Printf("Failed to load custom keywords, saving unfiltered: %v", err)
+	} else {
+		rules.keywords = keywords
+	}
+
+	watchlists, err := m.watchlists.GetAll()
+	if err != nil {
+		log.Printf("Failed to load watchlists, skipping tagging: %v", err)
+	} else {
+		rules.watchlists = watchlists
+	}
+
+	return rules
+}
+
+// saveOpportunity scores an opportunity, applies keyword boosts, saves it to
+// the database and tags it with the watchlists and topics it matches
+func (m *Manager) saveOpportunity(sourceID int64, opp Opportunity, rules *ingestRules) error {
+	if rules == nil {
+		rules = &ingestRules{}
+	}
+
 	// Convert to scoring.Opportunity for scoring
 	scoringOpp := scoring.Opportunity{
 		Title:       opp.Title,
@@ -204,30 +397,86 @@ func (m *Manager) saveOpportunity(sourceID int64, opp Opportunity) error {
 	for i, sig := range matchedSignals {
 		signalNames[i] = sig.Name
 	}
+
+	// Boost keywords (global and from matching watchlists) add points on top of the scorer result
+	matchedWatchlists := MatchWatchlists(opp, rules.watchlists)
+	boost := CalculateKeywordBoost(opp, rules.keywords)
+	for _, w := range matchedWatchlists {
+		boost += CalculateKeywordBoost(opp, &w.KeywordsConfig)
+	}
+	if boost > maxKeywordBoost {
+		boost = maxKeywordBoost
+	}
+
+	score := result.Score
+	if boost > 0 {
+		score += boost
+		if score > 100 {
+			score = 100
+		}
+	}
 	signalsJSON, _ := json.Marshal(signalNames)
 
+	metadataJSON, err := json.Marshal(opp.Metadata)
+	if err != nil || opp.Metadata == nil {
+		metadataJSON = []byte("{}")
+	}
+
 	// Format detected_at for SQLite compatibility (RFC3339 format)
 	detectedAt := opp.DetectedAt.UTC().Format(time.RFC3339)
 
-	_, err := m.db.Exec(`
-		INSERT INTO opportunities (source_id, title, description, source, source_url, source_id_external, score, signals, detected_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	var id int64
+	err = m.db.QueryRow(`
+		INSERT INTO opportunities (source_id, title, description, source, source_url, source_id_external, score, signals, keyword_boost, metadata, detected_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(source, source_id_external) DO UPDATE SET
 			title = excluded.title,
 			description = excluded.description,
 			source_url = excluded.source_url,
 			score = excluded.score,
 			signals = excluded.signals,
+			keyword_boost = excluded.keyword_boost,
+			metadata = excluded.metadata,
 			detected_at = excluded.detected_at
-	`, sourceID, opp.Title, opp.Description, opp.SourceType, opp.SourceURL, opp.SourceIDExternal, result.Score, string(signalsJSON), detectedAt)
+		RETURNING id
+	`, sourceID, opp.Title, opp.Description, opp.SourceType, opp.SourceURL, opp.SourceIDExternal, score, string(signalsJSON), boost, string(metadataJSON), detectedAt).Scan(&id)
 
 	if err != nil {
 		return fmt.Errorf("failed to insert opportunity: %w", err)
 	}
 
+	if err := m.watchlists.Tag(id, matchedWatchlists); err != nil {
+		return err
+	}
+
+	// Group with the same launch seen on other sources
+	if _, err := m.clusters.Assign(id, opp.Title, opportunityURLs(opp)); err != nil {
+		return fmt.Errorf("failed to cluster opportunity: %w", err)
+	}
+
+	if rules.extractor != nil {
+		if err := m.topics.Tag(id, rules.extractor.Extract(opp.Title, opp.Description)); err != nil {
+			return fmt.Errorf("failed to tag topics: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// linkMetadataKeys are metadata fields that may point at the project behind an opportunity
+var linkMetadataKeys = []string{"url", "html_url", "homepage", "repository"}
+
+// opportunityURLs returns every URL that may identify the project behind an opportunity
+func opportunityURLs(opp Opportunity) []string {
+	urls := []string{opp.SourceURL}
+	for _, key := range linkMetadataKeys {
+		if u, ok := opp.Metadata[key].(string); ok && u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
 // GetRepository returns the source repository
 func (m *Manager) GetRepository() *Repository {
 	return m.repo
@@ -6,7 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
-	_ "modernc.org/sqlite"
+	"github.com/mx-seer/seer/internal/db"
 )
 
 func setupTestDB(t *testing.T) *sql.DB {
@@ -15,51 +15,17 @@ func setupTestDB(t *testing.T) *sql.DB {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
 
-	db, err := sql.Open("sqlite", dbPath+"?_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)")
+	// Use the real migrations so the schema stays in sync with production
+	database, err := db.New(dbPath)
 	if err != nil {
 		t.Fatalf("failed to open database: %v", err)
 	}
 
-	// Create tables
-	migrations := []string{
-		`CREATE TABLE IF NOT EXISTS sources (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			type TEXT NOT NULL,
-			name TEXT NOT NULL,
-			url TEXT,
-			config TEXT DEFAULT '{}',
-			enabled BOOLEAN DEFAULT true,
-			is_builtin BOOLEAN DEFAULT false,
-			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
-		)`,
-		`CREATE TABLE IF NOT EXISTS opportunities (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			source_id INTEGER REFERENCES sources(id),
-			title TEXT NOT NULL,
-			description TEXT,
-			source TEXT NOT NULL,
-			source_url TEXT NOT NULL,
-			source_id_external TEXT NOT NULL,
-			score INTEGER DEFAULT 0,
-			signals TEXT DEFAULT '[]',
-			ai_analysis TEXT,
-			detected_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-			UNIQUE(source, source_id_external)
-		)`,
-	}
-
-	for _, m := range migrations {
-		if _, err := db.Exec(m); err != nil {
-			t.Fatalf("failed to run migration: %v", err)
-		}
-	}
-
 	t.Cleanup(func() {
-		db.Close()
+		database.Close()
 	})
 
-	return db
+	return database.DB
 }
 
 func TestManager_RegisterFactory(t *testing.T) {
@@ -186,7 +152,7 @@ func TestManager_SaveOpportunity(t *testing.T) {
 		SourceIDExternal: "test-123",
 	}
 
-	if err := m.saveOpportunity(sources[0].ID, opp); err != nil {
+	if err := m.saveOpportunity(sources[0].ID, opp, nil); err != nil {
 		t.Fatalf("failed to save opportunity: %v", err)
 	}
 
@@ -212,3 +178,35 @@ func TestManager_FetchAll_EmptySources(t *testing.T) {
 		t.Errorf("expected no error with empty sources, got %v", err)
 	}
 }
+
+func TestManager_FetchAll_RefreshesTopicsOnce(t *testing.T) {
+	db := setupTestDB(t)
+	m := NewManager(db, 60)
+
+	if _, err := db.Exec(`
+		INSERT INTO opportunities (title, source, source_url, source_id_external, detected_at)
+		VALUES ('Self-hosted invoicing for freelancers', 'hackernews', '', '1', CURRENT_TIMESTAMP)
+	`); err != nil {
+		t.Fatalf("failed to insert opportunity: %v", err)
+	}
+
+	// The first fetch builds the extractor and tags the corpus
+	if err := m.FetchAll(context.Background()); err != nil {
+		t.Fatalf("FetchAll() error = %v", err)
+	}
+	var taggedAt sql.NullString
+	db.QueryRow(`SELECT topics_at FROM opportunities WHERE id = 1`).Scan(&taggedAt)
+	if !taggedAt.Valid || m.extractor == nil {
+		t.Fatal("expected the first fetch to tag topics")
+	}
+
+	// Later fetches leave re-tagging to the scheduled refresh
+	db.Exec(`UPDATE opportunities SET topics_at = '2020-01-01 00:00:00' WHERE id = 1`)
+	if err := m.FetchAll(context.Background()); err != nil {
+		t.Fatalf("FetchAll() error = %v", err)
+	}
+	db.QueryRow(`SELECT datetime(topics_at) FROM opportunities WHERE id = 1`).Scan(&taggedAt)
+	if taggedAt.String != "2020-01-01 00:00:00" {
+		t.Errorf("expected no re-tagging on a later fetch, got %q", taggedAt.String)
+	}
+}
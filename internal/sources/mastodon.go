@@ -0,0 +1,213 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const mastodonDefaultInstance = "https://mastodon.social"
+
+// Mastodon fetches posts from a Mastodon instance's hashtag timelines and search
+type Mastodon struct {
+	name        string
+	instance    string
+	hashtags    []string
+	keywords    []string
+	accessToken string
+	client      *http.Client
+}
+
+// mastodonStatus represents a status of the Mastodon API
+type mastodonStatus struct {
+	ID              string    `json:"id"`
+	URI             string    `json:"uri"`
+	URL             string    `json:"url"`
+	CreatedAt       time.Time `json:"created_at"`
+	Content         string    `json:"content"`
+	SpoilerText     string    `json:"spoiler_text"`
+	Language        string    `json:"language"`
+	ReblogsCount    int       `json:"reblogs_count"`
+	FavouritesCount int       `json:"favourites_count"`
+	RepliesCount    int       `json:"replies_count"`
+	Reblog          *struct{} `json:"reblog"`
+	Account         struct {
+		Acct        string `json:"acct"`
+		DisplayName string `json:"display_name"`
+	} `json:"account"`
+	Tags []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+}
+
+// mastodonSearchResponse represents the Mastodon v2 search response
+type mastodonSearchResponse struct {
+	Statuses []mastodonStatus `json:"statuses"`
+}
+
+// NewMastodon creates a new Mastodon source. The source URL is the instance
+// (default mastodon.social). The "hashtags" config lists public hashtag timelines
+// to read; with an "access_token" the "keywords" (the Twitter source's list by
+// default) are searched too, since instances only allow search when signed in.
+func NewMastodon(cfg SourceConfig) (Source, error) {
+	instance := mastodonDefaultInstance
+	if cfg.URL != "" {
+		instance = strings.TrimRight(cfg.URL, "/")
+	}
+
+	var hashtags []string
+	for _, tag := range parseCSV(cfg.Config["hashtags"]) {
+		hashtags = append(hashtags, strings.TrimPrefix(tag, "#"))
+	}
+
+	keywords := socialKeywords
+	if kw, ok := cfg.Config["keywords"]; ok && kw != "" {
+		keywords = parseCSV(kw)
+	}
+
+	accessToken := cfg.Config["access_token"]
+	if len(hashtags) == 0 && accessToken == "" {
+		return nil, fmt.Errorf("mastodon source requires hashtags or an access_token in config")
+	}
+
+	return &Mastodon{
+		name:        cfg.Name,
+		instance:    instance,
+		hashtags:    hashtags,
+		keywords:    keywords,
+		accessToken: accessToken,
+		client:      &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (m *Mastodon) Type() string {
+	return "mastodon"
+}
+
+func (m *Mastodon) Name() string {
+	return m.name
+}
+
+// Fetch reads the hashtag timelines and, when signed in, searches the keywords
+func (m *Mastodon) Fetch(ctx context.Context) ([]Opportunity, error) {
+	seen := make(map[string]bool)
+	var opportunities []Opportunity
+	var lastErr error
+
+	collect := func(statuses []mastodonStatus, query string) {
+		for _, status := range statuses {
+			// Boosts repeat a post that is already in the timeline or search
+			if status.Reblog != nil || seen[status.URI] {
+				continue
+			}
+			seen[status.URI] = true
+			opportunities = append(opportunities, m.statusToOpportunity(status, query))
+		}
+	}
+
+	for _, tag := range m.hashtags {
+		var statuses []mastodonStatus
+		params := url.Values{}
+		params.Set("limit", "40")
+		if err := m.get(ctx, "/api/v1/timelines/tag/"+url.PathEscape(tag), params, &statuses); err != nil {
+			lastErr = err
+			continue // Skip failed timelines
+		}
+		collect(statuses, "#"+tag)
+	}
+
+	if m.accessToken != "" {
+		for _, keyword := range m.keywords {
+			var result mastodonSearchResponse
+			params := url.Values{}
+			params.Set("q", keyword)
+			params.Set("type", "statuses")
+			params.Set("limit", "40")
+			if err := m.get(ctx, "/api/v2/search", params, &result); err != nil {
+				lastErr = err
+				continue // Skip failed searches
+			}
+			collect(result.Statuses, keyword)
+		}
+	}
+
+	// Only fail when nothing could be read at all
+	if len(opportunities) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	return opportunities, nil
+}
+
+func (m *Mastodon) get(ctx context.Context, path string, params url.Values, out any) error {
+	reqURL := m.instance + path + "?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "Seer/1.0")
+	if m.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+m.accessToken)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("mastodon API returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+func (m *Mastodon) statusToOpportunity(status mastodonStatus, query string) Opportunity {
+	text := cleanText(status.Content)
+	if status.SpoilerText != "" {
+		text = status.SpoilerText + ": " + text
+	}
+
+	sourceURL := status.URL
+	if sourceURL == "" {
+		sourceURL = status.URI
+	}
+
+	createdAt := status.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	tags := make([]string, 0, len(status.Tags))
+	for _, tag := range status.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	return Opportunity{
+		Title:       truncate(text, 100),
+		Description: text,
+		SourceType:  "mastodon",
+		SourceURL:   sourceURL,
+		// The URI identifies a post across instances; IDs are local to one
+		SourceIDExternal: status.URI,
+		DetectedAt:       createdAt,
+		Metadata: map[string]any{
+			"author":      status.Account.Acct,
+			"boost_count": status.ReblogsCount,
+			"like_count":  status.FavouritesCount,
+			"reply_count": status.RepliesCount,
+			"tags":        tags,
+			"keyword":     query,
+		},
+	}
+}
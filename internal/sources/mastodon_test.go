@@ -0,0 +1,83 @@
+package sources
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMastodon_Config(t *testing.T) {
+	if _, err := NewMastodon(SourceConfig{Name: "No hashtags"}); err == nil {
+		t.Error("expected error without hashtags or access token")
+	}
+
+	src, err := NewMastodon(SourceConfig{Name: "Test", Config: map[string]string{"hashtags": "#selfhosted, indiedev"}})
+	if err != nil {
+		t.Fatalf("NewMastodon() error = %v", err)
+	}
+	m := src.(*Mastodon)
+	if m.Type() != "mastodon" || m.instance != "https://mastodon.social" || len(m.hashtags) != 2 || m.hashtags[0] != "selfhosted" {
+		t.Errorf("unexpected config %+v", m)
+	}
+	if len(m.keywords) != len(socialKeywords) {
+		t.Errorf("expected the Twitter keyword list by default, got %v", m.keywords)
+	}
+}
+
+func TestMastodon_Fetch(t *testing.T) {
+	var searches []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/timelines/tag/selfhosted":
+			w.Write([]byte(`[
+				{"id": "1", "uri": "https://social.example/users/jane/statuses/1", "url": "https://social.example/@jane/1", "created_at": "2026-03-02T10:00:00.000Z", "content": "<p>Looking for a self-hosted <a href=\"#\">#status</a> page &amp; uptime monitor</p>", "reblogs_count": 12, "favourites_count": 40, "replies_count": 7, "account": {"acct": "jane@social.example"}, "tags": [{"name": "selfhosted"}]},
+				{"id": "2", "uri": "https://social.example/users/sam/statuses/2", "reblog": {}, "content": ""}
+			]`))
+		case "/api/v2/search":
+			if r.Header.Get("Authorization") != "Bearer token" {
+				t.Errorf("expected access token on search, got %q", r.Header.Get("Authorization"))
+			}
+			searches = append(searches, r.URL.Query().Get("q"))
+			w.Write([]byte(`{"statuses": [
+				{"id": "9", "uri": "https://social.example/users/jane/statuses/1", "content": "duplicate"},
+				{"id": "3", "uri": "https://other.example/notes/3", "created_at": "2026-03-02T11:00:00.000Z", "spoiler_text": "Tools", "content": "<p>Any alternative to Notion?</p>", "favourites_count": 2, "account": {"acct": "lee@other.example"}}
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	src, _ := NewMastodon(SourceConfig{Name: "Test", URL: server.URL + "/", Config: map[string]string{
+		"hashtags":     "selfhosted",
+		"keywords":     "alternative to",
+		"access_token": "token",
+	}})
+
+	opps, err := src.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(searches) != 1 || searches[0] != "alternative to" {
+		t.Errorf("expected keyword search, got %v", searches)
+	}
+	if len(opps) != 2 {
+		t.Fatalf("expected boosts and duplicates to be skipped, got %d", len(opps))
+	}
+
+	opp := opps[0]
+	if opp.Description != "Looking for a self-hosted #status page & uptime monitor" {
+		t.Errorf("expected text without HTML, got %q", opp.Description)
+	}
+	if opp.SourceType != "mastodon" || opp.SourceURL != "https://social.example/@jane/1" || opp.SourceIDExternal != "https://social.example/users/jane/statuses/1" {
+		t.Errorf("unexpected source fields %+v", opp)
+	}
+	if opp.Metadata["boost_count"] != 12 || opp.Metadata["like_count"] != 40 || opp.Metadata["reply_count"] != 7 || opp.Metadata["keyword"] != "#selfhosted" {
+		t.Errorf("unexpected metadata %v", opp.Metadata)
+	}
+
+	if opps[1].Description != "Tools: Any alternative to Notion?" || opps[1].SourceURL != "https://other.example/notes/3" {
+		t.Errorf("expected content warning and URI fallback, got %+v", opps[1])
+	}
+}
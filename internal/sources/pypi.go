@@ -0,0 +1,230 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	pypiBaseURL  = "https://pypi.org"
+	pypiStatsAPI = "https://pypistats.org/api/packages/"
+)
+
+// PyPI fetches new and updated Python packages from PyPI
+type PyPI struct {
+	name     string
+	baseURL  string
+	statsURL string
+	queries  []string
+	window   time.Duration
+	client   *http.Client
+}
+
+// pypiPackage represents the PyPI JSON API response for a project
+type pypiPackage struct {
+	Info struct {
+		Name        string            `json:"name"`
+		Version     string            `json:"version"`
+		Summary     string            `json:"summary"`
+		Keywords    string            `json:"keywords"`
+		Author      string            `json:"author"`
+		HomePage    string            `json:"home_page"`
+		ProjectURLs map[string]string `json:"project_urls"`
+	} `json:"info"`
+	Releases map[string]json.RawMessage `json:"releases"`
+}
+
+// pypiStats represents the pypistats.org recent downloads response
+type pypiStats struct {
+	Data struct {
+		LastDay   int `json:"last_day"`
+		LastWeek  int `json:"last_week"`
+		LastMonth int `json:"last_month"`
+	} `json:"data"`
+}
+
+// NewPyPI creates a new PyPI source. The optional "queries" config is a comma-
+// separated list of terms a package name or summary must contain, and "days" how
+// recent a release must be (default 14).
+func NewPyPI(cfg SourceConfig) (Source, error) {
+	queries, window, err := registryConfig(cfg, 14)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PyPI{
+		name:     cfg.Name,
+		baseURL:  pypiBaseURL,
+		statsURL: pypiStatsAPI,
+		queries:  queries,
+		window:   window,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Type returns the source type
+func (p *PyPI) Type() string {
+	return "pypi"
+}
+
+// Name returns the source name
+func (p *PyPI) Name() string {
+	return p.name
+}
+
+// Fetch reads the new and updated package feeds and looks up matching packages
+func (p *PyPI) Fetch(ctx context.Context) ([]Opportunity, error) {
+	seen := make(map[string]bool)
+	var opportunities []Opportunity
+	var lastErr error
+	cutoff := time.Now().Add(-p.window)
+
+	for _, feed := range []string{"/rss/packages.xml", "/rss/updates.xml"} {
+		entries, err := p.fetchFeed(ctx, p.baseURL+feed)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		for _, entry := range entries {
+			name := pypiProjectName(entry.Link)
+			if name == "" || seen[strings.ToLower(name)] {
+				continue
+			}
+			if !entry.Published.IsZero() && entry.Published.Before(cutoff) {
+				continue
+			}
+			if !containsAnyTerm(name+" "+entry.Description, p.queries) {
+				continue
+			}
+			seen[strings.ToLower(name)] = true
+
+			opportunities = append(opportunities, p.packageToOpportunity(ctx, name, entry))
+		}
+	}
+
+	// Only fail when neither feed could be read
+	if len(opportunities) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	return opportunities, nil
+}
+
+func (p *PyPI) fetchFeed(ctx context.Context, feedURL string) ([]feedEntry, error) {
+	body, err := p.get(ctx, feedURL)
+	if err != nil {
+		return nil, err
+	}
+	return parseFeed(body)
+}
+
+// get fetches a URL, reading at most feedMaxBytes of the response
+func (p *PyPI) get(ctx context.Context, reqURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "Seer/1.0")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, feedMaxBytes))
+}
+
+// packageToOpportunity builds an opportunity from a feed entry, adding the details
+// and downloads of the package where they can be looked up
+func (p *PyPI) packageToOpportunity(ctx context.Context, name string, entry feedEntry) Opportunity {
+	metadata := map[string]any{}
+	version := ""
+	description := entry.Description
+
+	var pkg pypiPackage
+	if body, err := p.get(ctx, p.baseURL+"/pypi/"+url.PathEscape(name)+"/json"); err == nil && json.Unmarshal(body, &pkg) == nil {
+		name = pkg.Info.Name
+		version = pkg.Info.Version
+		if pkg.Info.Summary != "" {
+			description = pkg.Info.Summary
+		}
+		metadata["versions"] = len(pkg.Releases)
+		metadata["author"] = pkg.Info.Author
+		metadata["keywords"] = parseCSV(strings.ReplaceAll(pkg.Info.Keywords, " ", ","))
+		metadata["homepage"] = pkg.Info.HomePage
+		if repo := pypiRepository(pkg.Info.ProjectURLs); repo != "" {
+			metadata["repository"] = repo
+		}
+	}
+
+	var stats pypiStats
+	if body, err := p.get(ctx, p.statsURL+url.PathEscape(strings.ToLower(name))+"/recent"); err == nil && json.Unmarshal(body, &stats) == nil {
+		metadata["downloads_last_week"] = stats.Data.LastWeek
+		metadata["downloads_last_month"] = stats.Data.LastMonth
+	}
+
+	if version == "" {
+		// Update entries are titled "name version"
+		version = strings.TrimSpace(strings.TrimPrefix(entry.Title, name))
+		if strings.Contains(version, " ") {
+			version = ""
+		}
+	}
+	metadata["version"] = version
+
+	if description == "" {
+		description = fmt.Sprintf("PyPI package: %s %s", name, version)
+	}
+
+	detectedAt := entry.Published
+	if detectedAt.IsZero() {
+		detectedAt = time.Now()
+	}
+
+	return Opportunity{
+		Title:            name,
+		Description:      truncate(description, 1000),
+		SourceType:       "pypi",
+		SourceURL:        p.baseURL + "/project/" + name + "/",
+		SourceIDExternal: name + "@" + version,
+		DetectedAt:       detectedAt,
+		Metadata:         metadata,
+	}
+}
+
+// pypiProjectName returns the project name of a pypi.org project URL
+func pypiProjectName(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "project" {
+		return ""
+	}
+	return parts[1]
+}
+
+// pypiRepository returns the source repository among the project URLs
+func pypiRepository(urls map[string]string) string {
+	for label, link := range urls {
+		switch strings.ToLower(label) {
+		case "source", "source code", "repository", "code", "github":
+			return link
+		}
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+package sources
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// registryQueries are the default search terms of the package registry sources
+var registryQueries = []string{
+	// Developer tools
+	"cli",
+	"devtool",
+	"developer tool",
+
+	// Self-hosted / alternatives
+	"self-hosted",
+	"alternative",
+
+	// Starters and templates
+	"boilerplate",
+	"starter",
+	"template",
+	"scaffold",
+
+	// API/SDK (competition)
+	"sdk",
+	"api client",
+
+	// Trending categories
+	"ai",
+	"llm",
+	"markdown",
+	"pdf",
+}
+
+// registryConfig parses the "queries" and "days" config shared by the package
+// registry sources
+func registryConfig(cfg SourceConfig, defaultDays int) ([]string, time.Duration, error) {
+	queries := registryQueries
+	if q, ok := cfg.Config["queries"]; ok && q != "" {
+		queries = parseCSV(q)
+	}
+
+	days, err := strconv.Atoi(getConfigOrDefault(cfg.Config, "days", strconv.Itoa(defaultDays)))
+	if err != nil || days < 1 {
+		return nil, 0, fmt.Errorf("invalid days: %q", cfg.Config["days"])
+	}
+
+	return queries, time.Duration(days) * 24 * time.Hour, nil
+}
+
+// containsAnyTerm reports whether text contains one of the terms as whole words,
+// so that "ai" matches "ai-agent" but not "email"
+func containsAnyTerm(text string, terms []string) bool {
+	lowerText := strings.ToLower(text)
+	for _, term := range terms {
+		term = strings.ToLower(term)
+		if term == "" {
+			continue
+		}
+		for start := 0; ; {
+			i := strings.Index(lowerText[start:], term)
+			if i < 0 {
+				break
+			}
+			i += start
+			end := i + len(term)
+			if (i == 0 || !isWordByte(lowerText[i-1])) && (end == len(lowerText) || !isWordByte(lowerText[end])) {
+				return true
+			}
+			start = i + 1
+		}
+	}
+	return false
+}
+
+func isWordByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= '0' && b <= '9'
+}
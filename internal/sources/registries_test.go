@@ -0,0 +1,186 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContainsAnyTerm(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"ai-agent toolkit", true},
+		{"github.com/acme/cli", true},
+		{"Send email from Python", false},
+		{"httpclient", false},
+		{"A Markdown renderer", true},
+	}
+
+	for _, tt := range tests {
+		if got := containsAnyTerm(tt.text, []string{"ai", "cli", "markdown"}); got != tt.want {
+			t.Errorf("containsAnyTerm(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestRegistryConfig(t *testing.T) {
+	queries, window, err := registryConfig(SourceConfig{Config: map[string]string{"queries": "orm, queue", "days": "3"}}, 14)
+	if err != nil || len(queries) != 2 || window != 72*time.Hour {
+		t.Errorf("unexpected config %v %v %v", queries, window, err)
+	}
+
+	if _, _, err := registryConfig(SourceConfig{Config: map[string]string{"days": "0"}}, 14); err == nil {
+		t.Error("expected error for invalid days")
+	}
+}
+
+func TestPyPI_Fetch(t *testing.T) {
+	published := time.Now().Add(-time.Hour).UTC().Format(time.RFC1123Z)
+	old := time.Now().AddDate(0, -2, 0).UTC().Format(time.RFC1123Z)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rss/packages.xml":
+			fmt.Fprintf(w, `<?xml version="1.0"?><rss version="2.0"><channel>
+				<item><title>fastcli added to PyPI</title><link>https://pypi.org/project/fastcli/</link><description>Build a CLI in minutes</description><pubDate>%s</pubDate></item>
+				<item><title>mailer added to PyPI</title><link>https://pypi.org/project/mailer/</link><description>Send email</description><pubDate>%s</pubDate></item>
+			</channel></rss>`, published, published)
+		case "/rss/updates.xml":
+			fmt.Fprintf(w, `<?xml version="1.0"?><rss version="2.0"><channel>
+				<item><title>pdfkit2 2.1.0</title><link>https://pypi.org/project/pdfkit2/2.1.0/</link><description>PDF tools</description><pubDate>%s</pubDate></item>
+				<item><title>oldpdf 1.0</title><link>https://pypi.org/project/oldpdf/1.0/</link><description>PDF</description><pubDate>%s</pubDate></item>
+			</channel></rss>`, published, old)
+		case "/pypi/fastcli/json":
+			w.Write([]byte(`{"info": {"name": "fastcli", "version": "0.3.0", "summary": "Build a CLI in minutes", "keywords": "cli terminal", "author": "Jane", "project_urls": {"Source": "https://github.com/jane/fastcli"}}, "releases": {"0.1.0": [], "0.2.0": [], "0.3.0": []}}`))
+		case "/stats/fastcli/recent":
+			w.Write([]byte(`{"data": {"last_day": 10, "last_week": 120, "last_month": 480}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	src, _ := NewPyPI(SourceConfig{Name: "Test PyPI", Config: map[string]string{"queries": "cli, pdf"}})
+	p := src.(*PyPI)
+	p.baseURL = server.URL
+	p.statsURL = server.URL + "/stats/"
+
+	opps, err := p.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(opps) != 2 {
+		t.Fatalf("expected matching recent packages only, got %d", len(opps))
+	}
+
+	opp := opps[0]
+	if opp.Title != "fastcli" || opp.SourceType != "pypi" || opp.SourceIDExternal != "fastcli@0.3.0" {
+		t.Errorf("unexpected package %+v", opp)
+	}
+	if opp.Metadata["versions"] != 3 || opp.Metadata["downloads_last_week"] != 120 || opp.Metadata["repository"] != "https://github.com/jane/fastcli" {
+		t.Errorf("unexpected metadata %v", opp.Metadata)
+	}
+
+	// Without package details the version comes from the update entry
+	if opps[1].SourceIDExternal != "pdfkit2@2.1.0" || opps[1].Description != "PDF tools" {
+		t.Errorf("unexpected updated package %+v", opps[1])
+	}
+}
+
+func TestCrates_Fetch(t *testing.T) {
+	now := time.Now().UTC()
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		if r.URL.Query().Get("sort") != "new" {
+			t.Errorf("expected newest crates, got sort=%s", r.URL.Query().Get("sort"))
+		}
+		if !strings.Contains(r.Header.Get("User-Agent"), "Seer") {
+			t.Errorf("expected a User-Agent, got %q", r.Header.Get("User-Agent"))
+		}
+		fmt.Fprintf(w, `{"crates": [
+			{"id": "tui-forms", "name": "tui-forms", "description": "Forms for terminal apps", "newest_version": "0.2.0", "downloads": 830, "recent_downloads": 400, "repository": "https://github.com/acme/tui-forms", "created_at": %q},
+			{"id": "ancient", "name": "ancient", "newest_version": "1.0.0", "created_at": %q}
+		]}`, now.Add(-time.Hour).Format(time.RFC3339), now.AddDate(-1, 0, 0).Format(time.RFC3339))
+	}))
+	defer server.Close()
+
+	src, _ := NewCrates(SourceConfig{Name: "Test crates", Config: map[string]string{"queries": "tui, forms", "days": "7"}})
+	c := src.(*Crates)
+	c.apiURL = server.URL
+
+	opps, err := c.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if strings.Join(queries, ",") != "tui,forms" {
+		t.Errorf("expected one search per query, got %v", queries)
+	}
+	if len(opps) != 1 {
+		t.Fatalf("expected recent crates deduplicated, got %d", len(opps))
+	}
+
+	opp := opps[0]
+	if opp.SourceType != "crates" || opp.SourceIDExternal != "tui-forms@0.2.0" || opp.SourceURL != "https://crates.io/crates/tui-forms" {
+		t.Errorf("unexpected crate %+v", opp)
+	}
+	if opp.Metadata["downloads"] != 830 || opp.Metadata["recent_downloads"] != 400 || opp.Metadata["version"] != "0.2.0" {
+		t.Errorf("unexpected metadata %v", opp.Metadata)
+	}
+}
+
+func TestGoModules_Fetch(t *testing.T) {
+	base := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	var since []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		since = append(since, r.URL.Query().Get("since"))
+		for i, line := range []string{
+			`{"Path": "github.com/acme/cli", "Version": "v0.1.0", "Timestamp": %q}`,
+			`{"Path": "github.com/acme/mailclient", "Version": "v1.0.0", "Timestamp": %q}`,
+			`{"Path": "github.com/acme/cli", "Version": "v0.0.0-20260301120000-abcdef123456", "Timestamp": %q}`,
+			`{"Path": "github.com/acme/cli", "Version": "v0.2.0", "Timestamp": %q}`,
+		} {
+			fmt.Fprintf(w, line+"\n", base.Add(time.Duration(i)*time.Minute).Format(time.RFC3339Nano))
+		}
+	}))
+	defer server.Close()
+
+	src, _ := NewGoModules(SourceConfig{Name: "Test Go", Config: map[string]string{"queries": "cli"}})
+	g := src.(*GoModules)
+	g.indexURL = server.URL
+
+	opps, err := g.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(opps) != 1 {
+		t.Fatalf("expected one matching module, got %d", len(opps))
+	}
+
+	opp := opps[0]
+	if opp.SourceIDExternal != "github.com/acme/cli@v0.2.0" || opp.SourceURL != "https://pkg.go.dev/github.com/acme/cli" {
+		t.Errorf("expected latest release, got %+v", opp)
+	}
+	if opp.Metadata["version"] != "v0.2.0" || opp.Metadata["host"] != "github.com" {
+		t.Errorf("unexpected metadata %v", opp.Metadata)
+	}
+
+	// The next fetch continues after the last entry read
+	state := g.State()
+	if state["since"] != base.Add(3*time.Minute).Format(time.RFC3339Nano) {
+		t.Errorf("unexpected state %v", state)
+	}
+
+	next, _ := NewGoModules(SourceConfig{Name: "Test Go", Config: map[string]string{"queries": "cli"}, State: state})
+	next.(*GoModules).indexURL = server.URL
+	next.Fetch(context.Background())
+	if since[len(since)-1] != state["since"] {
+		t.Errorf("expected fetch to resume from state, got since=%s", since[len(since)-1])
+	}
+}
@@ -2,5 +2,5 @@ package sources
 
 // GetAvailableTypes returns the available source types
 func GetAvailableTypes() []string {
-	return []string{"hackernews", "github", "npm", "devto", "reddit", "twitter", "custom"}
+	return []string{"hackernews", "github", "npm", "devto", "reddit", "twitter", "custom", "feed", "lobsters", "stackexchange", "github_issues", "pypi", "crates", "gomodules", "mastodon", "bluesky", "discourse", "exec", "webhook"}
 }
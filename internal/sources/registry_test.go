@@ -7,7 +7,7 @@ import (
 func TestGetAvailableTypes(t *testing.T) {
 	types := GetAvailableTypes()
 
-	expected := []string{"hackernews", "github", "npm", "devto", "reddit", "twitter", "custom"}
+	expected := []string{"hackernews", "github", "npm", "devto", "reddit", "twitter", "custom", "feed", "lobsters", "stackexchange", "github_issues", "pypi", "crates", "gomodules", "mastodon", "bluesky", "discourse", "exec", "webhook"}
 	if len(types) != len(expected) {
 		t.Errorf("expected %d types, got %d", len(expected), len(types))
 	}
@@ -139,9 +139,10 @@ func (r *Repository) Create(s *SourceRecord) error {
 
 // Update updates an existing source
 func (r *Repository) Update(s *SourceRecord) error {
+	// State kept from earlier fetches may not apply to the new settings
 	_, err := r.db.Exec(`
 		UPDATE sources
-		SET name = ?, url = ?, config = ?, enabled = ?
+		SET name = ?, url = ?, config = ?, enabled = ?, state = '{}'
 		WHERE id = ? AND is_builtin = false
 	`, s.Name, s.URL, s.Config, s.Enabled, s.ID)
 
@@ -152,6 +153,32 @@ func (r *Repository) Update(s *SourceRecord) error {
 	return nil
 }
 
+// GetState returns the state a source kept from its previous fetch
+func (r *Repository) GetState(id int64) (map[string]string, error) {
+	var value sql.NullString
+	err := r.db.QueryRow(`SELECT state FROM sources WHERE id = ?`, id).Scan(&value)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get source state: %w", err)
+	}
+
+	state := make(map[string]string)
+	json.Unmarshal([]byte(value.String), &state)
+	return state, nil
+}
+
+// SetState stores the state a source keeps for its next fetch
+func (r *Repository) SetState(id int64, state map[string]string) error {
+	data, err := json.Marshal(state)
+	if err != nil || state == nil {
+		data = []byte("{}")
+	}
+
+	if _, err := r.db.Exec(`UPDATE sources SET state = ? WHERE id = ?`, string(data), id); err != nil {
+		return fmt.Errorf("failed to set source state: %w", err)
+	}
+	return nil
+}
+
 // SetEnabled updates the enabled status of a source
 func (r *Repository) SetEnabled(id int64, enabled bool) error {
 	_, err := r.db.Exec(`UPDATE sources SET enabled = ? WHERE id = ?`, enabled, id)
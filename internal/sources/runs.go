@@ -0,0 +1,96 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// fetchRunsKept is how many fetch runs are kept per source
+const fetchRunsKept = 50
+
+// FetchRun records the outcome of fetching one source
+type FetchRun struct {
+	ID         int64     `json:"id"`
+	SourceID   int64     `json:"source_id"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	// Fetched counts the opportunities the source returned
+	Fetched int `json:"fetched"`
+	// Kept counts those left after the keyword filters
+	Kept     int      `json:"kept"`
+	Error    string   `json:"error,omitempty"`
+	Warnings []string `json:"warnings"`
+}
+
+// RecordRun stores a fetch run, keeping only the most recent runs of its source
+func (r *Repository) RecordRun(run *FetchRun) error {
+	warnings, err := json.Marshal(nonNilStrings(run.Warnings))
+	if err != nil {
+		return fmt.Errorf("failed to encode warnings: %w", err)
+	}
+
+	result, err := r.db.Exec(`
+		INSERT INTO fetch_runs (source_id, started_at, finished_at, fetched, kept, error, warnings)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, run.SourceID, run.StartedAt, run.FinishedAt, run.Fetched, run.Kept, run.Error, string(warnings))
+	if err != nil {
+		return fmt.Errorf("failed to record fetch run: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get fetch run ID: %w", err)
+	}
+	run.ID = id
+
+	_, err = r.db.Exec(`
+		DELETE FROM fetch_runs WHERE source_id = ? AND id NOT IN (
+			SELECT id FROM fetch_runs WHERE source_id = ? ORDER BY started_at DESC, id DESC LIMIT ?
+		)
+	`, run.SourceID, run.SourceID, fetchRunsKept)
+	if err != nil {
+		return fmt.Errorf("failed to prune fetch runs: %w", err)
+	}
+
+	return nil
+}
+
+// GetRuns returns the most recent fetch runs of a source, newest first
+func (r *Repository) GetRuns(sourceID int64, limit int) ([]FetchRun, error) {
+	if limit <= 0 || limit > fetchRunsKept {
+		limit = fetchRunsKept
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, source_id, started_at, finished_at, fetched, kept, COALESCE(error, ''), COALESCE(warnings, '[]')
+		FROM fetch_runs WHERE source_id = ?
+		ORDER BY started_at DESC, id DESC LIMIT ?
+	`, sourceID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fetch runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []FetchRun{}
+	for rows.Next() {
+		var run FetchRun
+		var warnings string
+		if err := rows.Scan(&run.ID, &run.SourceID, &run.StartedAt, &run.FinishedAt, &run.Fetched, &run.Kept, &run.Error, &warnings); err != nil {
+			return nil, fmt.Errorf("failed to scan fetch run: %w", err)
+		}
+		json.Unmarshal([]byte(warnings), &run.Warnings)
+		run.Warnings = nonNilStrings(run.Warnings)
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// nonNilStrings returns items, or an empty list when it is nil
+func nonNilStrings(items []string) []string {
+	if items == nil {
+		return []string{}
+	}
+	return items
+}
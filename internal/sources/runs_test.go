@@ -0,0 +1,102 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+type warningStub struct {
+	stubSource
+	warnings []string
+	err      error
+}
+
+func (s *warningStub) Warnings() []string { return s.warnings }
+func (s *warningStub) Fetch(ctx context.Context) ([]Opportunity, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.opportunities, nil
+}
+
+func TestManager_FetchSourceRecordsRuns(t *testing.T) {
+	db := setupTestDB(t)
+	m := NewManager(db, 60)
+
+	stub := &warningStub{
+		stubSource: stubSource{opportunities: []Opportunity{
+			{Title: "Self-hosted analytics", SourceType: "stub", SourceURL: "https://example.com/1", SourceIDExternal: "1"},
+			{Title: "Crypto exchange launch", SourceType: "stub", SourceURL: "https://example.com/2", SourceIDExternal: "2"},
+		}},
+		warnings: []string{"rate limit exhausted"},
+	}
+	m.RegisterFactory("stub", func(cfg SourceConfig) (Source, error) { return stub, nil })
+
+	record := &SourceRecord{Type: "stub", Name: "Stub", Enabled: true, Config: "{}"}
+	if err := m.repo.Create(record); err != nil {
+		t.Fatalf("failed to create source: %v", err)
+	}
+	if err := m.keywords.SaveKeywords(&KeywordsConfig{ExcludeKeywords: []string{"crypto"}}); err != nil {
+		t.Fatalf("failed to save keywords: %v", err)
+	}
+
+	if err := m.fetchSource(context.Background(), *record); err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+
+	stub.err = fmt.Errorf("connection refused")
+	stub.warnings = nil
+	if err := m.fetchSource(context.Background(), *record); err == nil {
+		t.Fatal("expected fetch error")
+	}
+
+	runs, err := m.repo.GetRuns(record.ID, 10)
+	if err != nil {
+		t.Fatalf("GetRuns() error = %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(runs))
+	}
+
+	failed, ok := runs[0], runs[1]
+	if failed.Error != "failed to fetch: connection refused" || len(failed.Warnings) != 0 {
+		t.Errorf("expected latest run to record the error, got %+v", failed)
+	}
+	if ok.Fetched != 2 || ok.Kept != 1 || ok.Error != "" {
+		t.Errorf("expected counts of the successful run, got %+v", ok)
+	}
+	if len(ok.Warnings) != 1 || ok.Warnings[0] != "rate limit exhausted" {
+		t.Errorf("expected warnings of the successful run, got %v", ok.Warnings)
+	}
+	if ok.FinishedAt.Before(ok.StartedAt) {
+		t.Errorf("expected finish after start, got %+v", ok)
+	}
+}
+
+func TestRepository_RecordRunPrunes(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	record := &SourceRecord{Type: "stub", Name: "Stub", Enabled: true, Config: "{}"}
+	if err := repo.Create(record); err != nil {
+		t.Fatalf("failed to create source: %v", err)
+	}
+
+	for i := 0; i < fetchRunsKept+5; i++ {
+		if err := repo.RecordRun(&FetchRun{SourceID: record.ID, Fetched: i}); err != nil {
+			t.Fatalf("RecordRun() error = %v", err)
+		}
+	}
+
+	var count int
+	db.QueryRow("SELECT COUNT(*) FROM fetch_runs WHERE source_id = ?", record.ID).Scan(&count)
+	if count != fetchRunsKept {
+		t.Errorf("expected %d runs kept, got %d", fetchRunsKept, count)
+	}
+
+	runs, _ := repo.GetRuns(record.ID, 1)
+	if len(runs) != 1 || runs[0].Fetched != fetchRunsKept+4 {
+		t.Errorf("expected the newest run, got %+v", runs)
+	}
+}
@@ -37,6 +37,28 @@ type SourceConfig struct {
 	Config    map[string]string `json:"config,omitempty"`
 	Enabled   bool              `json:"enabled"`
 	IsBuiltin bool              `json:"is_builtin"`
+	// State is what the source returned from State after its previous fetch
+	State map[string]string `json:"-"`
+}
+
+// StatefulSource is a Source that keeps state between fetches, such as HTTP cache
+// validators. The state is stored after each successful fetch and passed back in
+// SourceConfig.State; it is cleared when the source is edited.
+type StatefulSource interface {
+	Source
+
+	// State returns the state to keep for the next fetch
+	State() map[string]string
+}
+
+// WarningSource is a Source that reports problems which did not fail its fetch but
+// may have left the results incomplete, such as an exhausted rate limit. The
+// warnings of each fetch are recorded in its fetch run.
+type WarningSource interface {
+	Source
+
+	// Warnings returns the problems of the last fetch
+	Warnings() []string
 }
 
 // SourceFactory creates a Source from configuration
@@ -0,0 +1,288 @@
+package sources
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	stackExchangeAPI = "https://api.stackexchange.com/2.3/search/advanced"
+
+	// stackExchangeWindow is how far back questions are searched
+	stackExchangeWindow = 7 * 24 * time.Hour
+
+	// stackExchangeStateBackoff is the state key holding the time until which the
+	// API asked not to be called, so a backoff also holds across fetches
+	stackExchangeStateBackoff = "backoff_until"
+)
+
+// StackExchange fetches questions from Stack Exchange sites such as Stack Overflow
+type StackExchange struct {
+	name        string
+	apiURL      string
+	sites       []string
+	queries     []string
+	tags        []string
+	minVotes    int
+	unaccepted  bool
+	key         string
+	client      *http.Client
+	sleep       func(ctx context.Context, d time.Duration) error
+	backoffTill time.Time
+}
+
+// stackExchangeResponse represents the common wrapper of Stack Exchange API responses
+type stackExchangeResponse struct {
+	Items          []stackExchangeQuestion `json:"items"`
+	HasMore        bool                    `json:"has_more"`
+	QuotaRemaining int                     `json:"quota_remaining"`
+	Backoff        int                     `json:"backoff"`
+	ErrorID        int                     `json:"error_id"`
+	ErrorName      string                  `json:"error_name"`
+	ErrorMessage   string                  `json:"error_message"`
+}
+
+type stackExchangeQuestion struct {
+	QuestionID       int64    `json:"question_id"`
+	Title            string   `json:"title"`
+	Link             string   `json:"link"`
+	Body             string   `json:"body"`
+	Tags             []string `json:"tags"`
+	Score            int      `json:"score"`
+	ViewCount        int      `json:"view_count"`
+	AnswerCount      int      `json:"answer_count"`
+	IsAnswered       bool     `json:"is_answered"`
+	AcceptedAnswerID int64    `json:"accepted_answer_id"`
+	CreationDate     int64    `json:"creation_date"`
+	Owner            struct {
+		DisplayName string `json:"display_name"`
+	} `json:"owner"`
+}
+
+// NewStackExchange creates a new Stack Exchange source. Config keys: "site" (comma-
+// separated API site names, default stackoverflow), "queries" (comma-separated
+// search phrases), "tags" (comma-separated, a question needs one of them),
+// "min_votes", "no_accepted_answer" (default true) and the optional API "key".
+func NewStackExchange(cfg SourceConfig) (Source, error) {
+	sites := parseCSV(getConfigOrDefault(cfg.Config, "site", "stackoverflow"))
+	if len(sites) == 0 {
+		return nil, fmt.Errorf("stackexchange source requires a site")
+	}
+
+	queries := []string{"is there a tool", "alternative to", "looking for a tool"}
+	if q, ok := cfg.Config["queries"]; ok && q != "" {
+		queries = parseCSV(q)
+	}
+
+	var tags []string
+	if t, ok := cfg.Config["tags"]; ok && t != "" {
+		tags = parseCSV(t)
+	}
+
+	minVotes, err := strconv.Atoi(getConfigOrDefault(cfg.Config, "min_votes", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid min_votes: %w", err)
+	}
+
+	unaccepted, err := strconv.ParseBool(getConfigOrDefault(cfg.Config, "no_accepted_answer", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid no_accepted_answer: %w", err)
+	}
+
+	backoffTill, _ := time.Parse(time.RFC3339, cfg.State[stackExchangeStateBackoff])
+
+	return &StackExchange{
+		name:        cfg.Name,
+		apiURL:      stackExchangeAPI,
+		sites:       sites,
+		queries:     queries,
+		tags:        tags,
+		minVotes:    minVotes,
+		unaccepted:  unaccepted,
+		key:         cfg.Config["key"],
+		client:      &http.Client{Timeout: 30 * time.Second},
+		sleep:       sleepContext,
+		backoffTill: backoffTill,
+	}, nil
+}
+
+// Type returns the source type
+func (s *StackExchange) Type() string {
+	return "stackexchange"
+}
+
+// Name returns the source name
+func (s *StackExchange) Name() string {
+	return s.name
+}
+
+// State returns the end of the backoff the API last asked for, if still pending
+func (s *StackExchange) State() map[string]string {
+	state := make(map[string]string)
+	if time.Now().Before(s.backoffTill) {
+		state[stackExchangeStateBackoff] = s.backoffTill.Format(time.RFC3339)
+	}
+	return state
+}
+
+// Fetch searches every site for recent questions matching the configured queries
+func (s *StackExchange) Fetch(ctx context.Context) ([]Opportunity, error) {
+	seen := make(map[string]bool)
+	var opportunities []Opportunity
+	var lastErr error
+
+	for _, site := range s.sites {
+		for _, query := range s.queries {
+			questions, err := s.search(ctx, site, query)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				lastErr = err
+				continue // Skip failed queries
+			}
+
+			for _, q := range questions {
+				id := site + ":" + strconv.FormatInt(q.QuestionID, 10)
+				if seen[id] || q.Score < s.minVotes {
+					continue
+				}
+				seen[id] = true
+
+				opportunities = append(opportunities, s.questionToOpportunity(site, q))
+			}
+		}
+	}
+
+	// Only fail when no query could be run at all
+	if len(opportunities) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	return opportunities, nil
+}
+
+func (s *StackExchange) search(ctx context.Context, site, query string) ([]stackExchangeQuestion, error) {
+	// The API asks clients to wait out a backoff before calling it again
+	if wait := time.Until(s.backoffTill); wait > 0 {
+		if err := s.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+
+	params := url.Values{}
+	params.Set("site", site)
+	params.Set("q", query)
+	params.Set("sort", "creation")
+	params.Set("order", "desc")
+	params.Set("pagesize", "50")
+	params.Set("fromdate", strconv.FormatInt(time.Now().Add(-stackExchangeWindow).Unix(), 10))
+	params.Set("filter", "withbody")
+	if len(s.tags) > 0 {
+		params.Set("tagged", strings.Join(s.tags, ";"))
+	}
+	if s.unaccepted {
+		params.Set("accepted", "False")
+	}
+	if s.key != "" {
+		params.Set("key", s.key)
+	}
+
+	reqURL := s.apiURL + "?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "Seer/1.0")
+	// Responses are always compressed; asking explicitly means decompressing here
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body io.Reader = resp.Body
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress response: %w", err)
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	var result stackExchangeResponse
+	if err := json.NewDecoder(body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response (status %d): %w", resp.StatusCode, err)
+	}
+
+	if result.Backoff > 0 {
+		s.backoffTill = time.Now().Add(time.Duration(result.Backoff) * time.Second)
+	}
+
+	if result.ErrorID != 0 {
+		return nil, fmt.Errorf("stackexchange %s: %s", result.ErrorName, result.ErrorMessage)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	return result.Items, nil
+}
+
+func (s *StackExchange) questionToOpportunity(site string, q stackExchangeQuestion) Opportunity {
+	createdAt := time.Unix(q.CreationDate, 0)
+	if q.CreationDate == 0 {
+		createdAt = time.Now()
+	}
+
+	tags := q.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return Opportunity{
+		// Titles come HTML-encoded from the API
+		Title:            html.UnescapeString(q.Title),
+		Description:      truncate(cleanText(q.Body), 1000),
+		SourceType:       "stackexchange",
+		SourceURL:        q.Link,
+		SourceIDExternal: site + ":" + strconv.FormatInt(q.QuestionID, 10),
+		DetectedAt:       createdAt,
+		Metadata: map[string]any{
+			"site":         site,
+			"author":       html.UnescapeString(q.Owner.DisplayName),
+			"score":        q.Score,
+			"view_count":   q.ViewCount,
+			"answer_count": q.AnswerCount,
+			"is_answered":  q.IsAnswered,
+			"tags":         tags,
+		},
+	}
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,175 @@
+package sources
+
+import (
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const stackExchangeFixture = `{
+	"items": [
+		{"question_id": 101, "title": "Is there a tool to diff two Postgres schemas&#39; functions?", "link": "https://stackoverflow.com/q/101", "body": "<p>I compare them <b>by hand</b> every release.</p>", "tags": ["postgresql", "diff"], "score": 7, "view_count": 1200, "answer_count": 1, "is_answered": false, "creation_date": 1772443800, "owner": {"display_name": "Jane &amp; Co"}},
+		{"question_id": 102, "title": "Alternative to cron on Windows", "link": "https://stackoverflow.com/q/102", "tags": ["windows"], "score": 1, "view_count": 40, "answer_count": 0, "creation_date": 1772440000}
+	],
+	"has_more": false,
+	"quota_remaining": 290,
+	"backoff": 10
+}`
+
+// newStackExchangeServer serves the fixture gzip-compressed, recording the queries
+func newStackExchangeServer(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf("expected gzip to be requested")
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(stackExchangeFixture))
+		gz.Close()
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &queries
+}
+
+func newTestStackExchange(t *testing.T, url string, config map[string]string) (*StackExchange, *[]time.Duration) {
+	t.Helper()
+
+	src, err := NewStackExchange(SourceConfig{Name: "Test SE", Config: config})
+	if err != nil {
+		t.Fatalf("NewStackExchange() error = %v", err)
+	}
+
+	var waits []time.Duration
+	se := src.(*StackExchange)
+	se.apiURL = url
+	se.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return se, &waits
+}
+
+func TestStackExchange_Config(t *testing.T) {
+	if _, err := NewStackExchange(SourceConfig{Config: map[string]string{"min_votes": "many"}}); err == nil {
+		t.Error("expected error for invalid min_votes")
+	}
+	if _, err := NewStackExchange(SourceConfig{Config: map[string]string{"no_accepted_answer": "maybe"}}); err == nil {
+		t.Error("expected error for invalid no_accepted_answer")
+	}
+
+	src, _ := NewStackExchange(SourceConfig{Name: "Test SE"})
+	se := src.(*StackExchange)
+	if se.Type() != "stackexchange" || len(se.sites) != 1 || se.sites[0] != "stackoverflow" || !se.unaccepted {
+		t.Errorf("unexpected defaults %+v", se)
+	}
+}
+
+func TestStackExchange_Fetch(t *testing.T) {
+	server, queries := newStackExchangeServer(t)
+	se, waits := newTestStackExchange(t, server.URL, map[string]string{
+		"site":    "stackoverflow, superuser",
+		"queries": "is there a tool",
+		"tags":    "postgresql, windows",
+		"key":     "abc",
+	})
+
+	opps, err := se.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	if len(*queries) != 2 {
+		t.Fatalf("expected one request per site and query, got %d", len(*queries))
+	}
+	for _, want := range []string{"site=stackoverflow", "tagged=postgresql%3Bwindows", "accepted=False", "key=abc", "filter=withbody", "q=is+there+a+tool"} {
+		if !strings.Contains((*queries)[0], want) {
+			t.Errorf("expected query to contain %s, got %s", want, (*queries)[0])
+		}
+	}
+
+	// Questions are kept per site, since IDs are only unique within one
+	if len(opps) != 4 {
+		t.Fatalf("expected 4 opportunities, got %d", len(opps))
+	}
+
+	opp := opps[0]
+	if opp.Title != "Is there a tool to diff two Postgres schemas' functions?" {
+		t.Errorf("expected unescaped title, got %q", opp.Title)
+	}
+	if opp.Description != "I compare them by hand every release." {
+		t.Errorf("expected body without HTML, got %q", opp.Description)
+	}
+	if opp.SourceIDExternal != "stackoverflow:101" || opps[2].SourceIDExternal != "superuser:101" {
+		t.Errorf("expected site-qualified IDs, got %s and %s", opp.SourceIDExternal, opps[2].SourceIDExternal)
+	}
+	if opp.Metadata["score"] != 7 || opp.Metadata["view_count"] != 1200 || opp.Metadata["answer_count"] != 1 || opp.Metadata["author"] != "Jane & Co" {
+		t.Errorf("unexpected metadata %v", opp.Metadata)
+	}
+	if !opp.DetectedAt.Equal(time.Unix(1772443800, 0)) {
+		t.Errorf("unexpected date %v", opp.DetectedAt)
+	}
+
+	// The backoff of the first response delays the second request and is kept in state
+	if len(*waits) != 1 || (*waits)[0] <= 0 || (*waits)[0] > 10*time.Second {
+		t.Errorf("expected one backoff wait, got %v", *waits)
+	}
+	if se.State()["backoff_until"] == "" {
+		t.Error("expected pending backoff in state")
+	}
+}
+
+func TestStackExchange_MinVotes(t *testing.T) {
+	server, _ := newStackExchangeServer(t)
+	se, _ := newTestStackExchange(t, server.URL, map[string]string{"queries": "alternative to", "min_votes": "5", "no_accepted_answer": "false"})
+
+	opps, err := se.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(opps) != 1 || opps[0].SourceIDExternal != "stackoverflow:101" {
+		t.Errorf("expected only the question with enough votes, got %+v", opps)
+	}
+}
+
+func TestStackExchange_BackoffFromState(t *testing.T) {
+	server, _ := newStackExchangeServer(t)
+
+	src, _ := NewStackExchange(SourceConfig{
+		Name:   "Test SE",
+		Config: map[string]string{"queries": "alternative to"},
+		State:  map[string]string{"backoff_until": time.Now().Add(time.Minute).Format(time.RFC3339)},
+	})
+	se := src.(*StackExchange)
+	se.apiURL = server.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := se.Fetch(ctx); err == nil {
+		t.Error("expected fetch to wait out the stored backoff")
+	}
+}
+
+func TestStackExchange_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`{"error_id": 502, "error_name": "throttle_violation", "error_message": "too many requests from this IP"}`))
+	}))
+	defer server.Close()
+
+	se, _ := newTestStackExchange(t, server.URL, map[string]string{"queries": "alternative to"})
+	_, err := se.Fetch(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "throttle_violation") {
+		t.Errorf("expected API error, got %v", err)
+	}
+}
@@ -38,6 +38,9 @@ type twitterTweet struct {
 	} `json:"public_metrics"`
 }
 
+// socialKeywords are the default searches of the social sources
+var socialKeywords = []string{"looking for", "need a tool", "wish there was", "anyone know", "alternative to"}
+
 // NewTwitter creates a new Twitter source
 func NewTwitter(cfg SourceConfig) (Source, error) {
 	bearerToken := ""
@@ -49,7 +52,7 @@ func NewTwitter(cfg SourceConfig) (Source, error) {
 		return nil, fmt.Errorf("twitter source requires bearer_token in config")
 	}
 
-	keywords := []string{"looking for", "need a tool", "wish there was", "anyone know", "alternative to"}
+	keywords := socialKeywords
 	if kw, ok := cfg.Config["keywords"]; ok && kw != "" {
 		keywords = parseCSV(kw)
 	}
@@ -0,0 +1,297 @@
+package sources
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Watchlist is a named set of keyword rules used to tag opportunities
+type Watchlist struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	KeywordsConfig
+
+	// Sources limits the watchlist to these source types (empty means all)
+	Sources   []string  `json:"sources"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Normalize validates the watchlist and cleans up its keywords and source scope
+func (w *Watchlist) Normalize() error {
+	w.Name = strings.TrimSpace(w.Name)
+	if w.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if err := w.KeywordsConfig.Normalize(); err != nil {
+		return err
+	}
+
+	available := make(map[string]bool)
+	for _, t := range GetAvailableTypes() {
+		available[t] = true
+	}
+
+	scoped := []string{}
+	seen := make(map[string]bool)
+	for _, s := range w.Sources {
+		s = strings.TrimSpace(s)
+		if s == "" || seen[s] {
+			continue
+		}
+		if !available[s] {
+			return fmt.Errorf("unknown source type: %s", s)
+		}
+		seen[s] = true
+		scoped = append(scoped, s)
+	}
+	w.Sources = scoped
+
+	return nil
+}
+
+// Matches reports whether an opportunity belongs to the watchlist: it must be in
+// scope, not excluded, and match at least one include or boost keyword
+func (w *Watchlist) Matches(opp Opportunity) bool {
+	if len(w.Sources) > 0 {
+		inScope := false
+		for _, s := range w.Sources {
+			if s == opp.SourceType {
+				inScope = true
+				break
+			}
+		}
+		if !inScope {
+			return false
+		}
+	}
+
+	if len(FilterOpportunities([]Opportunity{opp}, &w.KeywordsConfig)) == 0 {
+		return false
+	}
+
+	// The filter keeps everything when there are no include keywords, so a
+	// watchlist of boost keywords alone must still see one of them
+	if len(w.IncludeKeywords) > 0 {
+		return true
+	}
+	return containsAnyKeyword(opp.Title+" "+opp.Description, w.BoostKeywords)
+}
+
+// MatchWatchlists returns the watchlists an opportunity matches
+func MatchWatchlists(opp Opportunity, watchlists []Watchlist) []Watchlist {
+	var matched []Watchlist
+	for _, w := range watchlists {
+		if w.Matches(opp) {
+			matched = append(matched, w)
+		}
+	}
+	return matched
+}
+
+// WatchlistRepository manages watchlists and opportunity tags in the database
+type WatchlistRepository struct {
+	db *sql.DB
+}
+
+// NewWatchlistRepository creates a new watchlist repository
+func NewWatchlistRepository(db *sql.DB) *WatchlistRepository {
+	return &WatchlistRepository{db: db}
+}
+
+// GetAll returns all watchlists
+func (r *WatchlistRepository) GetAll() ([]Watchlist, error) {
+	rows, err := r.db.Query(`
+		SELECT id, name, keywords, sources, created_at
+		FROM watchlists
+		ORDER BY name ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watchlists: %w", err)
+	}
+	defer rows.Close()
+
+	var watchlists []Watchlist
+	for rows.Next() {
+		w, err := scanWatchlist(rows)
+		if err != nil {
+			return nil, err
+		}
+		watchlists = append(watchlists, *w)
+	}
+
+	return watchlists, rows.Err()
+}
+
+// GetByID returns a watchlist by ID
+func (r *WatchlistRepository) GetByID(id int64) (*Watchlist, error) {
+	w, err := scanWatchlist(r.db.QueryRow(`
+		SELECT id, name, keywords, sources, created_at
+		FROM watchlists
+		WHERE id = ?
+	`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return w, err
+}
+
+// Create creates a new watchlist and tags existing opportunities with it
+func (r *WatchlistRepository) Create(w *Watchlist) error {
+	keywords, sources, err := marshalWatchlist(w)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(`
+		INSERT INTO watchlists (name, keywords, sources)
+		VALUES (?, ?, ?)
+	`, w.Name, keywords, sources)
+	if err != nil {
+		return fmt.Errorf("failed to create watchlist: %w", err)
+	}
+
+	w.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	w.CreatedAt = time.Now()
+
+	return r.Retag(w)
+}
+
+// Update updates a watchlist and re-tags existing opportunities
+func (r *WatchlistRepository) Update(w *Watchlist) error {
+	keywords, sources, err := marshalWatchlist(w)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		UPDATE watchlists
+		SET name = ?, keywords = ?, sources = ?
+		WHERE id = ?
+	`, w.Name, keywords, sources, w.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update watchlist: %w", err)
+	}
+
+	return r.Retag(w)
+}
+
+// Delete deletes a watchlist and its tags
+func (r *WatchlistRepository) Delete(id int64) error {
+	result, err := r.db.Exec(`DELETE FROM watchlists WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete watchlist: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("watchlist not found")
+	}
+
+	return nil
+}
+
+// Tag replaces the watchlist tags of an opportunity
+func (r *WatchlistRepository) Tag(opportunityID int64, watchlists []Watchlist) error {
+	if _, err := r.db.Exec(`DELETE FROM opportunity_watchlists WHERE opportunity_id = ?`, opportunityID); err != nil {
+		return fmt.Errorf("failed to clear watchlist tags: %w", err)
+	}
+
+	for _, w := range watchlists {
+		if _, err := r.db.Exec(`
+			INSERT OR IGNORE INTO opportunity_watchlists (opportunity_id, watchlist_id)
+			VALUES (?, ?)
+		`, opportunityID, w.ID); err != nil {
+			return fmt.Errorf("failed to tag opportunity: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Retag re-evaluates every stored opportunity against a single watchlist
+func (r *WatchlistRepository) Retag(w *Watchlist) error {
+	rows, err := r.db.Query(`SELECT id, title, description, source FROM opportunities`)
+	if err != nil {
+		return fmt.Errorf("failed to query opportunities: %w", err)
+	}
+
+	var matched []int64
+	for rows.Next() {
+		var id int64
+		var opp Opportunity
+		var description sql.NullString
+		if err := rows.Scan(&id, &opp.Title, &description, &opp.SourceType); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan opportunity: %w", err)
+		}
+		opp.Description = description.String
+
+		if w.Matches(opp) {
+			matched = append(matched, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM opportunity_watchlists WHERE watchlist_id = ?`, w.ID); err != nil {
+		return fmt.Errorf("failed to clear watchlist tags: %w", err)
+	}
+
+	for _, id := range matched {
+		if _, err := tx.Exec(`
+			INSERT INTO opportunity_watchlists (opportunity_id, watchlist_id)
+			VALUES (?, ?)
+		`, id, w.ID); err != nil {
+			return fmt.Errorf("failed to tag opportunity: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func marshalWatchlist(w *Watchlist) (string, string, error) {
+	keywords, err := json.Marshal(w.KeywordsConfig)
+	if err != nil {
+		return "", "", err
+	}
+
+	sources, err := json.Marshal(w.Sources)
+	if err != nil {
+		return "", "", err
+	}
+
+	return string(keywords), string(sources), nil
+}
+
+func scanWatchlist(row interface{ Scan(...any) error }) (*Watchlist, error) {
+	var w Watchlist
+	var keywords, sources string
+
+	if err := row.Scan(&w.ID, &w.Name, &keywords, &sources, &w.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	json.Unmarshal([]byte(keywords), &w.KeywordsConfig)
+	json.Unmarshal([]byte(sources), &w.Sources)
+	w.KeywordsConfig.Normalize()
+	if w.Sources == nil {
+		w.Sources = []string{}
+	}
+
+	return &w, nil
+}
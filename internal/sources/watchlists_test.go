@@ -0,0 +1,104 @@
+package sources
+
+import (
+	"testing"
+)
+
+func TestWatchlist_Matches(t *testing.T) {
+	w := Watchlist{
+		Name: "invoicing",
+		KeywordsConfig: KeywordsConfig{
+			IncludeKeywords: []string{"invoice", "invoicing"},
+			ExcludeKeywords: []string{"crypto"},
+		},
+		Sources: []string{"hackernews"},
+	}
+
+	testCases := []struct {
+		name     string
+		opp      Opportunity
+		expected bool
+	}{
+		{"match", Opportunity{Title: "Ask HN: Best invoicing tool?", SourceType: "hackernews"}, true},
+		{"out of scope", Opportunity{Title: "Invoice generator", SourceType: "npm"}, false},
+		{"excluded", Opportunity{Title: "Crypto invoicing", SourceType: "hackernews"}, false},
+		{"no keyword", Opportunity{Title: "Show HN: A game", SourceType: "hackernews"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := w.Matches(tc.opp); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestWatchlist_MatchesBoostOnly(t *testing.T) {
+	w := Watchlist{Name: "billing", KeywordsConfig: KeywordsConfig{BoostKeywords: []string{"stripe"}}}
+
+	if !w.Matches(Opportunity{Title: "Stripe alternative for Europe", SourceType: "hackernews"}) {
+		t.Error("expected a boost keyword to match")
+	}
+	if w.Matches(Opportunity{Title: "Show HN: A game", SourceType: "hackernews"}) {
+		t.Error("expected no match without any of the boost keywords")
+	}
+
+	empty := Watchlist{Name: "empty"}
+	if empty.Matches(Opportunity{Title: "Anything", SourceType: "hackernews"}) {
+		t.Error("expected a watchlist without keywords to match nothing")
+	}
+}
+
+func TestWatchlist_NormalizeRejectsUnknownSource(t *testing.T) {
+	w := Watchlist{Name: "test", Sources: []string{"myspace"}}
+	if err := w.Normalize(); err == nil {
+		t.Error("expected error for unknown source type")
+	}
+}
+
+func TestManager_SaveOpportunityTagsWatchlists(t *testing.T) {
+	db := setupTestDB(t)
+	m := NewManager(db, 60)
+
+	if err := m.repo.Seed(); err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+	sources, _ := m.repo.GetAll()
+
+	existing := Opportunity{
+		Title:            "Plausible-style self-hosted analytics",
+		SourceType:       "github",
+		SourceURL:        "https://example.com/1",
+		SourceIDExternal: "existing",
+	}
+	if err := m.saveOpportunity(sources[0].ID, existing, nil); err != nil {
+		t.Fatalf("failed to save opportunity: %v", err)
+	}
+
+	// Creating a watchlist tags opportunities that were already stored
+	analytics := &Watchlist{
+		Name:           "self-hosted analytics",
+		KeywordsConfig: KeywordsConfig{IncludeKeywords: []string{"analytics"}},
+	}
+	if err := m.watchlists.Create(analytics); err != nil {
+		t.Fatalf("failed to create watchlist: %v", err)
+	}
+
+	// New opportunities are tagged as they are saved
+	fresh := Opportunity{
+		Title:            "Privacy-first analytics for indie sites",
+		SourceType:       "hackernews",
+		SourceURL:        "https://example.com/2",
+		SourceIDExternal: "fresh",
+	}
+	if err := m.saveOpportunity(sources[0].ID, fresh, m.loadRules()); err != nil {
+		t.Fatalf("failed to save opportunity: %v", err)
+	}
+
+	var count int
+	db.QueryRow("SELECT COUNT(*) FROM opportunity_watchlists WHERE watchlist_id = ?", analytics.ID).Scan(&count)
+	if count != 2 {
+		t.Errorf("expected 2 tagged opportunities, got %d", count)
+	}
+}
@@ -0,0 +1,99 @@
+package sources
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// WebhookType is the type of sources that receive opportunities instead of
+	// fetching them
+	WebhookType = "webhook"
+
+	// WebhookSignatureHeader carries the hex HMAC-SHA256 of the request body,
+	// prefixed with "sha256="
+	WebhookSignatureHeader = "X-Seer-Signature"
+
+	// webhookMaxItems bounds how many opportunities one delivery may carry
+	webhookMaxItems = 500
+)
+
+// ErrWebhookUnauthorized is returned for deliveries without a valid token or signature
+var ErrWebhookUnauthorized = errors.New("invalid webhook token or signature")
+
+// Webhook checks deliveries to a webhook source. The "token" config is a bearer
+// token sent in the Authorization header; the "secret" config is the key of the
+// signature in the X-Seer-Signature header. Either one authenticates a delivery.
+type Webhook struct {
+	token  string
+	secret string
+}
+
+// NewWebhook creates the checker of a webhook source's deliveries
+func NewWebhook(cfg SourceConfig) (*Webhook, error) {
+	w := &Webhook{
+		token:  cfg.Config["token"],
+		secret: cfg.Config["secret"],
+	}
+	if w.token == "" && w.secret == "" {
+		return nil, fmt.Errorf("webhook source requires a token or a secret in config")
+	}
+	return w, nil
+}
+
+// Verify checks that a delivery carries the source's token or a valid signature
+// of its body
+func (w *Webhook) Verify(header http.Header, body []byte) error {
+	if w.token != "" {
+		if token, ok := strings.CutPrefix(header.Get("Authorization"), "Bearer "); ok &&
+			subtle.ConstantTimeCompare([]byte(token), []byte(w.token)) == 1 {
+			return nil
+		}
+	}
+
+	if w.secret != "" {
+		if signature, ok := strings.CutPrefix(header.Get(WebhookSignatureHeader), "sha256="); ok {
+			got, err := hex.DecodeString(signature)
+			mac := hmac.New(sha256.New, []byte(w.secret))
+			mac.Write(body)
+			if err == nil && hmac.Equal(got, mac.Sum(nil)) {
+				return nil
+			}
+		}
+	}
+
+	return ErrWebhookUnauthorized
+}
+
+// DecodeWebhookPayload reads a delivery holding a single opportunity or an array
+// of them
+func DecodeWebhookPayload(body []byte) ([]Opportunity, error) {
+	body = bytes.TrimSpace(body)
+
+	var opportunities []Opportunity
+	if len(body) > 0 && body[0] == '[' {
+		if err := json.Unmarshal(body, &opportunities); err != nil {
+			return nil, fmt.Errorf("invalid opportunities: %w", err)
+		}
+	} else {
+		var opp Opportunity
+		if err := json.Unmarshal(body, &opp); err != nil {
+			return nil, fmt.Errorf("invalid opportunity: %w", err)
+		}
+		opportunities = []Opportunity{opp}
+	}
+
+	if len(opportunities) > webhookMaxItems {
+		return nil, fmt.Errorf("too many opportunities: at most %d per request", webhookMaxItems)
+	}
+
+	return opportunities, nil
+}
@@ -0,0 +1,133 @@
+package sources
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestWebhook_Verify(t *testing.T) {
+	if _, err := NewWebhook(SourceConfig{Config: map[string]string{}}); err == nil {
+		t.Error("expected error without a token or secret")
+	}
+
+	w, _ := NewWebhook(SourceConfig{Config: map[string]string{"token": "tok", "secret": "shh"}})
+	body := []byte(`{"title": "Hello"}`)
+
+	mac := hmac.New(sha256.New, []byte("shh"))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   error
+	}{
+		{"token", http.Header{"Authorization": {"Bearer tok"}}, nil},
+		{"signature", http.Header{WebhookSignatureHeader: {signature}}, nil},
+		{"wrong token", http.Header{"Authorization": {"Bearer nope"}}, ErrWebhookUnauthorized},
+		{"wrong signature", http.Header{WebhookSignatureHeader: {"sha256=00ff"}}, ErrWebhookUnauthorized},
+		{"nothing", http.Header{}, ErrWebhookUnauthorized},
+	}
+
+	for _, tt := range tests {
+		if err := w.Verify(tt.header, body); err != tt.want {
+			t.Errorf("%s: Verify() = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	// The signature covers the exact body
+	if err := w.Verify(http.Header{WebhookSignatureHeader: {signature}}, []byte(`{"title": "Changed"}`)); err == nil {
+		t.Error("expected signature of another body to be rejected")
+	}
+}
+
+func TestDecodeWebhookPayload(t *testing.T) {
+	single, err := DecodeWebhookPayload([]byte(` {"title": "One", "source_url": "https://example.com/1"}`))
+	if err != nil || len(single) != 1 || single[0].Title != "One" {
+		t.Errorf("unexpected single payload %v, %v", single, err)
+	}
+
+	batch, err := DecodeWebhookPayload([]byte(`[{"title": "One"}, {"title": "Two"}]`))
+	if err != nil || len(batch) != 2 {
+		t.Errorf("unexpected batch payload %v, %v", batch, err)
+	}
+
+	if _, err := DecodeWebhookPayload([]byte(`not json`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestManager_Ingest(t *testing.T) {
+	db := setupTestDB(t)
+	m := NewManager(db, 60)
+
+	record := &SourceRecord{Type: WebhookType, Name: "Zapier", Enabled: true, Config: `{"token": "tok"}`}
+	if err := m.repo.Create(record); err != nil {
+		t.Fatalf("failed to create source: %v", err)
+	}
+	if err := m.keywords.SaveKeywords(&KeywordsConfig{ExcludeKeywords: []string{"crypto"}}); err != nil {
+		t.Fatalf("failed to save keywords: %v", err)
+	}
+
+	run, err := m.Ingest(*record, []Opportunity{
+		{Title: "Looking for a self-hosted CRM", SourceURL: "https://example.com/1", SourceType: "reddit", Metadata: map[string]any{"points": 80}},
+		{Title: "Crypto exchange launch", SourceIDExternal: "2"},
+		{Description: "No title"},
+	})
+	if err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+	if run.Fetched != 2 || run.Kept != 1 || len(run.Warnings) != 1 || run.Warnings[0] != "item 3: missing title" {
+		t.Errorf("unexpected run %+v", run)
+	}
+
+	var source, externalID string
+	var score int
+	if err := db.QueryRow(`SELECT source, source_id_external, score FROM opportunities WHERE source_id = ?`, record.ID).Scan(&source, &externalID, &score); err != nil {
+		t.Fatalf("expected stored opportunity: %v", err)
+	}
+	if source != WebhookType || externalID != fmt.Sprintf("%d:https://example.com/1", record.ID) || score == 0 {
+		t.Errorf("unexpected stored opportunity %s %s %d", source, externalID, score)
+	}
+
+	runs, _ := m.repo.GetRuns(record.ID, 10)
+	if len(runs) != 1 {
+		t.Errorf("expected the delivery to be recorded, got %d runs", len(runs))
+	}
+}
+
+func TestManager_IngestKeepsSourcesApart(t *testing.T) {
+	db := setupTestDB(t)
+	m := NewManager(db, 60)
+
+	var records []*SourceRecord
+	for _, name := range []string{"n8n leads", "n8n support"} {
+		record := &SourceRecord{Type: WebhookType, Name: name, Enabled: true, Config: `{"token": "tok"}`}
+		if err := m.repo.Create(record); err != nil {
+			t.Fatalf("failed to create source: %v", err)
+		}
+		records = append(records, record)
+	}
+
+	// Both flows number their items from 1
+	for _, record := range records {
+		if _, err := m.Ingest(*record, []Opportunity{
+			{Title: "Item one from " + record.Name, SourceIDExternal: "1"},
+			{Title: "Item two from " + record.Name, SourceIDExternal: "2"},
+		}); err != nil {
+			t.Fatalf("Ingest() error = %v", err)
+		}
+	}
+
+	for _, record := range records {
+		var count int
+		db.QueryRow(`SELECT COUNT(*) FROM opportunities WHERE source_id = ? AND title LIKE ?`, record.ID, "%"+record.Name).Scan(&count)
+		if count != 2 {
+			t.Errorf("expected 2 opportunities of %s, got %d", record.Name, count)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package topics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MaxTopics is the number of topics kept per opportunity
+	MaxTopics = 5
+
+	// maxPhraseLength is the longest keyphrase, in words
+	maxPhraseLength = 3
+
+	// minDocFreq is how many documents must share a phrase before it counts as a topic,
+	// enforced once the corpus has minCorpusSize documents
+	minDocFreq    = 2
+	minCorpusSize = 20
+
+	// maxDocRatio drops phrases so common they describe everything
+	maxDocRatio = 0.3
+
+	// titleWeight makes title words count more than description words
+	titleWeight = 2.0
+)
+
+// Topic is a keyphrase extracted from an opportunity
+type Topic struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+}
+
+// Extractor ranks keyphrases by TF-IDF against a corpus of opportunities
+type Extractor struct {
+	docs int
+	df   map[string]int
+}
+
+// NewExtractor creates an extractor with an empty corpus
+func NewExtractor() *Extractor {
+	return &Extractor{df: make(map[string]int)}
+}
+
+// Add adds a document to the corpus
+func (e *Extractor) Add(title, description string) {
+	e.docs++
+	for phrase := range Phrases(title, description) {
+		e.df[phrase]++
+	}
+}
+
+// Docs returns the number of documents in the corpus
+func (e *Extractor) Docs() int {
+	return e.docs
+}
+
+// Extract returns the highest-weighted topics of a document, best first
+func (e *Extractor) Extract(title, description string) []Topic {
+	var candidates []candidate
+	for phrase, tf := range Phrases(title, description) {
+		df := e.df[phrase]
+		if e.docs >= minCorpusSize {
+			if df < minDocFreq || float64(df)/float64(e.docs) > maxDocRatio {
+				continue
+			}
+		}
+
+		// Smoothed IDF; longer phrases are more specific, so they get a small lift
+		idf := math.Log(float64(e.docs+1)/float64(df+1)) + 1
+		words := strings.Count(phrase, " ") + 1
+		weight := tf * idf * (1 + 0.5*float64(words-1))
+
+		candidates = append(candidates, candidate{
+			Topic: Topic{Name: phrase, Weight: math.Round(weight*1000) / 1000},
+			df:    df,
+		})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Weight != candidates[j].Weight {
+			return candidates[i].Weight > candidates[j].Weight
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+
+	return dropRedundant(candidates, MaxTopics)
+}
+
+// candidate is a scored phrase with its document frequency
+type candidate struct {
+	Topic
+	df int
+}
+
+// dropRedundant keeps up to n topics, skipping phrases that overlap a better-ranked
+// one and occur in the same documents. "mcp servers" survives next to
+// "hosting mcp servers" when it is more widespread, but "mcp" alone does not when it
+// only ever appears as part of "mcp servers".
+func dropRedundant(candidates []candidate, n int) []Topic {
+	kept := []candidate{}
+	for _, c := range candidates {
+		if len(kept) == n {
+			break
+		}
+
+		redundant := false
+		for _, k := range kept {
+			overlaps := containsPhrase(k.Name, c.Name) || containsPhrase(c.Name, k.Name)
+			if overlaps && k.df == c.df {
+				redundant = true
+				break
+			}
+		}
+		if !redundant {
+			kept = append(kept, c)
+		}
+	}
+
+	topics := make([]Topic, len(kept))
+	for i, k := range kept {
+		topics[i] = k.Topic
+	}
+	return topics
+}
+
+// containsPhrase reports whether phrase appears as whole words inside outer
+func containsPhrase(outer, phrase string) bool {
+	return strings.Contains(" "+outer+" ", " "+phrase+" ")
+}
+
+// Phrases returns the candidate keyphrases of a document with their weighted term
+// frequencies. Phrases are runs of one to three words that never cross a stopword
+// or punctuation.
+func Phrases(title, description string) map[string]float64 {
+	phrases := make(map[string]float64)
+	addPhrases(phrases, title, titleWeight)
+	addPhrases(phrases, description, 1)
+	return phrases
+}
+
+func addPhrases(phrases map[string]float64, text string, weight float64) {
+	for _, run := range runs(text) {
+		for i := range run {
+			for n := 1; n <= maxPhraseLength && i+n <= len(run); n++ {
+				phrases[strings.Join(run[i:i+n], " ")] += weight
+			}
+		}
+	}
+}
+
+// runs splits text into runs of content words separated by stopwords and punctuation
+func runs(text string) [][]string {
+	// Every break becomes a standalone separator so phrases never span it
+	separated := strings.Map(func(r rune) rune {
+		if isBreak(r) {
+			return '|'
+		}
+		return unicode.ToLower(r)
+	}, text)
+	separated = strings.ReplaceAll(separated, "|", " | ")
+
+	var result [][]string
+	var current []string
+	flush := func() {
+		if len(current) > 0 {
+			result = append(result, current)
+			current = nil
+		}
+	}
+
+	for _, field := range strings.Fields(separated) {
+		word := strings.Trim(field, "-.'")
+		if field == "|" || !isContentWord(word) {
+			flush()
+			continue
+		}
+		current = append(current, word)
+
+		// A trailing dot ends a sentence, not a word like "node.js"
+		if strings.HasSuffix(field, ".") {
+			flush()
+		}
+	}
+	flush()
+
+	return result
+}
+
+// isBreak reports whether a rune splits phrases. Hyphens, dots, pluses, hashes and
+// apostrophes stay inside words so "local-first", "node.js", "c++" and "c#" survive.
+func isBreak(r rune) bool {
+	if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) {
+		return false
+	}
+	switch r {
+	case '-', '.', '+', '#', '\'':
+		return false
+	}
+	return true
+}
+
+// isContentWord reports whether a word can be part of a topic
+func isContentWord(word string) bool {
+	if len(word) < 2 || stopwords[word] {
+		return false
+	}
+
+	// Pure numbers and versions ("2024", "1.0") are not topics
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package topics
+
+// stopwords are common English words plus words that appear in almost every
+// launch or discussion title without saying what it is about
+var stopwords = toSet(
+	// English
+	"a", "about", "above", "after", "again", "against", "all", "also", "am", "an", "and",
+	"any", "are", "aren't", "as", "at", "be", "because", "been", "before", "being",
+	"below", "between", "both", "but", "by", "can", "can't", "cannot", "could",
+	"couldn't", "did", "didn't", "do", "does", "doesn't", "doing", "don't", "down",
+	"during", "each", "even", "ever", "every", "few", "for", "from", "further", "get",
+	"gets", "got", "had", "hadn't", "has", "hasn't", "have", "haven't", "having", "he",
+	"her", "here", "hers", "herself", "him", "himself", "his", "how", "i", "i'm",
+	"i've", "if", "in", "into", "is", "isn't", "it", "it's", "its", "itself", "just",
+	"let", "let's", "like", "made", "make", "makes", "many", "may", "me", "might",
+	"more", "most", "much", "must", "my", "myself", "need", "needs", "no", "nor", "not",
+	"now", "of", "off", "on", "once", "one", "only", "or", "other", "our", "ours",
+	"ourselves", "out", "over", "own", "really", "same", "she", "should", "shouldn't",
+	"so", "some", "still", "such", "than", "that", "that's", "the", "their", "theirs",
+	"them", "themselves", "then", "there", "there's", "these", "they", "they're",
+	"thing", "things", "this", "those", "through", "to", "too", "under", "until", "up",
+	"us", "use", "used", "using", "very", "via", "want", "was", "wasn't", "way", "we",
+	"we're", "we've", "well", "were", "weren't", "what", "what's", "when", "where",
+	"which", "while", "who", "whom", "why", "will", "with", "without", "won't", "would",
+	"wouldn't", "yet", "you", "you're", "you've", "your", "yours", "yourself",
+
+	// Launch and discussion boilerplate
+	"hn", "show", "ask", "tell", "launch", "launched", "launching", "introducing",
+	"announcing", "released", "release", "new", "now", "today", "free", "open",
+	"source", "anyone", "looking", "best", "better", "good", "great", "simple",
+	"easy", "fast", "built", "build", "building", "created", "create", "help", "app",
+	"tool", "tools", "project", "alternative", "feedback", "anything", "something",
+	"way", "ways", "know", "think", "first", "year", "years", "day", "days", "time",
+	"wish", "existed", "would", "pay", "problem", "solution", "people",
+)
+
+func toSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
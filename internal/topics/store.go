@@ -0,0 +1,247 @@
+package topics
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// corpusSize is how many recent opportunities document frequencies are computed over
+const corpusSize = 10000
+
+// Store persists opportunity topics and computes trends
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates a new topic store
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Extractor builds an extractor over the most recent opportunities
+func (s *Store) Extractor() (*Extractor, error) {
+	rows, err := s.db.Query(`
+		SELECT title, description FROM opportunities
+		ORDER BY id DESC
+		LIMIT ?
+	`, corpusSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query corpus: %w", err)
+	}
+	defer rows.Close()
+
+	e := NewExtractor()
+	for rows.Next() {
+		var title string
+		var description sql.NullString
+		if err := rows.Scan(&title, &description); err != nil {
+			return nil, fmt.Errorf("failed to scan corpus document: %w", err)
+		}
+		e.Add(title, description.String)
+	}
+
+	return e, rows.Err()
+}
+
+// Tag replaces the topics of an opportunity
+func (s *Store) Tag(opportunityID int64, topics []Topic) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM opportunity_topics WHERE opportunity_id = ?`, opportunityID); err != nil {
+		return fmt.Errorf("failed to clear topics: %w", err)
+	}
+
+	for _, t := range topics {
+		if _, err := tx.Exec(`
+			INSERT INTO opportunity_topics (opportunity_id, topic, weight)
+			VALUES (?, ?, ?)
+		`, opportunityID, t.Name, t.Weight); err != nil {
+			return fmt.Errorf("failed to save topic: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE opportunities SET topics_at = CURRENT_TIMESTAMP WHERE id = ?`, opportunityID); err != nil {
+		return fmt.Errorf("failed to mark opportunity tagged: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// Retag re-extracts topics for opportunities detected since a time and for any
+// that were never tagged. It returns the number of opportunities tagged.
+func (s *Store) Retag(e *Extractor, since time.Time) (int, error) {
+	rows, err := s.db.Query(`
+		SELECT id, title, description FROM opportunities
+		WHERE topics_at IS NULL OR datetime(detected_at) >= datetime(?)
+	`, since.UTC().Format(time.RFC3339))
+	if err != nil {
+		return 0, fmt.Errorf("failed to query opportunities: %w", err)
+	}
+
+	type document struct {
+		id                 int64
+		title, description string
+	}
+	var docs []document
+	for rows.Next() {
+		var d document
+		var description sql.NullString
+		if err := rows.Scan(&d.id, &d.title, &description); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan opportunity: %w", err)
+		}
+		d.description = description.String
+		docs = append(docs, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, d := range docs {
+		if err := s.Tag(d.id, e.Extract(d.title, d.description)); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(docs), nil
+}
+
+// Trend compares a topic's volume and score between two consecutive periods
+type Trend struct {
+	Topic         string  `json:"topic"`
+	Count         int     `json:"count"`
+	PreviousCount int     `json:"previous_count"`
+	VolumeChange  int     `json:"volume_change"`
+	Growth        float64 `json:"growth"`
+	AvgScore      float64 `json:"avg_score"`
+	PreviousScore float64 `json:"previous_avg_score"`
+	ScoreChange   float64 `json:"score_change"`
+}
+
+// TrendOptions configures a trend query
+type TrendOptions struct {
+	// Days is the length of each compared period
+	Days int
+	// MinCount is the minimum number of opportunities in the current period
+	MinCount int
+	// Sort orders by "volume" (default), "growth" or "score"
+	Sort   string
+	Source string
+	Limit  int
+}
+
+// Trends returns the topics gaining the most volume and score in the latest period
+// compared with the one before it
+func (s *Store) Trends(opts TrendOptions) ([]Trend, error) {
+	if opts.Days <= 0 {
+		opts.Days = 7
+	}
+	if opts.MinCount <= 0 {
+		opts.MinCount = 2
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 20
+	}
+
+	now := time.Now().UTC()
+	current := now.AddDate(0, 0, -opts.Days).Format(time.RFC3339)
+	previous := now.AddDate(0, 0, -2*opts.Days).Format(time.RFC3339)
+
+	query := `
+		SELECT t.topic,
+			SUM(CASE WHEN datetime(o.detected_at) >= datetime(?) THEN 1 ELSE 0 END),
+			SUM(CASE WHEN datetime(o.detected_at) < datetime(?) THEN 1 ELSE 0 END),
+			COALESCE(AVG(CASE WHEN datetime(o.detected_at) >= datetime(?) THEN o.score END), 0),
+			COALESCE(AVG(CASE WHEN datetime(o.detected_at) < datetime(?) THEN o.score END), 0)
+		FROM opportunity_topics t
+		JOIN opportunities o ON o.id = t.opportunity_id
+		WHERE datetime(o.detected_at) >= datetime(?)
+	`
+	args := []any{current, current, current, current, previous}
+
+	if opts.Source != "" {
+		query += " AND o.source = ?"
+		args = append(args, opts.Source)
+	}
+
+	query += `
+		GROUP BY t.topic
+		HAVING SUM(CASE WHEN datetime(o.detected_at) >= datetime(?) THEN 1 ELSE 0 END) >= ?
+	`
+	args = append(args, current, opts.MinCount)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trends: %w", err)
+	}
+	defer rows.Close()
+
+	trends := []Trend{}
+	for rows.Next() {
+		var t Trend
+		if err := rows.Scan(&t.Topic, &t.Count, &t.PreviousCount, &t.AvgScore, &t.PreviousScore); err != nil {
+			return nil, fmt.Errorf("failed to scan trend: %w", err)
+		}
+
+		t.VolumeChange = t.Count - t.PreviousCount
+		t.Growth = float64(t.VolumeChange) / float64(max(t.PreviousCount, 1))
+		if t.PreviousCount > 0 {
+			t.ScoreChange = t.AvgScore - t.PreviousScore
+		}
+		t.AvgScore = round(t.AvgScore)
+		t.PreviousScore = round(t.PreviousScore)
+		t.ScoreChange = round(t.ScoreChange)
+		t.Growth = round(t.Growth)
+
+		trends = append(trends, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sortTrends(trends, opts.Sort)
+	if len(trends) > opts.Limit {
+		trends = trends[:opts.Limit]
+	}
+
+	return trends, nil
+}
+
+// sortTrends orders trends by the chosen measure, breaking ties by the others
+func sortTrends(trends []Trend, by string) {
+	less := func(a, b Trend) bool {
+		switch by {
+		case "score":
+			if a.ScoreChange != b.ScoreChange {
+				return a.ScoreChange > b.ScoreChange
+			}
+		case "growth":
+			if a.Growth != b.Growth {
+				return a.Growth > b.Growth
+			}
+		}
+		if a.VolumeChange != b.VolumeChange {
+			return a.VolumeChange > b.VolumeChange
+		}
+		if a.ScoreChange != b.ScoreChange {
+			return a.ScoreChange > b.ScoreChange
+		}
+		return a.Topic < b.Topic
+	}
+
+	sort.Slice(trends, func(i, j int) bool {
+		return less(trends[i], trends[j])
+	})
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
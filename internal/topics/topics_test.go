@@ -0,0 +1,133 @@
+package topics
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mx-seer/seer/internal/db"
+)
+
+func TestPhrases(t *testing.T) {
+	phrases := Phrases("Show HN: A local-first notes app for MCP servers", "Works with Node.js. Rust too")
+
+	for _, want := range []string{"local-first notes", "mcp servers", "node.js", "rust", "notes"} {
+		if _, ok := phrases[want]; !ok {
+			t.Errorf("expected phrase %q in %v", want, phrases)
+		}
+	}
+
+	for _, unwanted := range []string{"show", "hn", "a", "node.js rust", "app", "servers works"} {
+		if _, ok := phrases[unwanted]; ok {
+			t.Errorf("unexpected phrase %q", unwanted)
+		}
+	}
+
+	if phrases["mcp"] != titleWeight {
+		t.Errorf("expected title words weighted %v, got %v", titleWeight, phrases["mcp"])
+	}
+}
+
+func TestExtract(t *testing.T) {
+	e := NewExtractor()
+	docs := []string{
+		"Building MCP servers in Go",
+		"A registry for MCP servers",
+		"Local-first sync engine",
+		"Why local-first software matters",
+	}
+	for _, d := range docs {
+		e.Add(d, "")
+	}
+	for i := 0; i < 20; i++ {
+		e.Add("Weekly newsletter", "")
+	}
+
+	got := e.Extract("Testing MCP servers locally", "")
+	if len(got) == 0 || got[0].Name != "mcp servers" {
+		t.Fatalf("expected mcp servers as top topic, got %v", got)
+	}
+	for _, topic := range got {
+		if topic.Name == "mcp" || topic.Name == "servers" {
+			t.Errorf("expected words of a better-ranked phrase to be dropped, got %v", got)
+		}
+		if topic.Name == "testing" || topic.Name == "locally" {
+			t.Errorf("expected phrases seen in a single document to be dropped, got %v", got)
+		}
+	}
+
+	if got := e.Extract("Weekly newsletter", ""); len(got) != 0 {
+		t.Errorf("expected phrases common to most documents to be dropped, got %v", got)
+	}
+}
+
+func TestTrends(t *testing.T) {
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	defer database.Close()
+
+	store := NewStore(database.DB)
+	now := time.Now().UTC()
+
+	insert := func(title string, score int, age time.Duration) {
+		t.Helper()
+		if _, err := database.Exec(`
+			INSERT INTO opportunities (title, source, source_url, source_id_external, score, detected_at)
+			VALUES (?, 'hackernews', '', ?, ?, ?)
+		`, title, title, score, now.Add(-age).Format(time.RFC3339)); err != nil {
+			t.Fatalf("failed to insert opportunity: %v", err)
+		}
+	}
+
+	day := 24 * time.Hour
+	insert("MCP servers for databases", 80, day)
+	insert("Hosting MCP servers", 70, 2*day)
+	insert("Testing MCP servers", 60, 3*day)
+	insert("Fast MCP servers", 40, 9*day)
+	insert("Kanban boards for teams", 30, day)
+	insert("Kanban boards that sync", 30, 2*day)
+	insert("Kanban boards reimagined", 50, 8*day)
+	insert("Kanban boards on paper", 50, 9*day)
+	insert("Kanban boards everywhere", 50, 10*day)
+
+	extractor, err := store.Extractor()
+	if err != nil {
+		t.Fatalf("Extractor() error = %v", err)
+	}
+	tagged, err := store.Retag(extractor, now.Add(-30*day))
+	if err != nil {
+		t.Fatalf("Retag() error = %v", err)
+	}
+	if tagged != 9 {
+		t.Errorf("expected 9 opportunities tagged, got %d", tagged)
+	}
+
+	trends, err := store.Trends(TrendOptions{})
+	if err != nil {
+		t.Fatalf("Trends() error = %v", err)
+	}
+	if len(trends) != 2 {
+		t.Fatalf("expected 2 trends, got %+v", trends)
+	}
+
+	mcp := trends[0]
+	if mcp.Topic != "mcp servers" || mcp.Count != 3 || mcp.PreviousCount != 1 || mcp.VolumeChange != 2 || mcp.ScoreChange != 30 {
+		t.Errorf("unexpected top trend: %+v", mcp)
+	}
+
+	kanban := trends[1]
+	if kanban.Topic != "kanban boards" || kanban.VolumeChange != -1 || kanban.ScoreChange != -20 {
+		t.Errorf("unexpected second trend: %+v", kanban)
+	}
+
+	// Untagged opportunities outside the window are still tagged once
+	tagged, err = store.Retag(extractor, now)
+	if err != nil {
+		t.Fatalf("Retag() error = %v", err)
+	}
+	if tagged != 0 {
+		t.Errorf("expected no opportunities re-tagged, got %d", tagged)
+	}
+}